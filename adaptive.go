@@ -0,0 +1,312 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Adaptive session defaults, used when options are left at zero
+const (
+	defaultAdaptiveMaxQuestions        = 20
+	defaultAdaptiveMinQuestions        = 5
+	defaultAdaptiveTargetStandardError = 0.3
+	adaptiveConfidenceZ                = 1.96 // 95% confidence interval
+)
+
+// itemParametersForQuestions returns IRT parameters for each question, using calibrated
+// values where available and otherwise placing the 1-5 difficulty on the ability scale
+func (a *App) itemParametersForQuestions(questions []Question) (map[string]ItemCalibration, error) {
+	calibrations, err := a.db.GetItemCalibrations()
+	if err != nil {
+		return nil, err
+	}
+	calibrated := make(map[string]ItemCalibration, len(calibrations))
+	for _, item := range calibrations {
+		calibrated[item.QuestionID] = item
+	}
+
+	params := make(map[string]ItemCalibration, len(questions))
+	for _, q := range questions {
+		if item, ok := calibrated[q.ID]; ok {
+			params[q.ID] = item
+			continue
+		}
+		difficulty := 0.0
+		if q.Difficulty != nil {
+			difficulty = float64(*q.Difficulty - 3)
+		}
+		params[q.ID] = ItemCalibration{QuestionID: q.ID, Model: CalibrationModel1PL, Difficulty: difficulty, Discrimination: 1}
+	}
+	return params, nil
+}
+
+// itemInformation returns the Fisher information of an item at an ability
+func itemInformation(theta float64, item ItemCalibration) float64 {
+	prob := irtProbability(theta, item.Difficulty, item.Discrimination)
+	return item.Discrimination * item.Discrimination * prob * (1 - prob)
+}
+
+// isAnswerCorrect compares a selected answer with a question's key, ignoring order
+func isAnswerCorrect(q Question, userAnswer []string) bool {
+	key := parseQuestionAnswer(q.Answer)
+	if len(key) == 0 || len(key) != len(userAnswer) {
+		return false
+	}
+	expected := append([]string{}, key...)
+	given := append([]string{}, userAnswer...)
+	sort.Strings(expected)
+	sort.Strings(given)
+	for i := range expected {
+		if expected[i] != given[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// gradeAnswer checks an answer against a question. Free responses to template variants
+// are graded by value against their template.
+func (a *App) gradeAnswer(q Question, userAnswer []string) bool {
+	if templateID, ok := templateIDForInstance(q.ID); ok {
+		if template, err := a.db.GetQuestionTemplate(templateID); err == nil {
+			return isTemplateAnswerCorrect(*template, q, userAnswer)
+		}
+	}
+	return isAnswerCorrect(q, userAnswer)
+}
+
+// normalizeAdaptiveOptions fills in defaults for unset options
+func normalizeAdaptiveOptions(options AdaptiveSessionOptions) AdaptiveSessionOptions {
+	if options.MaxQuestions <= 0 {
+		options.MaxQuestions = defaultAdaptiveMaxQuestions
+	}
+	if options.MinQuestions <= 0 {
+		options.MinQuestions = defaultAdaptiveMinQuestions
+	}
+	if options.MinQuestions > options.MaxQuestions {
+		options.MinQuestions = options.MaxQuestions
+	}
+	if options.TargetStandardError <= 0 {
+		options.TargetStandardError = defaultAdaptiveTargetStandardError
+	}
+	return options
+}
+
+// Adaptive session database methods
+
+// SaveAdaptiveSession stores the state of an adaptive session
+func (d *Database) SaveAdaptiveSession(state *AdaptiveSessionState, at time.Time) error {
+	stored := *state
+	stored.NextQuestion = nil
+	stateJSON, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	now := at.Format(time.RFC3339)
+	_, err = d.db.Exec(`INSERT INTO adaptive_sessions (id, status, state, created_at, updated_at) VALUES (?, ?, ?, ?, ?)
+			  ON CONFLICT(id) DO UPDATE SET status = excluded.status, state = excluded.state, updated_at = excluded.updated_at`,
+		state.SessionID, state.Status, string(stateJSON), now, now)
+	return err
+}
+
+// GetAdaptiveSession loads the state of an adaptive session
+func (d *Database) GetAdaptiveSession(sessionID string) (*AdaptiveSessionState, error) {
+	var stateJSON string
+	err := d.db.QueryRow(`SELECT state FROM adaptive_sessions WHERE id = ?`, sessionID).Scan(&stateJSON)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("adaptive session %s not found", sessionID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state AdaptiveSessionState
+	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
+		return nil, fmt.Errorf("failed to parse adaptive session: %v", err)
+	}
+	return &state, nil
+}
+
+// Adaptive session methods
+
+// StartAdaptiveSession starts a computerized adaptive test over the questions of a group,
+// or the whole bank when groupID is empty, and returns the first question
+func (a *App) StartAdaptiveSession(groupID string, options AdaptiveSessionOptions) (*AdaptiveSessionState, error) {
+	state := &AdaptiveSessionState{
+		SessionID:     fmt.Sprintf("session_%d_%d", time.Now().UnixNano(), rand.Int63()),
+		GroupID:       groupID,
+		Status:        AdaptiveStatusActive,
+		Options:       normalizeAdaptiveOptions(options),
+		StandardError: calibrationAbilityPrior,
+		Records:       []QuestionRecord{},
+		StartedAt:     a.now().Format(time.RFC3339),
+	}
+
+	if err := a.advanceAdaptiveSession(state); err != nil {
+		return nil, err
+	}
+	if state.Status == AdaptiveStatusFinished {
+		return nil, fmt.Errorf("no questions available for an adaptive session")
+	}
+	if err := a.withoutJournal(func() error { return a.db.SaveAdaptiveSession(state, a.now()) }); err != nil {
+		return nil, fmt.Errorf("failed to save adaptive session: %v", err)
+	}
+	return state, nil
+}
+
+// SubmitAnswerAndGetNext grades the answer to the current question of an adaptive
+// session, updates the ability estimate and returns the next question, or the final
+// result once a stopping rule is met
+func (a *App) SubmitAnswerAndGetNext(sessionID, questionID string, userAnswer []string, timeSpent int) (*AdaptiveSessionState, error) {
+	state, err := a.db.GetAdaptiveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if state.Status != AdaptiveStatusActive {
+		return nil, fmt.Errorf("adaptive session %s has already finished", sessionID)
+	}
+	if questionID != state.NextQuestionID {
+		return nil, fmt.Errorf("question %s is not the current question of the session", questionID)
+	}
+
+	question, err := a.db.GetQuestionByID(questionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question: %v", err)
+	}
+	record := QuestionRecord{
+		QuestionID: questionID,
+		UserAnswer: userAnswer,
+		IsCorrect:  a.gradeAnswer(*question, userAnswer),
+		TimeSpent:  timeSpent,
+	}
+	state.Records = append(state.Records, record)
+	state.Answered++
+	if record.IsCorrect {
+		state.CorrectCount++
+	}
+
+	if err := a.advanceAdaptiveSession(state); err != nil {
+		return nil, err
+	}
+	if state.Status == AdaptiveStatusFinished {
+		if err := a.finishAdaptiveSession(state); err != nil {
+			return nil, err
+		}
+	}
+	if err := a.withoutJournal(func() error { return a.db.SaveAdaptiveSession(state, a.now()) }); err != nil {
+		return nil, fmt.Errorf("failed to save adaptive session: %v", err)
+	}
+	return state, nil
+}
+
+// advanceAdaptiveSession re-estimates the ability and either picks the most informative
+// unanswered question or marks the session finished
+func (a *App) advanceAdaptiveSession(state *AdaptiveSessionState) error {
+	questions, err := a.practiceQuestions(state.GroupID)
+	if err != nil {
+		return fmt.Errorf("failed to get questions: %v", err)
+	}
+	params, err := a.itemParametersForQuestions(questions)
+	if err != nil {
+		return fmt.Errorf("failed to load item parameters: %v", err)
+	}
+
+	answered := make(map[string]bool, len(state.Records))
+	attempts := make([]questionAttempt, 0, len(state.Records))
+	for _, record := range state.Records {
+		answered[record.QuestionID] = true
+		attempts = append(attempts, questionAttempt{QuestionID: record.QuestionID, IsCorrect: record.IsCorrect})
+	}
+	if len(attempts) > 0 {
+		state.Ability, state.StandardError = estimateAbility(attempts, params)
+	}
+	state.ConfidenceLow = state.Ability - adaptiveConfidenceZ*state.StandardError
+	state.ConfidenceHigh = state.Ability + adaptiveConfidenceZ*state.StandardError
+
+	state.NextQuestionID = ""
+	state.NextQuestion = nil
+	var next *Question
+	switch {
+	case state.Answered >= state.Options.MaxQuestions:
+		state.StopReason = AdaptiveStopMaxQuestions
+	case state.Answered >= state.Options.MinQuestions && state.StandardError <= state.Options.TargetStandardError:
+		state.StopReason = AdaptiveStopPrecision
+	default:
+		// Questions keep their bank order on ties, so selection is deterministic
+		bestInformation := -1.0
+		for i := range questions {
+			if answered[questions[i].ID] {
+				continue
+			}
+			information := itemInformation(state.Ability, params[questions[i].ID])
+			if information > bestInformation+1e-12 {
+				bestInformation = information
+				next = &questions[i]
+			}
+		}
+		if next == nil {
+			state.StopReason = AdaptiveStopBankExhausted
+		}
+	}
+
+	if next != nil {
+		// Served without its answer, which is graded on submit
+		nextQuestion := sanitizeQuestion(*next)
+		state.NextQuestionID = next.ID
+		state.NextQuestion = &nextQuestion
+		return nil
+	}
+	state.Status = AdaptiveStatusFinished
+	finishedAt := a.now().Format(time.RFC3339)
+	state.FinishedAt = &finishedAt
+	return nil
+}
+
+// finishAdaptiveSession stores a finished adaptive session as a practice session so it
+// counts towards statistics, wrong questions and calibration
+func (a *App) finishAdaptiveSession(state *AdaptiveSessionState) error {
+	duration := 0
+	for _, record := range state.Records {
+		duration += record.TimeSpent
+	}
+
+	session := &PracticeSession{
+		ID:             state.SessionID,
+		GroupID:        state.GroupID,
+		Mode:           PracticeModeAdaptive,
+		StartTime:      state.StartedAt,
+		EndTime:        state.FinishedAt,
+		Duration:       duration,
+		TotalQuestions: state.Answered,
+		CorrectCount:   state.CorrectCount,
+		CreatedAt:      a.now().Format(time.RFC3339),
+	}
+	if err := a.saveCompletedSession(session, state.Records); err != nil {
+		return err
+	}
+
+	log.Printf("Adaptive session %s finished (%s): ability %.2f ± %.2f",
+		state.SessionID, state.StopReason, state.Ability, adaptiveConfidenceZ*state.StandardError)
+	return nil
+}
+
+// GetAdaptiveSession returns the current state of an adaptive session
+func (a *App) GetAdaptiveSession(sessionID string) (*AdaptiveSessionState, error) {
+	state, err := a.db.GetAdaptiveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if state.NextQuestionID != "" {
+		question, err := a.db.GetQuestionByID(state.NextQuestionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get question: %v", err)
+		}
+		nextQuestion := sanitizeQuestion(*question)
+		state.NextQuestion = &nextQuestion
+	}
+	return state, nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestAdaptiveSession tests item selection, ability updates and the stopping rules
+func TestAdaptiveSession(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	var data []map[string]interface{}
+	for d := 1; d <= 5; d++ {
+		for i := 0; i < 2; i++ {
+			data = append(data, map[string]interface{}{
+				"question":   fmt.Sprintf("D%d-%d", d, i),
+				"options":    []QuestionOption{{ID: "a", Text: "A"}, {ID: "b", Text: "B"}},
+				"answer":     []string{"a"},
+				"difficulty": float64(d),
+			})
+		}
+	}
+	app.ImportQuestions(data, "")
+
+	state, err := app.StartAdaptiveSession("", AdaptiveSessionOptions{MaxQuestions: 4, TargetStandardError: 0.01})
+	if err != nil {
+		t.Fatalf("Failed to start adaptive session: %v", err)
+	}
+	if state.NextQuestion == nil || *state.NextQuestion.Difficulty != 3 {
+		t.Fatalf("Expected a medium question first, got %+v", state.NextQuestion)
+	}
+
+	if _, err := app.SubmitAnswerAndGetNext(state.SessionID, "unknown", []string{"a"}, 5); err == nil {
+		t.Error("Expected answering a question that was not asked to fail")
+	}
+
+	// Correct answers raise the ability estimate and the difficulty of the next question
+	previousAbility := state.Ability
+	previousDifficulty := *state.NextQuestion.Difficulty
+	for state.Status == AdaptiveStatusActive {
+		state, err = app.SubmitAnswerAndGetNext(state.SessionID, state.NextQuestionID, []string{"a"}, 5)
+		if err != nil {
+			t.Fatalf("Failed to submit answer: %v", err)
+		}
+		if state.Ability <= previousAbility {
+			t.Errorf("Expected ability to increase after a correct answer, got %.2f after %.2f", state.Ability, previousAbility)
+		}
+		previousAbility = state.Ability
+		if state.NextQuestion != nil {
+			if *state.NextQuestion.Difficulty < previousDifficulty {
+				t.Errorf("Expected harder questions, got %d after %d", *state.NextQuestion.Difficulty, previousDifficulty)
+			}
+			previousDifficulty = *state.NextQuestion.Difficulty
+		}
+	}
+	app.waitForCalibration()
+
+	if state.Answered != 4 || state.StopReason != AdaptiveStopMaxQuestions || state.CorrectCount != 4 {
+		t.Errorf("Unexpected final state: %+v", state)
+	}
+	if state.ConfidenceLow >= state.Ability || state.ConfidenceHigh <= state.Ability {
+		t.Errorf("Expected ability inside its confidence interval, got %.2f [%.2f, %.2f]",
+			state.Ability, state.ConfidenceLow, state.ConfidenceHigh)
+	}
+
+	sessions, _ := db.GetPracticeSessions()
+	if len(sessions) != 1 || sessions[0].Mode != PracticeModeAdaptive || sessions[0].TotalQuestions != 4 {
+		t.Errorf("Expected the finished session to be saved, got %+v", sessions)
+	}
+	if _, err := app.SubmitAnswerAndGetNext(state.SessionID, "", []string{"a"}, 5); err == nil {
+		t.Error("Expected answering a finished session to fail")
+	}
+}
+
+// TestAdaptiveSessionPrecisionStop tests stopping once the ability is precise enough
+func TestAdaptiveSessionPrecisionStop(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	var data []map[string]interface{}
+	for i := 0; i < 30; i++ {
+		data = append(data, map[string]interface{}{
+			"question": fmt.Sprintf("Q%d", i), "options": []string{"A", "B"}, "answer": []string{"a"},
+		})
+	}
+	app.ImportQuestions(data, "")
+
+	state, err := app.StartAdaptiveSession("", AdaptiveSessionOptions{MaxQuestions: 30, MinQuestions: 2, TargetStandardError: 0.6})
+	if err != nil {
+		t.Fatalf("Failed to start adaptive session: %v", err)
+	}
+	answer := []string{"a"}
+	for state.Status == AdaptiveStatusActive {
+		state, err = app.SubmitAnswerAndGetNext(state.SessionID, state.NextQuestionID, answer, 5)
+		if err != nil {
+			t.Fatalf("Failed to submit answer: %v", err)
+		}
+		// Alternate answers so the estimate stays near the middle of the scale
+		if answer[0] == "a" {
+			answer = []string{"b"}
+		} else {
+			answer = []string{"a"}
+		}
+	}
+	app.waitForCalibration()
+
+	if state.StopReason != AdaptiveStopPrecision || state.StandardError > 0.6 || state.Answered >= 30 {
+		t.Errorf("Expected precision stop before the bank ran out, got %+v", state)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -26,11 +27,42 @@ func getKeys(m map[string]interface{}) []string {
 type App struct {
 	ctx context.Context
 	db  *Database
+
+	// journalMu is held for the whole of a journaled operation, so operations run one at
+	// a time and background writers cannot slip changes between their snapshots
+	journalMu sync.Mutex
+
+	// Background calibration job state
+	calibrationMu      sync.Mutex
+	calibrationWG      sync.WaitGroup
+	calibrationRunning bool
+	calibrationPending bool
+	calibrationModel   string
+	calibrationDelay   time.Duration // Wait before a background run so bursts of requests share it
+
+	// calibrationRunMu lets only one calibration run at a time
+	calibrationRunMu sync.Mutex
+
+	// timedExamMu lets only one change to timed exams run at a time, so concurrent
+	// answers and the deadline cannot overwrite each other or finish an exam twice
+	timedExamMu sync.Mutex
+
+	// clock is the time sessions are stamped with and schedules, goals and analytics are
+	// computed against. Tests replace it; nil means the system clock.
+	clock func() time.Time
 }
 
 // NewApp creates a new App application struct
 func NewApp() *App {
-	return &App{}
+	return &App{calibrationDelay: calibrationDebounce}
+}
+
+// now returns the current time from the app clock
+func (a *App) now() time.Time {
+	if a.clock != nil {
+		return a.clock()
+	}
+	return time.Now()
 }
 
 // startup is called when the app starts. The context is saved
@@ -48,6 +80,7 @@ func (a *App) startup(ctx context.Context) {
 
 // shutdown is called when the app is closing
 func (a *App) shutdown(ctx context.Context) {
+	a.waitForCalibration()
 	if a.db != nil {
 		a.db.Close()
 	}
@@ -62,6 +95,20 @@ func (a *App) Greet(name string) string {
 
 // ImportQuestions imports questions from JSON data
 func (a *App) ImportQuestions(data []map[string]interface{}, groupID string) ImportResult {
+	var result ImportResult
+	label := fmt.Sprintf("Import %d questions", len(data))
+	err := a.journaled(label, questionBankJournalScopes(), func() error {
+		result = a.importQuestions(data, groupID)
+		return nil
+	})
+	if err != nil {
+		return ImportResult{Success: false, Errors: []string{err.Error()}}
+	}
+	return result
+}
+
+// importQuestions imports questions from JSON data without journaling
+func (a *App) importQuestions(data []map[string]interface{}, groupID string) ImportResult {
 	result := ImportResult{
 		Success:    true,
 		Imported:   0,
@@ -119,8 +166,8 @@ func (a *App) ImportQuestions(data []map[string]interface{}, groupID string) Imp
 		q := &Question{
 			ID:          fmt.Sprintf("q_%d_%d_%d", time.Now().UnixNano(), rand.Int63(), i),
 			Question:    question,
-			CreatedAt:   time.Now().Format(time.RFC3339),
-			UpdatedAt:   time.Now().Format(time.RFC3339),
+			CreatedAt:   a.now().Format(time.RFC3339),
+			UpdatedAt:   a.now().Format(time.RFC3339),
 		}
 
 		// Set options
@@ -172,6 +219,10 @@ func (a *App) ImportQuestions(data []map[string]interface{}, groupID string) Imp
 			continue
 		}
 
+		if err := a.recordQuestionRevision(q.ID, RevisionActorImport, "Imported"); err != nil {
+			log.Printf("Warning: Failed to record revision for question %s: %v", q.ID, err)
+		}
+
 		// Handle group assignment
 		var targetGroupID string
 		
@@ -197,8 +248,8 @@ func (a *App) ImportQuestions(data []map[string]interface{}, groupID string) Imp
 						Description: fmt.Sprintf("Auto-created group: %s", groupName),
 						Color:       "#1890ff",
 						Icon:        "folder",
-						CreatedAt:   time.Now().Format(time.RFC3339),
-						UpdatedAt:   time.Now().Format(time.RFC3339),
+						CreatedAt:   a.now().Format(time.RFC3339),
+						UpdatedAt:   a.now().Format(time.RFC3339),
 					}
 					if err := a.db.CreateQuestionGroup(newGroup); err == nil {
 						targetGroupID = newGroup.ID
@@ -229,39 +280,164 @@ func (a *App) GetQuestions() ([]Question, error) {
 	return a.db.GetQuestions()
 }
 
-// GetQuestionsByGroup returns questions for a specific group
+// GetQuestionsByGroup returns questions for a specific group in the group's order.
+// Smart groups are evaluated against their saved query.
 func (a *App) GetQuestionsByGroup(groupID string) ([]Question, error) {
-	return a.db.GetQuestionsByGroup(groupID)
+	return a.groupQuestions(groupID)
+}
+
+// ReorderGroupQuestions sets the order of questions within a group. Questions in the
+// group that are not listed keep their relative order after the listed ones.
+func (a *App) ReorderGroupQuestions(groupID string, orderedIDs []string) error {
+	current, err := a.db.GetGroupQuestionIDs(groupID)
+	if err != nil {
+		return fmt.Errorf("failed to get group questions: %v", err)
+	}
+
+	inGroup := make(map[string]bool, len(current))
+	for _, id := range current {
+		inGroup[id] = true
+	}
+
+	ordered := make([]string, 0, len(current))
+	listed := make(map[string]bool, len(orderedIDs))
+	for _, id := range orderedIDs {
+		if !inGroup[id] {
+			return fmt.Errorf("question %s is not in group %s", id, groupID)
+		}
+		if listed[id] {
+			return fmt.Errorf("question %s is listed more than once", id)
+		}
+		listed[id] = true
+		ordered = append(ordered, id)
+	}
+	for _, id := range current {
+		if !listed[id] {
+			ordered = append(ordered, id)
+		}
+	}
+
+	return a.setGroupQuestionOrder(groupID, ordered, "Reorder group questions")
+}
+
+// MoveQuestionInGroup moves a question to a new position within a group
+func (a *App) MoveQuestionInGroup(groupID, questionID string, newPosition int) error {
+	current, err := a.db.GetGroupQuestionIDs(groupID)
+	if err != nil {
+		return fmt.Errorf("failed to get group questions: %v", err)
+	}
+
+	ordered := make([]string, 0, len(current))
+	found := false
+	for _, id := range current {
+		if id == questionID {
+			found = true
+			continue
+		}
+		ordered = append(ordered, id)
+	}
+	if !found {
+		return fmt.Errorf("question %s is not in group %s", questionID, groupID)
+	}
+
+	if newPosition < 0 || newPosition > len(ordered) {
+		newPosition = len(ordered)
+	}
+	ordered = append(ordered[:newPosition], append([]string{questionID}, ordered[newPosition:]...)...)
+
+	return a.setGroupQuestionOrder(groupID, ordered, "Move question in group")
+}
+
+// setGroupQuestionOrder stores a group's question order as one undoable operation
+func (a *App) setGroupQuestionOrder(groupID string, ordered []string, label string) error {
+	scopes := []journalScope{{table: "question_group_relations", where: "group_id = ?", args: []interface{}{groupID}}}
+	return a.journaled(label, scopes, func() error {
+		if err := a.db.SetGroupQuestionOrder(groupID, ordered); err != nil {
+			return fmt.Errorf("failed to reorder group questions: %v", err)
+		}
+		return nil
+	})
 }
 
 // Question Group management methods
 
 // CreateQuestionGroup creates a new question group
 func (a *App) CreateQuestionGroup(name, description, parentID, color, icon string) (*QuestionGroup, error) {
+	group, err := a.newQuestionGroup(name, description, parentID, color, icon)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := []journalScope{{table: "question_groups", where: "id = ?", args: []interface{}{group.ID}}}
+	err = a.journaled("Create group "+name, scopes, func() error {
+		return a.db.CreateQuestionGroup(group)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// newQuestionGroup builds a group placed after its siblings under an existing parent,
+// without storing it
+func (a *App) newQuestionGroup(name, description, parentID, color, icon string) (*QuestionGroup, error) {
 	group := &QuestionGroup{
 		ID:          fmt.Sprintf("group_%d_%d", time.Now().UnixNano(), rand.Int63()),
 		Name:        name,
 		Description: description,
 		Color:       color,
 		Icon:        icon,
-		CreatedAt:   time.Now().Format(time.RFC3339),
-		UpdatedAt:   time.Now().Format(time.RFC3339),
+		CreatedAt:   a.now().Format(time.RFC3339),
+		UpdatedAt:   a.now().Format(time.RFC3339),
 	}
 
 	if parentID != "" {
+		if err := a.checkParentGroup(parentID); err != nil {
+			return nil, err
+		}
 		group.ParentID = &parentID
 	}
 
-	if err := a.db.CreateQuestionGroup(group); err != nil {
-		return nil, err
+	position, err := a.db.nextGroupPosition(group.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group position: %v", err)
 	}
-
+	group.Position = position
 	return group, nil
 }
 
-// GetQuestionGroups returns all question groups
+// GetQuestionGroups returns all question groups, with smart group members evaluated
+// and the coverage of each group's questions
 func (a *App) GetQuestionGroups() ([]QuestionGroup, error) {
-	return a.db.GetQuestionGroups()
+	groups, err := a.db.GetQuestionGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := a.newSmartQueryContext(groups)
+	for i := range groups {
+		if groups[i].SmartQuery == nil {
+			continue
+		}
+		ids, err := ctx.resolveGroup(groups[i].ID)
+		if err != nil {
+			log.Printf("Warning: Failed to evaluate smart group %s: %v", groups[i].ID, err)
+			continue
+		}
+		groups[i].QuestionIds = ids
+	}
+
+	stats, err := a.db.GetQuestionExposures()
+	if err != nil {
+		log.Printf("Warning: Failed to load question exposures: %v", err)
+		return groups, nil
+	}
+	for i := range groups {
+		coverage := coverageStats(groups[i].ID, groups[i].Name, groups[i].QuestionIds, stats)
+		groups[i].Coverage = &coverage
+	}
+	return groups, nil
 }
 
 // CreateQuestion creates a new question
@@ -272,52 +448,292 @@ func (a *App) CreateQuestion(question Question) (*Question, error) {
 	}
 	
 	// Set timestamps
-	now := time.Now().Format(time.RFC3339)
+	now := a.now().Format(time.RFC3339)
 	question.CreatedAt = now
 	question.UpdatedAt = now
 	
 	// Save to database
-	if err := a.db.CreateQuestion(&question); err != nil {
-		return nil, fmt.Errorf("failed to create question: %v", err)
+	scopes := append(questionJournalScopes(question.ID),
+		journalScope{table: "question_revisions", where: "question_id = ?", args: []interface{}{question.ID}},
+	)
+	err := a.journaled("Create question", scopes, func() error {
+		if err := a.db.CreateQuestion(&question); err != nil {
+			return fmt.Errorf("failed to create question: %v", err)
+		}
+		if err := a.recordQuestionRevision(question.ID, RevisionActorUser, "Created"); err != nil {
+			log.Printf("Warning: Failed to record revision for question %s: %v", question.ID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	
 	return &question, nil
 }
 // UpdateQuestion updates an existing question
 func (a *App) UpdateQuestion(question Question) error {
+	return a.UpdateQuestionWithReason(question, "")
+}
+
+// UpdateQuestionWithReason updates an existing question and records why it changed
+func (a *App) UpdateQuestionWithReason(question Question, reason string) error {
 	// Set updated timestamp
-	question.UpdatedAt = time.Now().Format(time.RFC3339)
+	question.UpdatedAt = a.now().Format(time.RFC3339)
 	
 	// Update in database
-	if err := a.db.UpdateQuestion(&question); err != nil {
-		return fmt.Errorf("failed to update question: %v", err)
-	}
-	
-	return nil
+	scopes := append(questionJournalScopes(question.ID),
+		journalScope{table: "question_revisions", where: "question_id = ?", args: []interface{}{question.ID}},
+	)
+	return a.journaled("Update question", scopes, func() error {
+		if err := a.ensureQuestionRevisionBaseline(question.ID); err != nil {
+			log.Printf("Warning: Failed to record baseline revision for question %s: %v", question.ID, err)
+		}
+		if err := a.db.UpdateQuestion(&question); err != nil {
+			return fmt.Errorf("failed to update question: %v", err)
+		}
+		if err := a.recordQuestionRevision(question.ID, RevisionActorUser, reason); err != nil {
+			log.Printf("Warning: Failed to record revision for question %s: %v", question.ID, err)
+		}
+		return nil
+	})
 }
 // DeleteQuestion deletes a question by ID
 func (a *App) DeleteQuestion(questionID string) error {
-	if err := a.db.DeleteQuestion(questionID); err != nil {
-		return fmt.Errorf("failed to delete question: %v", err)
-	}
-	return nil
+	scopes := append(questionJournalScopes(questionID),
+		journalScope{table: "question_group_relations", where: "question_id = ?", args: []interface{}{questionID}},
+		journalScope{table: "question_revisions", where: "question_id = ?", args: []interface{}{questionID}},
+		journalScope{table: "item_calibrations", where: "question_id = ?", args: []interface{}{questionID}},
+	)
+	return a.journaled("Delete question", scopes, func() error {
+		if err := a.db.DeleteQuestion(questionID); err != nil {
+			return fmt.Errorf("failed to delete question: %v", err)
+		}
+		return nil
+	})
 }
 // GetQuestionByID gets a question by ID
 func (a *App) GetQuestionByID(questionID string) (*Question, error) {
 	return a.db.GetQuestionByID(questionID)
 }
 
+// Question revision methods
+
+// GetQuestionRevisions returns the revision history of a question, oldest first
+func (a *App) GetQuestionRevisions(questionID string) ([]QuestionRevision, error) {
+	revisions, err := a.db.GetQuestionRevisions(questionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question revisions: %v", err)
+	}
+	return revisions, nil
+}
+
+// DiffQuestionRevisions returns the field-level differences between two revisions of a question
+func (a *App) DiffQuestionRevisions(questionID string, fromRevision, toRevision int) ([]QuestionFieldChange, error) {
+	from, err := a.loadRevisionSnapshot(questionID, fromRevision)
+	if err != nil {
+		return nil, err
+	}
+	to, err := a.loadRevisionSnapshot(questionID, toRevision)
+	if err != nil {
+		return nil, err
+	}
+	return diffQuestions(from, to), nil
+}
+
+// RollbackQuestion restores a question to the content of an earlier revision.
+// The rollback itself is recorded as a new revision.
+func (a *App) RollbackQuestion(questionID string, revision int) (*Question, error) {
+	target, err := a.loadRevisionSnapshot(questionID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := a.db.GetQuestionByID(questionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question: %v", err)
+	}
+
+	restored := *target
+	restored.ID = current.ID
+	restored.CreatedAt = current.CreatedAt
+	restored.UpdatedAt = a.now().Format(time.RFC3339)
+
+	scopes := append(questionJournalScopes(questionID),
+		journalScope{table: "question_revisions", where: "question_id = ?", args: []interface{}{questionID}},
+	)
+	err = a.journaled(fmt.Sprintf("Roll back question to revision %d", revision), scopes, func() error {
+		if err := a.ensureQuestionRevisionBaseline(questionID); err != nil {
+			return fmt.Errorf("failed to record baseline revision: %v", err)
+		}
+		if err := a.db.UpdateQuestion(&restored); err != nil {
+			return fmt.Errorf("failed to roll back question: %v", err)
+		}
+		reason := fmt.Sprintf("Rolled back to revision %d", revision)
+		if err := a.recordQuestionRevision(questionID, RevisionActorRollback, reason); err != nil {
+			return fmt.Errorf("failed to record rollback revision: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &restored, nil
+}
+
+// loadRevisionSnapshot returns the question content stored in a revision
+func (a *App) loadRevisionSnapshot(questionID string, revision int) (*Question, error) {
+	rev, err := a.db.GetQuestionRevision(questionID, revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision %d: %v", revision, err)
+	}
+
+	var q Question
+	if err := json.Unmarshal(rev.Snapshot, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse revision %d: %v", revision, err)
+	}
+	return &q, nil
+}
+
+// ensureQuestionRevisionBaseline records the current state of a question as its first
+// revision if it has no history yet, so edits to pre-existing questions can be rolled back
+func (a *App) ensureQuestionRevisionBaseline(questionID string) error {
+	latest, err := a.db.GetLatestQuestionRevision(questionID)
+	if err != nil {
+		return err
+	}
+	if latest != nil {
+		return nil
+	}
+	return a.recordQuestionRevision(questionID, RevisionActorBaseline, "")
+}
+
+// recordQuestionRevision snapshots the stored question and records what changed since
+// the previous revision. Nothing is recorded when the content is unchanged.
+func (a *App) recordQuestionRevision(questionID, actor, reason string) error {
+	current, err := a.db.GetQuestionByID(questionID)
+	if err != nil {
+		return err
+	}
+
+	latest, err := a.db.GetLatestQuestionRevision(questionID)
+	if err != nil {
+		return err
+	}
+
+	var previous *Question
+	if latest != nil {
+		var q Question
+		if err := json.Unmarshal(latest.Snapshot, &q); err != nil {
+			return fmt.Errorf("failed to parse revision %d: %v", latest.Revision, err)
+		}
+		previous = &q
+	}
+
+	changes := diffQuestions(previous, current)
+	if previous != nil && len(changes) == 0 {
+		return nil
+	}
+
+	snapshotJSON, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	return a.db.CreateQuestionRevision(&QuestionRevision{
+		ID:         fmt.Sprintf("rev_%d_%d", time.Now().UnixNano(), rand.Int63()),
+		QuestionID: questionID,
+		Actor:      actor,
+		Reason:     reason,
+		Snapshot:   snapshotJSON,
+		Changes:    changesJSON,
+		CreatedAt:  a.now().Format(time.RFC3339),
+	})
+}
+
+// diffQuestions returns the content fields that differ between two question versions.
+// A nil "from" question reports every field as added.
+func diffQuestions(from, to *Question) []QuestionFieldChange {
+	fieldValues := func(q *Question) map[string]interface{} {
+		if q == nil {
+			return map[string]interface{}{}
+		}
+		return map[string]interface{}{
+			"question":    q.Question,
+			"options":     q.Options,
+			"answer":      q.Answer,
+			"explanation": q.Explanation,
+			"tags":        q.Tags,
+			"imageUrl":    q.ImageURL,
+			"difficulty":  q.Difficulty,
+			"source":      q.Source,
+			"index":       q.Index,
+		}
+	}
+
+	fields := []string{"question", "options", "answer", "explanation", "tags", "imageUrl", "difficulty", "source", "index"}
+	fromValues := fieldValues(from)
+	toValues := fieldValues(to)
+
+	changes := []QuestionFieldChange{}
+	for _, field := range fields {
+		fromJSON := normalizedJSON(fromValues[field])
+		toJSON := normalizedJSON(toValues[field])
+		if string(fromJSON) == string(toJSON) {
+			continue
+		}
+		changes = append(changes, QuestionFieldChange{
+			Field: field,
+			From:  fromJSON,
+			To:    toJSON,
+		})
+	}
+	return changes
+}
+
+// normalizedJSON marshals a value into compact JSON so equivalent values compare equal
+func normalizedJSON(value interface{}) json.RawMessage {
+	if raw, ok := value.(json.RawMessage); ok {
+		if len(raw) == 0 {
+			return json.RawMessage("null")
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(raw, &decoded); err == nil {
+			value = decoded
+		}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	return data
+}
+
 // Practice Session management methods
 
-// CreatePracticeSession creates a new practice session
+// CreatePracticeSession creates a new practice session. When totalQuestions is not
+// positive, the session covers every question currently in the group.
 func (a *App) CreatePracticeSession(groupID, mode string, totalQuestions int) (*PracticeSession, error) {
+	if totalQuestions <= 0 && groupID != "" {
+		questions, err := a.groupQuestions(groupID)
+		if err != nil {
+			return nil, err
+		}
+		totalQuestions = len(questions)
+	}
+
 	session := &PracticeSession{
 		ID:             fmt.Sprintf("session_%d", time.Now().Unix()),
 		GroupID:        groupID,
 		Mode:           mode,
-		StartTime:      time.Now().Format(time.RFC3339),
+		StartTime:      a.now().Format(time.RFC3339),
 		TotalQuestions: totalQuestions,
-		CreatedAt:      time.Now().Format(time.RFC3339),
+		CreatedAt:      a.now().Format(time.RFC3339),
 	}
 
 	if err := a.db.CreatePracticeSession(session); err != nil {
@@ -337,7 +753,7 @@ func (a *App) SavePracticeSession(sessionData map[string]interface{}) error {
 		TotalQuestions: int(sessionData["totalQuestions"].(float64)),
 		CorrectCount:   int(sessionData["correctCount"].(float64)),
 		Duration:       int(sessionData["duration"].(float64)),
-		CreatedAt:      time.Now().Format(time.RFC3339),
+		CreatedAt:      a.now().Format(time.RFC3339),
 	}
 
 	// Parse start time
@@ -368,116 +784,21 @@ func (a *App) SavePracticeSession(sessionData map[string]interface{}) error {
 		session.Details = detailsJSON
 	}
 
-	// Update question difficulties based on session results
-	if err := a.updateQuestionDifficulties(*session); err != nil {
-		log.Printf("Warning: Failed to update question difficulties: %v", err)
-	}
-
 	// Auto-add wrong questions (but check user preferences first)
 	if err := a.AddWrongQuestionsFromSession(sessionData); err != nil {
 		log.Printf("Warning: Failed to add wrong questions from session: %v", err)
 	}
 
-	return a.db.CreatePracticeSession(session)
-}
-
-// updateQuestionDifficulties adjusts question difficulties based on accuracy rates
-func (a *App) updateQuestionDifficulties(session PracticeSession) error {
-	if session.TotalQuestions == 0 {
-		return nil
-	}
-
-	// Parse session details to get question-level results
-	var questions []map[string]interface{}
-	if err := json.Unmarshal(session.Details, &questions); err != nil {
-		return fmt.Errorf("failed to parse session details: %v", err)
-	}
-
-	// Calculate accuracy rate
-	accuracyRate := float64(session.CorrectCount) / float64(session.TotalQuestions) * 100
-
-	// Determine difficulty adjustment based on accuracy
-	var difficultyAdjustment int
-	switch {
-	case accuracyRate > 80:
-		difficultyAdjustment = 1 // Make harder
-	case accuracyRate >= 60:
-		difficultyAdjustment = 0 // Keep same
-	case accuracyRate >= 40:
-		difficultyAdjustment = -1 // Make slightly easier
-	case accuracyRate >= 20:
-		difficultyAdjustment = -2 // Make moderately easier
-	default:
-		difficultyAdjustment = -3 // Make much easier
-	}
-
-	log.Printf("Session accuracy: %.1f%%, difficulty adjustment: %d", accuracyRate, difficultyAdjustment)
-
-	// Update each question's difficulty based on individual performance
-	for _, q := range questions {
-		questionID, ok := q["questionId"].(string)
-		if !ok {
-			continue
-		}
-
-		isCorrect, ok := q["isCorrect"].(bool)
-		if !ok {
-			continue
-		}
-
-		// Get current question
-		currentQuestion, err := a.db.GetQuestionByID(questionID)
-		if err != nil {
-			log.Printf("Warning: Failed to get question %s: %v", questionID, err)
-			continue
-		}
-
-		// Calculate individual adjustment
-		individualAdjustment := difficultyAdjustment
-		if isCorrect && accuracyRate <= 60 {
-			// Question was answered correctly but overall performance was poor
-			// Don't make this specific question harder
-			individualAdjustment = 0
-		} else if !isCorrect && accuracyRate >= 80 {
-			// Question was answered incorrectly but overall performance was good
-			// Make this specific question slightly easier
-			individualAdjustment = -1
-		}
-
-		// Apply adjustment
-		newDifficulty := currentQuestion.Difficulty
-		if newDifficulty != nil {
-			adjustedDifficulty := *newDifficulty + individualAdjustment
-			// Clamp between 1 and 5
-			if adjustedDifficulty < 1 {
-				adjustedDifficulty = 1
-			} else if adjustedDifficulty > 5 {
-				adjustedDifficulty = 5
-			}
-			newDifficulty = &adjustedDifficulty
-		} else {
-			// If no difficulty set, start with 3 (medium) and apply adjustment
-			baseDifficulty := 3 + individualAdjustment
-			if baseDifficulty < 1 {
-				baseDifficulty = 1
-			} else if baseDifficulty > 5 {
-				baseDifficulty = 5
-			}
-			newDifficulty = &baseDifficulty
-		}
-
-		// Update in database
-		if err := a.db.UpdateQuestionDifficulty(questionID, newDifficulty); err != nil {
-			log.Printf("Warning: Failed to update difficulty for question %s: %v", questionID, err)
-		} else {
-			oldDiff := "nil"
-			if currentQuestion.Difficulty != nil {
-				oldDiff = fmt.Sprintf("%d", *currentQuestion.Difficulty)
-			}
-			log.Printf("Updated question %s difficulty: %s → %d", questionID, oldDiff, *newDifficulty)
-		}
+	if err := a.db.CreatePracticeSession(session); err != nil {
+		return err
 	}
 
+	// Recalibrate question difficulties with the new answers in the background
+	a.StartCalibration(CalibrationModel2PL)
+	a.recordReadiness(session)
+	a.recordExposures(session)
+	a.refreshStudyPlans()
+	a.evaluateGoals()
 	return nil
 }
 
@@ -488,6 +809,19 @@ func (a *App) GetPracticeSessions() ([]PracticeSession, error) {
 
 // InitializeDemoData creates demo question groups and questions for new users
 func (a *App) InitializeDemoData() ImportResult {
+	var result ImportResult
+	err := a.journaled("Initialize demo data", questionBankJournalScopes(), func() error {
+		result = a.initializeDemoData()
+		return nil
+	})
+	if err != nil {
+		return ImportResult{Success: false, Errors: []string{err.Error()}}
+	}
+	return result
+}
+
+// initializeDemoData creates demo groups and questions without journaling
+func (a *App) initializeDemoData() ImportResult {
 	result := ImportResult{
 		Success:    true,
 		Imported:   0,
@@ -529,7 +863,10 @@ func (a *App) InitializeDemoData() ImportResult {
 	log.Printf("Creating %d demo groups", len(demoGroups))
 	for _, demoGroup := range demoGroups {
 		log.Printf("Creating group: %s", demoGroup.name)
-		group, err := a.CreateQuestionGroup(demoGroup.name, demoGroup.description, "", demoGroup.color, demoGroup.icon)
+		group, err := a.newQuestionGroup(demoGroup.name, demoGroup.description, "", demoGroup.color, demoGroup.icon)
+		if err == nil {
+			err = a.db.CreateQuestionGroup(group)
+		}
 		if err != nil {
 			log.Printf("ERROR: Failed to create group %s: %v", demoGroup.name, err)
 			result.Errors = append(result.Errors, fmt.Sprintf("Failed to create group %s: %v", demoGroup.name, err))
@@ -739,7 +1076,7 @@ func (a *App) InitializeDemoData() ImportResult {
 		log.Printf("Question data - Options: %s, Answer: %s, Tags: %s", string(optionsJSON), string(answerJSON), string(tagsJSON))
 
 		// Import single question
-		importResult := a.ImportQuestions([]map[string]interface{}{questionData}, groupID)
+		importResult := a.importQuestions([]map[string]interface{}{questionData}, groupID)
 		log.Printf("Import result - Success: %v, Imported: %d, Errors: %v, Duplicates: %d", 
 			importResult.Success, importResult.Imported, importResult.Errors, importResult.Duplicates)
 
@@ -766,6 +1103,19 @@ func (a *App) InitializeDemoData() ImportResult {
 
 // ClearDemoData removes all demo data for testing purposes
 func (a *App) ClearDemoData() ImportResult {
+	var result ImportResult
+	err := a.journaled("Clear demo data", questionBankJournalScopes(), func() error {
+		result = a.clearDemoData()
+		return nil
+	})
+	if err != nil {
+		return ImportResult{Success: false, Errors: []string{err.Error()}}
+	}
+	return result
+}
+
+// clearDemoData removes demo questions and groups without journaling
+func (a *App) clearDemoData() ImportResult {
 	result := ImportResult{
 		Success:    true,
 		Imported:   0,
@@ -854,6 +1204,7 @@ func (a *App) GetUserSettings() (map[string]interface{}, error) {
 		"autoSave", "showExplanations", "randomizeQuestions", "randomizeOptions",
 		"enableNotifications", "reminderTime", "studyGoal", "questionSpacing",
 		"showProgress", "highlightCorrectAnswers", "saveHistory", "shareAnonymousStats",
+		"leechThreshold", "leechAction", "streakGraceDays", "preferUnderexposed",
 	}
 
 	settings := make(map[string]interface{})
@@ -879,12 +1230,14 @@ func (a *App) GetUserSettings() (map[string]interface{}, error) {
 
 // UpdateUserSettings updates user settings
 func (a *App) UpdateUserSettings(settings map[string]interface{}) error {
-	for key, value := range settings {
-		if err := a.db.SetSetting(key, value); err != nil {
-			return fmt.Errorf("failed to update setting %s: %v", key, err)
+	return a.journaled("Update settings", []journalScope{{table: "user_settings"}}, func() error {
+		for key, value := range settings {
+			if err := a.db.SetSetting(key, value); err != nil {
+				return fmt.Errorf("failed to update setting %s: %v", key, err)
+			}
 		}
-	}
-	return nil
+		return nil
+	})
 }
 
 // GetUserSetting gets a single user setting
@@ -904,11 +1257,19 @@ func (a *App) GetUserSetting(key string) (interface{}, error) {
 
 // SetUserSetting sets a single user setting
 func (a *App) SetUserSetting(key string, value interface{}) error {
-	return a.db.SetSetting(key, value)
+	scopes := []journalScope{{table: "user_settings", where: "key = ?", args: []interface{}{key}}}
+	return a.journaled("Update setting "+key, scopes, func() error {
+		return a.db.SetSetting(key, value)
+	})
 }
 
 // ResetAllData resets all user data including settings
 func (a *App) ResetAllData() error {
+	return a.journaled("Reset all data", resetJournalScopes(), a.resetAllData)
+}
+
+// resetAllData deletes all user data without journaling
+func (a *App) resetAllData() error {
 	// Delete all questions
 	questions, err := a.db.GetQuestions()
 	if err != nil {
@@ -921,6 +1282,11 @@ func (a *App) ResetAllData() error {
 		}
 	}
 	
+	// Delete any revision history left behind by earlier question deletions
+	if _, err := a.db.db.Exec("DELETE FROM question_revisions"); err != nil {
+		return fmt.Errorf("failed to delete question revisions: %v", err)
+	}
+	
 	// Delete all groups
 	groups, err := a.db.GetQuestionGroups()
 	if err != nil {
@@ -933,11 +1299,83 @@ func (a *App) ResetAllData() error {
 		}
 	}
 	
+	// Delete exam blueprints
+	if _, err := a.db.db.Exec("DELETE FROM exam_blueprints"); err != nil {
+		return fmt.Errorf("failed to delete exam blueprints: %v", err)
+	}
+	
+	// Delete question templates and their generated variants
+	if _, err := a.db.db.Exec("DELETE FROM template_instances"); err != nil {
+		return fmt.Errorf("failed to delete template instances: %v", err)
+	}
+	if _, err := a.db.db.Exec("DELETE FROM question_templates"); err != nil {
+		return fmt.Errorf("failed to delete question templates: %v", err)
+	}
+	
+	// Delete question suspensions
+	if _, err := a.db.db.Exec("DELETE FROM question_suspensions"); err != nil {
+		return fmt.Errorf("failed to delete question suspensions: %v", err)
+	}
+	
+	// Delete study plans
+	if _, err := a.db.db.Exec("DELETE FROM study_plans"); err != nil {
+		return fmt.Errorf("failed to delete study plans: %v", err)
+	}
+	
+	// Delete readiness statistics
+	if _, err := a.db.db.Exec("DELETE FROM readiness_stats"); err != nil {
+		return fmt.Errorf("failed to delete readiness statistics: %v", err)
+	}
+	
+	// Delete question exposures
+	if _, err := a.db.db.Exec("DELETE FROM question_exposures"); err != nil {
+		return fmt.Errorf("failed to delete question exposures: %v", err)
+	}
+	
+	// Delete goals and achievements
+	if _, err := a.db.db.Exec("DELETE FROM study_goals"); err != nil {
+		return fmt.Errorf("failed to delete goals: %v", err)
+	}
+	if _, err := a.db.db.Exec("DELETE FROM achievements"); err != nil {
+		return fmt.Errorf("failed to delete achievements: %v", err)
+	}
+	
+	// Delete all tags
+	if _, err := a.db.db.Exec("DELETE FROM tags"); err != nil {
+		return fmt.Errorf("failed to delete tags: %v", err)
+	}
+	
 	// Delete all practice sessions
 	if _, err := a.db.db.Exec("DELETE FROM practice_sessions"); err != nil {
 		return fmt.Errorf("failed to delete practice sessions: %v", err)
 	}
 	
+	// Delete session state
+	if _, err := a.db.db.Exec("DELETE FROM active_sessions"); err != nil {
+		return fmt.Errorf("failed to delete active sessions: %v", err)
+	}
+	if _, err := a.db.db.Exec("DELETE FROM session_events"); err != nil {
+		return fmt.Errorf("failed to delete session events: %v", err)
+	}
+	
+	// Delete timed exam state
+	if _, err := a.db.db.Exec("DELETE FROM timed_exams"); err != nil {
+		return fmt.Errorf("failed to delete timed exams: %v", err)
+	}
+	
+	// Delete adaptive session state
+	if _, err := a.db.db.Exec("DELETE FROM adaptive_sessions"); err != nil {
+		return fmt.Errorf("failed to delete adaptive sessions: %v", err)
+	}
+	
+	// Delete calibration history
+	if _, err := a.db.db.Exec("DELETE FROM calibration_runs"); err != nil {
+		return fmt.Errorf("failed to delete calibration runs: %v", err)
+	}
+	if _, err := a.db.db.Exec("DELETE FROM item_calibrations"); err != nil {
+		return fmt.Errorf("failed to delete item calibrations: %v", err)
+	}
+	
 	// Delete all settings
 	if _, err := a.db.db.Exec("DELETE FROM user_settings"); err != nil {
 		return fmt.Errorf("failed to delete user settings: %v", err)
@@ -979,7 +1417,7 @@ func (a *App) ExportUserData() (map[string]interface{}, error) {
 	data["settings"] = settings
 	
 	// Add metadata
-	data["exportedAt"] = time.Now().Format(time.RFC3339)
+	data["exportedAt"] = a.now().Format(time.RFC3339)
 	data["version"] = "1.0.0"
 	
 	return data, nil
@@ -999,7 +1437,7 @@ func (a *App) ExportSelectiveData(options ExportOptions) (map[string]interface{}
 			// Export questions from specific groups only
 			allQuestions := make([]Question, 0)
 			for _, groupID := range options.GroupIDs {
-				groupQuestions, err := a.db.GetQuestionsByGroup(groupID)
+				groupQuestions, err := a.groupQuestions(groupID)
 				if err != nil {
 					return nil, fmt.Errorf("failed to get questions for group %s: %v", groupID, err)
 				}
@@ -1125,7 +1563,7 @@ func (a *App) ExportSelectiveData(options ExportOptions) (map[string]interface{}
 
 // ExportGroupAsCSV exports questions from a specific group in CSV format
 func (a *App) ExportGroupAsCSV(groupID string) (string, error) {
-	questions, err := a.db.GetQuestionsByGroup(groupID)
+	questions, err := a.groupQuestions(groupID)
 	if err != nil {
 		return "", fmt.Errorf("failed to get questions for group: %v", err)
 	}
@@ -1325,6 +1763,21 @@ func parseCSVLine(line string) []string {
 
 // ImportUserData imports all user data from exported JSON
 func (a *App) ImportUserData(data map[string]interface{}) ImportResult {
+	var result ImportResult
+	scopes := append(questionBankJournalScopes(), journalScope{table: "user_settings"})
+	err := a.journaled("Import user data", scopes, func() error {
+		result = a.importUserData(data)
+		return nil
+	})
+	if err != nil {
+		return ImportResult{Success: false, Errors: []string{err.Error()}}
+	}
+	a.rebuildAttemptCaches()
+	return result
+}
+
+// importUserData imports exported user data without journaling
+func (a *App) importUserData(data map[string]interface{}) ImportResult {
 	result := ImportResult{
 		Success:    true,
 		Imported:   0,
@@ -1380,6 +1833,9 @@ func (a *App) ImportUserData(data map[string]interface{}) ImportResult {
 					result.Errors = append(result.Errors, fmt.Sprintf("Failed to import question %s: %v", question.ID, err))
 				} else {
 					result.Imported++
+					if err := a.recordQuestionRevision(question.ID, RevisionActorImport, "Imported from user data"); err != nil {
+						log.Printf("Warning: Failed to record revision for question %s: %v", question.ID, err)
+					}
 				}
 			}
 		}
@@ -1438,7 +1894,41 @@ func (a *App) ImportUserData(data map[string]interface{}) ImportResult {
 	return result
 }
 
-// GetWeakestTopics analyzes user performance to identify weak topics
+// questionTopics returns the topics a question counts towards: its tags rolled up into
+// their parent topics, or its source when it has no tags
+func questionTopics(question Question) []string {
+	// Parse tags
+	var tags []string
+	if question.Tags != nil {
+		json.Unmarshal(question.Tags, &tags)
+	}
+
+	// If no tags, use source as topic
+	if len(tags) == 0 {
+		if question.Source != "" {
+			tags = []string{question.Source}
+		} else {
+			tags = []string{"General"}
+		}
+	}
+
+	// Roll hierarchical tags up into their parent topics, counting each topic once
+	var topics []string
+	seenTopics := make(map[string]bool)
+	for _, tag := range tags {
+		for _, topic := range tagPathAncestors(tag) {
+			if !seenTopics[topic] {
+				seenTopics[topic] = true
+				topics = append(topics, topic)
+			}
+		}
+	}
+	return topics
+}
+
+// GetWeakestTopics analyzes user performance to identify weak topics, ranked by the
+// recall predicted from each topic's forgetting curve so topics that were once strong
+// but have not been practiced for a while are surfaced too
 func (a *App) GetWeakestTopics() ([]map[string]interface{}, error) {
 	// Get all practice sessions
 	sessions, err := a.db.GetPracticeSessions()
@@ -1446,6 +1936,16 @@ func (a *App) GetWeakestTopics() ([]map[string]interface{}, error) {
 		return nil, fmt.Errorf("failed to get practice sessions: %v", err)
 	}
 
+	// Get the forgetting curve of each topic
+	retentionList, err := a.GetTopicRetention()
+	if err != nil {
+		return nil, err
+	}
+	retention := make(map[string]TopicRetention, len(retentionList))
+	for _, topic := range retentionList {
+		retention[topic.Topic] = topic
+	}
+
 	// Topic performance tracking
 	topicStats := make(map[string]struct {
 		total    int
@@ -1481,20 +1981,7 @@ func (a *App) GetWeakestTopics() ([]map[string]interface{}, error) {
 				continue
 			}
 
-			// Parse tags
-			var tags []string
-			if question.Tags != nil {
-				json.Unmarshal(question.Tags, &tags)
-			}
-
-			// If no tags, use source as topic
-			if len(tags) == 0 {
-				if question.Source != "" {
-					tags = []string{question.Source}
-				} else {
-					tags = []string{"General"}
-				}
-			}
+			tags := questionTopics(*question)
 
 			// Update stats for each tag/topic
 			for _, tag := range tags {
@@ -1532,21 +2019,31 @@ func (a *App) GetWeakestTopics() ([]map[string]interface{}, error) {
 
 		accuracy := float64(stats.correct) / float64(stats.total) * 100
 		
+		// Without a forgetting curve the accuracy stands in for the recall
+		predictedRecall, halfLife, daysSince, stale := accuracy, defaultHalfLifeDays, 0.0, false
+		if curve, ok := retention[topic]; ok {
+			predictedRecall, halfLife, daysSince, stale = curve.PredictedRecall, curve.HalfLifeDays, curve.DaysSincePractice, curve.Stale
+		}
+		
 		result := map[string]interface{}{
 			"topic":        topic,
 			"totalAttempts": stats.total,
 			"correctCount":  stats.correct,
 			"accuracy":     accuracy,
 			"category":     stats.category,
+			"predictedRecall":   predictedRecall,
+			"halfLifeDays":      halfLife,
+			"daysSincePractice": daysSince,
+			"stale":             stale,
 		}
 		
 		weakestTopics = append(weakestTopics, result)
 	}
 
-	// Sort by accuracy (ascending) to get weakest topics first
+	// Sort by predicted recall (ascending) to get weakest topics first
 	for i := 0; i < len(weakestTopics)-1; i++ {
 		for j := i + 1; j < len(weakestTopics); j++ {
-			if weakestTopics[i]["accuracy"].(float64) > weakestTopics[j]["accuracy"].(float64) {
+			if weakestTopics[i]["predictedRecall"].(float64) > weakestTopics[j]["predictedRecall"].(float64) {
 				weakestTopics[i], weakestTopics[j] = weakestTopics[j], weakestTopics[i]
 			}
 		}
@@ -1567,11 +2064,13 @@ func (a *App) AddWrongQuestion(questionID string, notes string) error {
 	wrongQuestion := &WrongQuestion{
 		ID:         fmt.Sprintf("wrong_%d_%d", time.Now().UnixNano(), rand.Int63()),
 		QuestionID: questionID,
-		AddedAt:    time.Now().Format(time.RFC3339),
+		AddedAt:    a.now().Format(time.RFC3339),
 		Notes:      notes,
 	}
 
-	return a.db.AddWrongQuestion(wrongQuestion)
+	return a.journaled("Add wrong question", wrongQuestionJournalScopes(questionID), func() error {
+		return a.db.AddWrongQuestion(wrongQuestion)
+	})
 }
 
 // AddWrongQuestionsFromSession automatically adds wrong questions from a practice session
@@ -1582,21 +2081,8 @@ func (a *App) AddWrongQuestionsFromSession(sessionData map[string]interface{}) e
 			qMap := q.(map[string]interface{})
 			if isCorrect, ok := qMap["isCorrect"].(bool); ok && !isCorrect {
 				if questionID, ok := qMap["questionId"].(string); ok {
-					// Check if already exists
-					exists, err := a.db.IsQuestionMarkedWrong(questionID)
-					if err != nil {
-						continue
-					}
-					if !exists {
-						// Add to wrong questions
-						wrongQuestion := &WrongQuestion{
-							ID:         fmt.Sprintf("wrong_%d_%d", time.Now().UnixNano(), rand.Int63()),
-							QuestionID: questionID,
-							AddedAt:    time.Now().Format(time.RFC3339),
-							Notes:      "Added from practice session",
-						}
-						a.db.AddWrongQuestion(wrongQuestion)
-					}
+					confidence, _ := qMap["confidence"].(string)
+					a.addWrongQuestionFromSession(questionID, wrongQuestionPriority(confidence), true)
 				}
 			}
 		}
@@ -1604,6 +2090,95 @@ func (a *App) AddWrongQuestionsFromSession(sessionData map[string]interface{}) e
 	return nil
 }
 
+// saveCompletedSession stores a session finished by a backend session engine and feeds
+// its answers into the wrong questions and the difficulty calibration. The writes stay
+// out of journaled operations, which must not undo them.
+func (a *App) saveCompletedSession(session *PracticeSession, records []QuestionRecord) error {
+	details, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session details: %v", err)
+	}
+	session.Details = details
+	return a.withoutJournal(func() error {
+		return a.storeCompletedSession(session, records)
+	})
+}
+
+// storeCompletedSession writes a completed session and everything derived from it
+func (a *App) storeCompletedSession(session *PracticeSession, records []QuestionRecord) error {
+	if err := a.db.CreatePracticeSession(session); err != nil {
+		return fmt.Errorf("failed to save practice session: %v", err)
+	}
+
+	for _, record := range records {
+		if record.IsCorrect {
+			continue
+		}
+		if session.Mode == PracticeModeFlashcard {
+			// Graded cards had their lapse counted when they were graded and ungraded
+			// cards were never answered
+			if record.Grade != "" {
+				a.addWrongQuestionFromSession(record.QuestionID, wrongQuestionPriority(record.Confidence), false)
+			}
+			continue
+		}
+		a.addWrongQuestionFromSession(record.QuestionID, wrongQuestionPriority(record.Confidence), true)
+	}
+
+	a.StartCalibration(CalibrationModel2PL)
+	a.recordReadiness(session)
+	a.recordExposures(session)
+	a.refreshStudyPlans()
+	a.evaluateGoals()
+	return nil
+}
+
+// wrongQuestionPriority returns the review priority of a question answered wrong with
+// the given confidence
+func wrongQuestionPriority(confidence string) int {
+	if confidence == ConfidenceSure {
+		return WrongPriorityConfidentlyWrong
+	}
+	return WrongPriorityNormal
+}
+
+// addWrongQuestionFromSession adds a question answered incorrectly in a session to the
+// wrong questions. When it is already there and countLapse is set, a lapse is counted
+// and its priority raised.
+func (a *App) addWrongQuestionFromSession(questionID string, priority int, countLapse bool) {
+	// Template variants are regenerated rather than reviewed
+	if _, ok := templateIDForInstance(questionID); ok {
+		return
+	}
+
+	// Check if already exists
+	exists, err := a.db.IsQuestionMarkedWrong(questionID)
+	if err != nil {
+		return
+	}
+	if exists {
+		if !countLapse {
+			return
+		}
+		if err := a.db.RecordWrongQuestionLapse(questionID, priority); err != nil {
+			log.Printf("Warning: Failed to record wrong question lapse: %v", err)
+			return
+		}
+		a.detectLeech(questionID)
+		return
+	}
+
+	// Add to wrong questions
+	wrongQuestion := &WrongQuestion{
+		ID:         fmt.Sprintf("wrong_%d_%d", time.Now().UnixNano(), rand.Int63()),
+		QuestionID: questionID,
+		AddedAt:    a.now().Format(time.RFC3339),
+		Notes:      "Added from practice session",
+		Priority:   priority,
+	}
+	a.db.AddWrongQuestion(wrongQuestion)
+}
+
 // GetWrongQuestions returns all wrong questions
 func (a *App) GetWrongQuestions() ([]WrongQuestion, error) {
 	return a.db.GetWrongQuestions()
@@ -1628,17 +2203,23 @@ func (a *App) GetWrongQuestionsForPractice() ([]Question, error) {
 		}
 	}
 
-	return questions, nil
+	return a.withoutSuspended(questions)
 }
 
 // UpdateWrongQuestionReview updates a wrong question after review
 func (a *App) UpdateWrongQuestionReview(questionID string, isCorrect bool, notes string) error {
-	return a.db.UpdateWrongQuestionReview(questionID, isCorrect, notes)
+	if err := a.db.UpdateWrongQuestionReview(questionID, isCorrect, notes, a.now()); err != nil {
+		return err
+	}
+	a.detectLeech(questionID)
+	return nil
 }
 
 // RemoveWrongQuestion removes a question from the wrong questions list
 func (a *App) RemoveWrongQuestion(questionID string) error {
-	return a.db.RemoveWrongQuestion(questionID)
+	return a.journaled("Remove wrong question", wrongQuestionJournalScopes(questionID), func() error {
+		return a.db.RemoveWrongQuestion(questionID)
+	})
 }
 
 // IsQuestionMarkedWrong checks if a question is marked as wrong
@@ -1654,36 +2235,45 @@ func (a *App) ToggleWrongQuestion(questionID string, notes string) (bool, error)
 	}
 
 	if isMarked {
-		err = a.db.RemoveWrongQuestion(questionID)
+		err = a.RemoveWrongQuestion(questionID)
 		return false, err
 	} else {
-		wrongQuestion := &WrongQuestion{
-			ID:         fmt.Sprintf("wrong_%d_%d", time.Now().UnixNano(), rand.Int63()),
-			QuestionID: questionID,
-			AddedAt:    time.Now().Format(time.RFC3339),
-			Notes:      notes,
-		}
-		err = a.db.AddWrongQuestion(wrongQuestion)
+		err = a.AddWrongQuestion(questionID, notes)
 		return true, err
 	}
 }
 
 // UpdateQuestionGroup updates an existing question group
 func (a *App) UpdateQuestionGroup(group QuestionGroup) error {
+	// Reject parents that would make the group its own ancestor
+	if group.ParentID != nil && *group.ParentID != "" {
+		if err := a.checkParentGroup(*group.ParentID); err != nil {
+			return err
+		}
+		cycle, err := a.db.wouldCreateGroupCycle(group.ID, *group.ParentID)
+		if err != nil {
+			return fmt.Errorf("failed to check group hierarchy: %v", err)
+		}
+		if cycle {
+			return fmt.Errorf("cannot make a group its own ancestor")
+		}
+	} else {
+		group.ParentID = nil
+	}
+
 	// Set updated timestamp
-	group.UpdatedAt = time.Now().Format(time.RFC3339)
+	group.UpdatedAt = a.now().Format(time.RFC3339)
 	
 	// Update in database
-	if err := a.db.UpdateQuestionGroup(&group); err != nil {
-		return fmt.Errorf("failed to update question group: %v", err)
-	}
-	
-	return nil
+	scopes := []journalScope{{table: "question_groups", where: "id = ?", args: []interface{}{group.ID}}}
+	return a.journaled("Update group "+group.Name, scopes, func() error {
+		if err := a.db.UpdateQuestionGroup(&group); err != nil {
+			return fmt.Errorf("failed to update question group: %v", err)
+		}
+		return nil
+	})
 }
-// DeleteQuestionGroup deletes a question group by ID
+// DeleteQuestionGroup deletes a question group by ID, moving its child groups up to its parent
 func (a *App) DeleteQuestionGroup(groupID string) error {
-	if err := a.db.DeleteQuestionGroup(groupID); err != nil {
-		return fmt.Errorf("failed to delete question group: %v", err)
-	}
-	return nil
+	return a.DeleteQuestionGroupWithMode(groupID, GroupDeleteReparent)
 }
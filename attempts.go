@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// questionAttempt is a single answered question taken from a practice session's details
+type questionAttempt struct {
+	SessionID  string
+	QuestionID string // The parent template for answers to template variants
+	VariantID  string // The answered template variant, empty for stored questions
+	Mode       string
+	AnsweredAt time.Time
+	UserAnswer []string
+	IsCorrect  bool
+	TimeSpent  int
+	Marked     bool
+	Confidence string
+	Grade      string
+}
+
+// answered reports whether the question was answered, or self-graded on a flashcard,
+// rather than left open when its session finished
+func (attempt questionAttempt) answered() bool {
+	return len(attempt.UserAnswer) > 0 || attempt.Grade != ""
+}
+
+// sessionTime returns when a session took place, preferring its end time
+func sessionTime(session PracticeSession) time.Time {
+	candidates := []string{session.StartTime, session.CreatedAt}
+	if session.EndTime != nil {
+		candidates = append([]string{*session.EndTime}, candidates...)
+	}
+	for _, value := range candidates {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// parseSessionAttempts extracts the question records stored in a session's details
+func parseSessionAttempts(session PracticeSession) []questionAttempt {
+	if len(session.Details) == 0 {
+		return nil
+	}
+
+	var records []QuestionRecord
+	if err := json.Unmarshal(session.Details, &records); err != nil {
+		return nil
+	}
+
+	answeredAt := sessionTime(session)
+	attempts := make([]questionAttempt, 0, len(records))
+	for _, record := range records {
+		if record.QuestionID == "" {
+			continue
+		}
+		questionID, variantID := record.QuestionID, ""
+		if templateID, ok := templateIDForInstance(record.QuestionID); ok {
+			questionID, variantID = templateID, record.QuestionID
+		}
+		attempts = append(attempts, questionAttempt{
+			SessionID:  session.ID,
+			QuestionID: questionID,
+			VariantID:  variantID,
+			Mode:       session.Mode,
+			AnsweredAt: answeredAt,
+			UserAnswer: record.UserAnswer,
+			IsCorrect:  record.IsCorrect,
+			TimeSpent:  record.TimeSpent,
+			Marked:     record.Marked,
+			Confidence: record.Confidence,
+			Grade:      record.Grade,
+		})
+	}
+	return attempts
+}
+
+// GetQuestionAttempts returns every recorded answer from all practice sessions, oldest first
+func (d *Database) GetQuestionAttempts() ([]questionAttempt, error) {
+	sessions, err := d.GetPracticeSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	var attempts []questionAttempt
+	for _, session := range sessions {
+		attempts = append(attempts, parseSessionAttempts(session)...)
+	}
+	sort.SliceStable(attempts, func(i, j int) bool {
+		return attempts[i].AnsweredAt.Before(attempts[j].AnsweredAt)
+	})
+	return attempts, nil
+}
+
+// scoredAttempts drops flashcard self-grades, leaving the answers that were graded
+// against the key
+func scoredAttempts(attempts []questionAttempt) []questionAttempt {
+	scored := make([]questionAttempt, 0, len(attempts))
+	for _, attempt := range attempts {
+		if attempt.Mode != PracticeModeFlashcard {
+			scored = append(scored, attempt)
+		}
+	}
+	return scored
+}
+
+// groupAttemptsByQuestion indexes attempts by question ID, keeping their order
+func groupAttemptsByQuestion(attempts []questionAttempt) map[string][]questionAttempt {
+	byQuestion := make(map[string][]questionAttempt)
+	for _, attempt := range attempts {
+		byQuestion[attempt.QuestionID] = append(byQuestion[attempt.QuestionID], attempt)
+	}
+	return byQuestion
+}
+
+// attemptStat is a per-question statistic that answers are folded into
+type attemptStat interface {
+	add(correct bool, at time.Time)
+}
+
+// attemptCache is a table of per-question statistics kept up to date as sessions are
+// saved, so reports need not replay the whole history
+type attemptCache[S attemptStat] struct {
+	name  string
+	table string
+	load  func() (map[string]S, error)
+	save  func(map[string]S) error
+	fresh func() S
+}
+
+// fold adds attempts to stats and returns the statistics they changed
+func (c attemptCache[S]) fold(stats map[string]S, attempts []questionAttempt) map[string]S {
+	changed := make(map[string]S)
+	for _, attempt := range attempts {
+		stat, ok := stats[attempt.QuestionID]
+		if !ok {
+			stat = c.fresh()
+			stats[attempt.QuestionID] = stat
+		}
+		stat.add(attempt.IsCorrect, attempt.AnsweredAt)
+		changed[attempt.QuestionID] = stat
+	}
+	return changed
+}
+
+// record folds the questions of a saved session into the cache, touching only those
+// questions. The cache is built from the whole history the first time and leaves out
+// flashcard self-grades. Failures are logged since the session has been saved.
+func (c attemptCache[S]) record(a *App, session *PracticeSession) {
+	stats, err := c.load()
+	if err != nil {
+		log.Printf("Warning: Failed to load %s: %v", c.name, err)
+		return
+	}
+
+	var attempts []questionAttempt
+	if len(stats) == 0 {
+		if attempts, err = a.db.GetQuestionAttempts(); err != nil {
+			log.Printf("Warning: Failed to get question attempts: %v", err)
+			return
+		}
+	} else {
+		attempts = parseSessionAttempts(*session)
+	}
+	if err := c.save(c.fold(stats, scoredAttempts(attempts))); err != nil {
+		log.Printf("Warning: Failed to save %s: %v", c.name, err)
+	}
+}
+
+// rebuild replaces the cache with statistics built from the given history
+func (c attemptCache[S]) rebuild(d *Database, attempts []questionAttempt) error {
+	if _, err := d.db.Exec("DELETE FROM " + c.table); err != nil {
+		return fmt.Errorf("failed to clear %s: %v", c.name, err)
+	}
+	if err := c.save(c.fold(make(map[string]S), attempts)); err != nil {
+		return fmt.Errorf("failed to save %s: %v", c.name, err)
+	}
+	return nil
+}
+
+// rebuildAttemptCaches rebuilds the readiness statistics and exposures from the whole
+// history, for changes to it that saving sessions one at a time does not cover such as
+// undo and import. Failures are logged since the change itself has been made.
+func (a *App) rebuildAttemptCaches() {
+	attempts, err := a.db.GetQuestionAttempts()
+	if err != nil {
+		log.Printf("Warning: Failed to get question attempts: %v", err)
+		return
+	}
+	attempts = scoredAttempts(attempts)
+	if err := a.readinessCache().rebuild(a.db, attempts); err != nil {
+		log.Printf("Warning: Failed to rebuild readiness statistics: %v", err)
+	}
+	if err := a.exposureCache().rebuild(a.db, attempts); err != nil {
+		log.Printf("Warning: Failed to rebuild question exposures: %v", err)
+	}
+}
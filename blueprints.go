@@ -0,0 +1,440 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultAvoidRecentDays is how long answered questions are kept out of generated exams
+const defaultAvoidRecentDays = 7
+
+// apportion splits total into integer shares proportional to weights using the
+// largest remainder method, so the shares always add up to total
+func apportion(total int, weights []float64) []int {
+	shares := make([]int, len(weights))
+	sum := 0.0
+	for _, w := range weights {
+		if w > 0 {
+			sum += w
+		}
+	}
+	if total <= 0 || sum == 0 {
+		return shares
+	}
+
+	type remainder struct {
+		index int
+		value float64
+	}
+	remainders := make([]remainder, 0, len(weights))
+	assigned := 0
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		exact := float64(total) * w / sum
+		shares[i] = int(math.Floor(exact))
+		assigned += shares[i]
+		remainders = append(remainders, remainder{i, exact - float64(shares[i])})
+	}
+	sort.SliceStable(remainders, func(i, j int) bool { return remainders[i].value > remainders[j].value })
+	for i := 0; assigned < total; i++ {
+		shares[remainders[i%len(remainders)].index]++
+		assigned++
+	}
+	return shares
+}
+
+// sectionCounts returns the number of questions each section asks for
+func sectionCounts(blueprint ExamBlueprint) []int {
+	counts := make([]int, len(blueprint.Sections))
+	fixed := 0
+	weights := make([]float64, len(blueprint.Sections))
+	for i, section := range blueprint.Sections {
+		if section.Count > 0 {
+			counts[i] = section.Count
+			fixed += section.Count
+		} else {
+			weights[i] = section.Weight
+		}
+	}
+
+	weighted := apportion(blueprint.TotalQuestions-fixed, weights)
+	for i := range counts {
+		counts[i] += weighted[i]
+	}
+	return counts
+}
+
+// validateBlueprint checks a blueprint before it is stored
+func validateBlueprint(blueprint ExamBlueprint) error {
+	if strings.TrimSpace(blueprint.Name) == "" {
+		return fmt.Errorf("blueprint name is required")
+	}
+	if len(blueprint.Sections) == 0 {
+		return fmt.Errorf("blueprint needs at least one section")
+	}
+
+	fixed := 0
+	weight := 0.0
+	for i, section := range blueprint.Sections {
+		if section.Count < 0 || section.Weight < 0 || section.TimeLimit < 0 {
+			return fmt.Errorf("section %d: counts, weights and time limits cannot be negative", i+1)
+		}
+		if section.Count == 0 && section.Weight == 0 {
+			return fmt.Errorf("section %d: needs a count or a weight", i+1)
+		}
+		for level, w := range section.Difficulty {
+			if level < 1 || level > 5 || w < 0 {
+				return fmt.Errorf("section %d: difficulty weights need levels 1-5 and non-negative weights", i+1)
+			}
+		}
+		fixed += section.Count
+		if section.Count == 0 {
+			weight += section.Weight
+		}
+	}
+	for level, w := range blueprint.Difficulty {
+		if level < 1 || level > 5 || w < 0 {
+			return fmt.Errorf("difficulty weights need levels 1-5 and non-negative weights")
+		}
+	}
+	if blueprint.PassingScore < 0 || blueprint.PassingScore > 100 {
+		return fmt.Errorf("passing score must be between 0 and 100")
+	}
+	if weight > 100.0001 {
+		return fmt.Errorf("section weights add up to %.1f%%, more than 100%%", weight)
+	}
+	if weight > 0 && blueprint.TotalQuestions <= fixed {
+		return fmt.Errorf("weighted sections need a total question count above the fixed section counts")
+	}
+	return nil
+}
+
+// Blueprint database methods
+
+// SaveExamBlueprint inserts or replaces a blueprint
+func (d *Database) SaveExamBlueprint(blueprint *ExamBlueprint) error {
+	definition, err := json.Marshal(blueprint)
+	if err != nil {
+		return err
+	}
+	_, err = d.db.Exec(`INSERT INTO exam_blueprints (id, name, description, definition, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?)
+			  ON CONFLICT(id) DO UPDATE SET name = excluded.name, description = excluded.description,
+			  definition = excluded.definition, updated_at = excluded.updated_at`,
+		blueprint.ID, blueprint.Name, blueprint.Description, string(definition), blueprint.CreatedAt, blueprint.UpdatedAt)
+	return err
+}
+
+// GetExamBlueprint returns a blueprint by ID
+func (d *Database) GetExamBlueprint(id string) (*ExamBlueprint, error) {
+	var definition string
+	err := d.db.QueryRow(`SELECT definition FROM exam_blueprints WHERE id = ?`, id).Scan(&definition)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("blueprint %s not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var blueprint ExamBlueprint
+	if err := json.Unmarshal([]byte(definition), &blueprint); err != nil {
+		return nil, fmt.Errorf("failed to parse blueprint: %v", err)
+	}
+	return &blueprint, nil
+}
+
+// GetExamBlueprints returns all blueprints ordered by name
+func (d *Database) GetExamBlueprints() ([]ExamBlueprint, error) {
+	rows, err := d.db.Query(`SELECT definition FROM exam_blueprints ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blueprints := make([]ExamBlueprint, 0)
+	for rows.Next() {
+		var definition string
+		if err := rows.Scan(&definition); err != nil {
+			return nil, err
+		}
+		var blueprint ExamBlueprint
+		if err := json.Unmarshal([]byte(definition), &blueprint); err != nil {
+			return nil, fmt.Errorf("failed to parse blueprint: %v", err)
+		}
+		blueprints = append(blueprints, blueprint)
+	}
+	return blueprints, rows.Err()
+}
+
+// DeleteExamBlueprint deletes a blueprint
+func (d *Database) DeleteExamBlueprint(id string) error {
+	_, err := d.db.Exec(`DELETE FROM exam_blueprints WHERE id = ?`, id)
+	return err
+}
+
+// Blueprint methods
+
+// CreateExamBlueprint validates and stores a new exam blueprint
+func (a *App) CreateExamBlueprint(blueprint ExamBlueprint) (*ExamBlueprint, error) {
+	if err := validateBlueprint(blueprint); err != nil {
+		return nil, fmt.Errorf("invalid blueprint: %v", err)
+	}
+	blueprint.ID = fmt.Sprintf("blueprint_%d_%d", time.Now().UnixNano(), rand.Int63())
+	blueprint.CreatedAt = a.now().Format(time.RFC3339)
+	blueprint.UpdatedAt = blueprint.CreatedAt
+
+	scopes := []journalScope{{table: "exam_blueprints", where: "id = ?", args: []interface{}{blueprint.ID}}}
+	err := a.journaled("Create blueprint "+blueprint.Name, scopes, func() error {
+		if err := a.db.SaveExamBlueprint(&blueprint); err != nil {
+			return fmt.Errorf("failed to save blueprint: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &blueprint, nil
+}
+
+// UpdateExamBlueprint validates and replaces an existing blueprint
+func (a *App) UpdateExamBlueprint(blueprint ExamBlueprint) error {
+	if err := validateBlueprint(blueprint); err != nil {
+		return fmt.Errorf("invalid blueprint: %v", err)
+	}
+	existing, err := a.db.GetExamBlueprint(blueprint.ID)
+	if err != nil {
+		return err
+	}
+	blueprint.CreatedAt = existing.CreatedAt
+	blueprint.UpdatedAt = a.now().Format(time.RFC3339)
+
+	scopes := []journalScope{{table: "exam_blueprints", where: "id = ?", args: []interface{}{blueprint.ID}}}
+	return a.journaled("Update blueprint "+blueprint.Name, scopes, func() error {
+		if err := a.db.SaveExamBlueprint(&blueprint); err != nil {
+			return fmt.Errorf("failed to save blueprint: %v", err)
+		}
+		return nil
+	})
+}
+
+// GetExamBlueprints returns all exam blueprints
+func (a *App) GetExamBlueprints() ([]ExamBlueprint, error) {
+	return a.db.GetExamBlueprints()
+}
+
+// GetExamBlueprint returns an exam blueprint by ID
+func (a *App) GetExamBlueprint(id string) (*ExamBlueprint, error) {
+	return a.db.GetExamBlueprint(id)
+}
+
+// DeleteExamBlueprint deletes an exam blueprint
+func (a *App) DeleteExamBlueprint(id string) error {
+	scopes := []journalScope{{table: "exam_blueprints", where: "id = ?", args: []interface{}{id}}}
+	return a.journaled("Delete blueprint", scopes, func() error {
+		if err := a.db.DeleteExamBlueprint(id); err != nil {
+			return fmt.Errorf("failed to delete blueprint: %v", err)
+		}
+		return nil
+	})
+}
+
+// blueprintSectionPool returns the candidate questions of a section
+func (a *App) blueprintSectionPool(section BlueprintSection) ([]Question, error) {
+	questions, err := a.practiceQuestions(section.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	tag := normalizeTagPath(section.Tag)
+	if tag == "" {
+		return questions, nil
+	}
+
+	pool := make([]Question, 0, len(questions))
+	for _, q := range questions {
+		for _, t := range parseQuestionTags(q.Tags) {
+			if isTagPathWithin(t, tag) {
+				pool = append(pool, q)
+				break
+			}
+		}
+	}
+	return pool, nil
+}
+
+// GenerateExamFromBlueprint samples a mock exam that follows a blueprint's section and
+// difficulty quotas. Questions answered within the blueprint's recent window are only
+// used when nothing else is left, and quotas the bank cannot fill are reported as
+// shortfalls. The least answered questions are picked first when the
+// preferUnderexposed setting is on.
+func (a *App) GenerateExamFromBlueprint(blueprintID string) (*GeneratedExam, error) {
+	blueprint, err := a.db.GetExamBlueprint(blueprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	avoidDays := blueprint.AvoidRecentDays
+	if avoidDays == 0 {
+		avoidDays = defaultAvoidRecentDays
+	}
+	recent := make(map[string]bool)
+	if avoidDays > 0 {
+		attempts, err := a.db.GetQuestionAttempts()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load attempts: %v", err)
+		}
+		since := a.now().AddDate(0, 0, -avoidDays)
+		for _, attempt := range attempts {
+			if !attempt.AnsweredAt.Before(since) {
+				recent[attempt.QuestionID] = true
+			}
+		}
+	}
+
+	exam := &GeneratedExam{
+		BlueprintID: blueprint.ID,
+		Name:        blueprint.Name,
+		TimeLimit:   blueprint.TimeLimit,
+		Sections:    []GeneratedExamSection{},
+		Shortfalls:  []BlueprintShortfall{},
+		GeneratedAt: a.now().Format(time.RFC3339),
+	}
+	used := make(map[string]bool)
+	sectionTime := 0
+
+	for i, count := range sectionCounts(*blueprint) {
+		section := blueprint.Sections[i]
+		name := section.Name
+		if name == "" {
+			name = fmt.Sprintf("Section %d", i+1)
+		}
+
+		pool, err := a.blueprintSectionPool(section)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get questions for section %s: %v", name, err)
+		}
+
+		// Shuffle, then move recently seen questions to the back so they are used last
+		candidates := make([]Question, 0, len(pool))
+		for _, q := range pool {
+			if !used[q.ID] {
+				candidates = append(candidates, q)
+			}
+		}
+		rand.Shuffle(len(candidates), func(x, y int) { candidates[x], candidates[y] = candidates[y], candidates[x] })
+		a.preferUnderexposed(candidates)
+		sort.SliceStable(candidates, func(x, y int) bool { return !recent[candidates[x].ID] && recent[candidates[y].ID] })
+
+		firstShortfall := len(exam.Shortfalls)
+		generated := GeneratedExamSection{Name: name, TimeLimit: section.TimeLimit, Requested: count, Questions: []Question{}}
+		take := func(q Question) {
+			used[q.ID] = true
+			if recent[q.ID] {
+				generated.RecentlySeen++
+			}
+			generated.Questions = append(generated.Questions, q)
+		}
+
+		weights := section.Difficulty
+		if len(weights) == 0 {
+			weights = blueprint.Difficulty
+		}
+		if len(weights) > 0 {
+			levels := []int{1, 2, 3, 4, 5}
+			levelWeights := make([]float64, len(levels))
+			for l, level := range levels {
+				levelWeights[l] = weights[level]
+			}
+			quotas := apportion(count, levelWeights)
+
+			for l, level := range levels {
+				available := 0
+				for _, q := range candidates {
+					if used[q.ID] || q.Difficulty == nil || *q.Difficulty != level {
+						continue
+					}
+					if available < quotas[l] {
+						take(q)
+					}
+					available++
+				}
+				if available < quotas[l] {
+					lvl := level
+					exam.Shortfalls = append(exam.Shortfalls, BlueprintShortfall{
+						Section: name, Difficulty: &lvl, Requested: quotas[l], Available: available,
+					})
+				}
+			}
+
+			// Fill difficulty shortfalls with the closest available difficulties
+			missing := count - len(generated.Questions)
+			if missing > 0 {
+				target := 0.0
+				for l, level := range levels {
+					target += float64(level*quotas[l]) / float64(count)
+				}
+				sort.SliceStable(candidates, func(x, y int) bool {
+					if recent[candidates[x].ID] != recent[candidates[y].ID] {
+						return !recent[candidates[x].ID]
+					}
+					return difficultyDistance(candidates[x], target) < difficultyDistance(candidates[y], target)
+				})
+				substituted := 0
+				for _, q := range candidates {
+					if substituted == missing {
+						break
+					}
+					if !used[q.ID] {
+						take(q)
+						substituted++
+					}
+				}
+				for s := firstShortfall; s < len(exam.Shortfalls) && substituted > 0; s++ {
+					n := exam.Shortfalls[s].Requested - exam.Shortfalls[s].Available
+					if n > substituted {
+						n = substituted
+					}
+					exam.Shortfalls[s].Substituted = n
+					substituted -= n
+				}
+			}
+		} else {
+			for _, q := range candidates {
+				if len(generated.Questions) == count {
+					break
+				}
+				take(q)
+			}
+		}
+
+		if len(generated.Questions) < count {
+			exam.Shortfalls = append(exam.Shortfalls, BlueprintShortfall{
+				Section: name, Requested: count, Available: len(generated.Questions),
+			})
+		}
+		exam.TotalQuestions += len(generated.Questions)
+		sectionTime += section.TimeLimit
+		exam.Sections = append(exam.Sections, generated)
+	}
+
+	if exam.TimeLimit == 0 {
+		exam.TimeLimit = sectionTime
+	}
+	return exam, nil
+}
+
+// difficultyDistance is how far a question's difficulty is from a target level;
+// questions without a difficulty count as medium
+func difficultyDistance(q Question, target float64) float64 {
+	level := 3.0
+	if q.Difficulty != nil {
+		level = float64(*q.Difficulty)
+	}
+	return math.Abs(level - target)
+}
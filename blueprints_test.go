@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestApportion tests that shares follow the weights and add up to the total
+func TestApportion(t *testing.T) {
+	shares := apportion(10, []float64{20, 15, 10, 0})
+	if shares[0]+shares[1]+shares[2] != 10 || shares[3] != 0 || shares[0] != 5 {
+		t.Errorf("Unexpected shares: %v", shares)
+	}
+}
+
+// TestGenerateExamFromBlueprint tests quotas, shortfalls and avoidance of recent questions
+func TestGenerateExamFromBlueprint(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	data := []map[string]interface{}{
+		{"question": "C1", "options": []string{"a"}, "answer": []string{"a"}, "tags": []string{"Cardiology"}, "difficulty": float64(2)},
+		{"question": "C2", "options": []string{"a"}, "answer": []string{"a"}, "tags": []string{"Cardiology/ECG"}, "difficulty": float64(2)},
+		{"question": "C3", "options": []string{"a"}, "answer": []string{"a"}, "tags": []string{"Cardiology"}, "difficulty": float64(3)},
+		{"question": "C4", "options": []string{"a"}, "answer": []string{"a"}, "tags": []string{"Cardiology"}, "difficulty": float64(2)},
+		{"question": "S1", "options": []string{"a"}, "answer": []string{"a"}, "tags": []string{"Statistics"}, "difficulty": float64(3)},
+	}
+	app.ImportQuestions(data, "")
+	questions, _ := app.GetQuestions()
+	ids := make(map[string]string)
+	for _, q := range questions {
+		ids[q.Question] = q.ID
+	}
+
+	// C1 was answered yesterday
+	details, _ := json.Marshal([]map[string]interface{}{{"questionId": ids["C1"], "isCorrect": true}})
+	at := time.Now().AddDate(0, 0, -1).Format(time.RFC3339)
+	db.CreatePracticeSession(&PracticeSession{ID: "recent", Mode: "practice", StartTime: at, TotalQuestions: 1, Details: details, CreatedAt: at})
+
+	if _, err := app.CreateExamBlueprint(ExamBlueprint{Name: "Empty"}); err == nil {
+		t.Error("Expected a blueprint without sections to be rejected")
+	}
+
+	blueprint, err := app.CreateExamBlueprint(ExamBlueprint{
+		Name:           "Mock",
+		TotalQuestions: 5,
+		Sections: []BlueprintSection{
+			{Name: "Cardiology", Tag: "Cardiology", Weight: 60, TimeLimit: 600, Difficulty: map[int]float64{2: 1, 4: 1}},
+			{Name: "Statistics", Tag: "Statistics", Weight: 40, TimeLimit: 300},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create blueprint: %v", err)
+	}
+
+	exam, err := app.GenerateExamFromBlueprint(blueprint.ID)
+	if err != nil {
+		t.Fatalf("Failed to generate exam: %v", err)
+	}
+	if len(exam.Sections) != 2 || exam.TimeLimit != 900 {
+		t.Fatalf("Unexpected exam: %+v", exam)
+	}
+
+	cardiology := exam.Sections[0]
+	if cardiology.Requested != 3 || len(cardiology.Questions) != 3 || cardiology.RecentlySeen != 0 {
+		t.Errorf("Expected 3 unseen cardiology questions, got %+v", cardiology)
+	}
+	for _, q := range cardiology.Questions {
+		if q.ID == ids["C1"] {
+			t.Error("Expected the recently answered question to be avoided")
+		}
+	}
+
+	// Difficulty 4 has no questions and statistics has only one question
+	var difficultyShortfall, sectionShortfall *BlueprintShortfall
+	for i, s := range exam.Shortfalls {
+		if s.Difficulty != nil && *s.Difficulty == 4 {
+			difficultyShortfall = &exam.Shortfalls[i]
+		}
+		if s.Difficulty == nil && s.Section == "Statistics" {
+			sectionShortfall = &exam.Shortfalls[i]
+		}
+	}
+	if difficultyShortfall == nil || difficultyShortfall.Requested != 1 || difficultyShortfall.Substituted != 1 {
+		t.Errorf("Expected a substituted difficulty 4 shortfall, got %+v", exam.Shortfalls)
+	}
+	if sectionShortfall == nil || sectionShortfall.Requested != 2 || sectionShortfall.Available != 1 {
+		t.Errorf("Expected a statistics shortfall, got %+v", exam.Shortfalls)
+	}
+	if exam.TotalQuestions != 4 {
+		t.Errorf("Expected 4 questions in total, got %d", exam.TotalQuestions)
+	}
+}
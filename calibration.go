@@ -0,0 +1,548 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+)
+
+// Calibration tuning. Priors keep estimates finite for items and sessions with
+// all-correct or all-wrong answers.
+const (
+	calibrationMaxIterations   = 100
+	calibrationTolerance       = 1e-4
+	calibrationAbilityPrior    = 1.0 // Standard deviation of session abilities
+	calibrationDifficultyPrior = 2.0 // Standard deviation of item difficulties
+	calibrationLogSlopePrior   = 0.5 // Standard deviation of log discrimination
+	calibrationParamLimit      = 6.0
+	minAttemptsFor2PL          = 10              // Items with fewer answers are fitted with a fixed slope
+	minAttemptsForDifficulty   = 3               // Items with fewer answers keep their current 1-5 difficulty
+	calibrationDebounce        = 2 * time.Second // Wait before a background run started by saved sessions
+)
+
+// irtResponse is one scored answer of a session (person) to a question (item)
+type irtResponse struct {
+	person  int
+	item    int
+	correct bool
+}
+
+// irtItem is the fitted state of a single item
+type irtItem struct {
+	questionID    string
+	difficulty    float64
+	logSlope      float64
+	fitSlope      bool
+	difficultySE  float64
+	attempts      int
+	correct       int
+	responseIndex []int
+}
+
+// irtFit is the result of fitting a model to the attempt history
+type irtFit struct {
+	items      []irtItem
+	abilities  []float64
+	iterations int
+}
+
+// irtProbability returns the probability of a correct answer under the 2PL model
+func irtProbability(theta, difficulty, slope float64) float64 {
+	return 1 / (1 + math.Exp(-slope*(theta-difficulty)))
+}
+
+// clampIRT keeps parameters inside a finite range
+func clampIRT(value float64) float64 {
+	return math.Max(-calibrationParamLimit, math.Min(calibrationParamLimit, value))
+}
+
+// fitIRT fits a 1PL or 2PL model by joint maximum a posteriori estimation.
+// The app has a single learner, so each practice session is treated as its own
+// person whose ability may differ from day to day; this lets one bad session
+// lower that session's ability instead of every item's difficulty.
+func fitIRT(attempts []questionAttempt, model string) irtFit {
+	personIndex := make(map[string]int)
+	itemIndex := make(map[string]int)
+	var items []irtItem
+	var responses []irtResponse
+	personResponses := make(map[int][]int)
+
+	for _, attempt := range attempts {
+		p, ok := personIndex[attempt.SessionID]
+		if !ok {
+			p = len(personIndex)
+			personIndex[attempt.SessionID] = p
+		}
+		i, ok := itemIndex[attempt.QuestionID]
+		if !ok {
+			i = len(items)
+			itemIndex[attempt.QuestionID] = i
+			items = append(items, irtItem{questionID: attempt.QuestionID})
+		}
+		items[i].attempts++
+		if attempt.IsCorrect {
+			items[i].correct++
+		}
+		items[i].responseIndex = append(items[i].responseIndex, len(responses))
+		personResponses[p] = append(personResponses[p], len(responses))
+		responses = append(responses, irtResponse{person: p, item: i, correct: attempt.IsCorrect})
+	}
+
+	// Start difficulties from smoothed proportions correct
+	for i := range items {
+		pCorrect := (float64(items[i].correct) + 0.5) / (float64(items[i].attempts) + 1)
+		items[i].difficulty = clampIRT(-math.Log(pCorrect / (1 - pCorrect)))
+		items[i].fitSlope = model == CalibrationModel2PL && items[i].attempts >= minAttemptsFor2PL
+	}
+	abilities := make([]float64, len(personIndex))
+
+	fit := irtFit{items: items, abilities: abilities}
+	for fit.iterations < calibrationMaxIterations {
+		fit.iterations++
+		maxChange := 0.0
+
+		// Ability step for each session
+		for p := range abilities {
+			gradient := -abilities[p] / (calibrationAbilityPrior * calibrationAbilityPrior)
+			hessian := -1 / (calibrationAbilityPrior * calibrationAbilityPrior)
+			for _, r := range personResponses[p] {
+				item := items[responses[r].item]
+				slope := math.Exp(item.logSlope)
+				prob := irtProbability(abilities[p], item.difficulty, slope)
+				gradient += slope * (boolToFloat(responses[r].correct) - prob)
+				hessian -= slope * slope * prob * (1 - prob)
+			}
+			next := clampIRT(abilities[p] - gradient/hessian)
+			maxChange = math.Max(maxChange, math.Abs(next-abilities[p]))
+			abilities[p] = next
+		}
+
+		// Difficulty and discrimination steps for each item
+		for i := range items {
+			item := &items[i]
+			slope := math.Exp(item.logSlope)
+			gradient := -item.difficulty / (calibrationDifficultyPrior * calibrationDifficultyPrior)
+			hessian := -1 / (calibrationDifficultyPrior * calibrationDifficultyPrior)
+			for _, r := range item.responseIndex {
+				prob := irtProbability(abilities[responses[r].person], item.difficulty, slope)
+				gradient -= slope * (boolToFloat(responses[r].correct) - prob)
+				hessian -= slope * slope * prob * (1 - prob)
+			}
+			next := clampIRT(item.difficulty - gradient/hessian)
+			maxChange = math.Max(maxChange, math.Abs(next-item.difficulty))
+			item.difficulty = next
+			item.difficultySE = 1 / math.Sqrt(-hessian)
+
+			if !item.fitSlope {
+				continue
+			}
+			gradient = -item.logSlope / (calibrationLogSlopePrior * calibrationLogSlopePrior)
+			hessian = -1 / (calibrationLogSlopePrior * calibrationLogSlopePrior)
+			for _, r := range item.responseIndex {
+				distance := abilities[responses[r].person] - item.difficulty
+				prob := irtProbability(abilities[responses[r].person], item.difficulty, slope)
+				gradient += slope * distance * (boolToFloat(responses[r].correct) - prob)
+				hessian -= slope * slope * distance * distance * prob * (1 - prob)
+			}
+			next = math.Max(-2, math.Min(2, item.logSlope-gradient/hessian))
+			maxChange = math.Max(maxChange, math.Abs(next-item.logSlope))
+			item.logSlope = next
+		}
+
+		if maxChange < calibrationTolerance {
+			break
+		}
+	}
+	return fit
+}
+
+// estimateAbility computes the learner's current ability from every attempt, given
+// fixed item parameters. Returns the estimate and its standard error.
+func estimateAbility(attempts []questionAttempt, items map[string]ItemCalibration) (float64, float64) {
+	theta := 0.0
+	hessian := -1 / (calibrationAbilityPrior * calibrationAbilityPrior)
+	for iteration := 0; iteration < calibrationMaxIterations; iteration++ {
+		gradient := -theta / (calibrationAbilityPrior * calibrationAbilityPrior)
+		hessian = -1 / (calibrationAbilityPrior * calibrationAbilityPrior)
+		for _, attempt := range attempts {
+			item, ok := items[attempt.QuestionID]
+			if !ok {
+				continue
+			}
+			prob := irtProbability(theta, item.Difficulty, item.Discrimination)
+			gradient += item.Discrimination * (boolToFloat(attempt.IsCorrect) - prob)
+			hessian -= item.Discrimination * item.Discrimination * prob * (1 - prob)
+		}
+		next := clampIRT(theta - gradient/hessian)
+		done := math.Abs(next-theta) < calibrationTolerance
+		theta = next
+		if done {
+			break
+		}
+	}
+	return theta, 1 / math.Sqrt(-hessian)
+}
+
+// difficultyLevel maps a calibrated difficulty onto the 1-5 scale used by questions
+func difficultyLevel(b float64) int {
+	switch {
+	case b < -1.5:
+		return 1
+	case b < -0.5:
+		return 2
+	case b < 0.5:
+		return 3
+	case b < 1.5:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// boolToFloat converts a scored answer to 0 or 1
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// Calibration database methods
+
+// CreateCalibrationRun records the start of a calibration run
+func (d *Database) CreateCalibrationRun(run *CalibrationRun) error {
+	result, err := d.db.Exec(`INSERT INTO calibration_runs (model, status, started_at) VALUES (?, ?, ?)`,
+		run.Model, run.Status, run.StartedAt)
+	if err != nil {
+		return err
+	}
+	run.ID, err = result.LastInsertId()
+	return err
+}
+
+// FinishCalibrationRun stores the outcome of a calibration run
+func (d *Database) FinishCalibrationRun(run *CalibrationRun) error {
+	_, err := d.db.Exec(`UPDATE calibration_runs SET status = ?, items = ?, attempts = ?, iterations = ?,
+			  ability = ?, ability_se = ?, error = ?, finished_at = ? WHERE id = ?`,
+		run.Status, run.Items, run.Attempts, run.Iterations, run.Ability, run.AbilitySE, run.Error, run.FinishedAt, run.ID)
+	return err
+}
+
+// GetLatestCalibrationRun returns the most recent calibration run with the given status,
+// or of any status when status is empty. Returns nil if there is none.
+func (d *Database) GetLatestCalibrationRun(status string) (*CalibrationRun, error) {
+	var run CalibrationRun
+	var errorText sql.NullString
+	err := d.db.QueryRow(`SELECT id, model, status, items, attempts, iterations, ability, ability_se, error,
+			  +started_at, +finished_at FROM calibration_runs
+			  WHERE ? = '' OR status = ? ORDER BY id DESC LIMIT 1`, status, status).Scan(
+		&run.ID, &run.Model, &run.Status, &run.Items, &run.Attempts, &run.Iterations,
+		&run.Ability, &run.AbilitySE, &errorText, &run.StartedAt, &run.FinishedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	run.Error = errorText.String
+	return &run, nil
+}
+
+// ReplaceItemCalibrations replaces all stored item parameters in one transaction
+func (d *Database) ReplaceItemCalibrations(items []ItemCalibration) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM item_calibrations`); err != nil {
+		return err
+	}
+	for _, item := range items {
+		_, err := tx.Exec(`INSERT INTO item_calibrations (question_id, model, difficulty, discrimination, difficulty_se, attempts, correct, calibrated_at)
+				  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			item.QuestionID, item.Model, item.Difficulty, item.Discrimination, item.DifficultySE,
+			item.Attempts, item.Correct, item.CalibratedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+const itemCalibrationColumns = `question_id, model, difficulty, discrimination, COALESCE(difficulty_se, 0),
+			  attempts, correct, +calibrated_at`
+
+// scanItemCalibration scans a row selected with itemCalibrationColumns
+func scanItemCalibration(scanner interface{ Scan(...interface{}) error }) (ItemCalibration, error) {
+	var item ItemCalibration
+	err := scanner.Scan(&item.QuestionID, &item.Model, &item.Difficulty, &item.Discrimination,
+		&item.DifficultySE, &item.Attempts, &item.Correct, &item.CalibratedAt)
+	return item, err
+}
+
+// GetItemCalibrations returns the stored parameters of every calibrated question
+func (d *Database) GetItemCalibrations() ([]ItemCalibration, error) {
+	rows, err := d.db.Query(`SELECT ` + itemCalibrationColumns + ` FROM item_calibrations ORDER BY difficulty`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]ItemCalibration, 0)
+	for rows.Next() {
+		item, err := scanItemCalibration(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// GetItemCalibration returns the stored parameters of a question, or nil if it has none
+func (d *Database) GetItemCalibration(questionID string) (*ItemCalibration, error) {
+	row := d.db.QueryRow(`SELECT `+itemCalibrationColumns+` FROM item_calibrations WHERE question_id = ?`, questionID)
+	item, err := scanItemCalibration(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Calibration methods
+
+// CalibrateDifficulties fits the IRT model over the whole attempt history, stores the
+// item parameters and learner ability, and derives each question's 1-5 difficulty
+// from its calibrated value. Model is CalibrationModel1PL or CalibrationModel2PL.
+func (a *App) CalibrateDifficulties(model string) (*CalibrationRun, error) {
+	if model == "" {
+		model = CalibrationModel2PL
+	}
+	if model != CalibrationModel1PL && model != CalibrationModel2PL {
+		return nil, fmt.Errorf("unknown calibration model %q", model)
+	}
+
+	a.calibrationRunMu.Lock()
+	defer a.calibrationRunMu.Unlock()
+
+	run := &CalibrationRun{
+		Model:     model,
+		Status:    CalibrationStatusRunning,
+		StartedAt: a.now().Format(time.RFC3339),
+	}
+	if err := a.withoutJournal(func() error { return a.db.CreateCalibrationRun(run) }); err != nil {
+		return nil, fmt.Errorf("failed to record calibration run: %v", err)
+	}
+
+	err := a.calibrate(run)
+	finishedAt := a.now().Format(time.RFC3339)
+	run.FinishedAt = &finishedAt
+	run.Status = CalibrationStatusCompleted
+	if err != nil {
+		run.Status = CalibrationStatusFailed
+		run.Error = err.Error()
+	}
+	if finishErr := a.withoutJournal(func() error { return a.db.FinishCalibrationRun(run) }); finishErr != nil {
+		return nil, fmt.Errorf("failed to record calibration run: %v", finishErr)
+	}
+	return run, err
+}
+
+// calibrate performs the fit for a run and applies the results
+func (a *App) calibrate(run *CalibrationRun) error {
+	attempts, err := a.db.GetQuestionAttempts()
+	if err != nil {
+		return fmt.Errorf("failed to load attempts: %v", err)
+	}
+
+	// Flashcard self-grades and answers to questions that were deleted since cannot be
+	// calibrated
+	questions, err := a.db.GetQuestions()
+	if err != nil {
+		return fmt.Errorf("failed to load questions: %v", err)
+	}
+	byID := make(map[string]Question, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
+	}
+	known := attempts[:0]
+	for _, attempt := range scoredAttempts(attempts) {
+		if _, ok := byID[attempt.QuestionID]; ok {
+			known = append(known, attempt)
+		}
+	}
+	attempts = known
+
+	fit := fitIRT(attempts, run.Model)
+	calibratedAt := a.now().Format(time.RFC3339)
+	items := make([]ItemCalibration, 0, len(fit.items))
+	byQuestion := make(map[string]ItemCalibration, len(fit.items))
+	for _, item := range fit.items {
+		model := CalibrationModel1PL
+		if item.fitSlope {
+			model = CalibrationModel2PL
+		}
+		calibration := ItemCalibration{
+			QuestionID:     item.questionID,
+			Model:          model,
+			Difficulty:     item.difficulty,
+			Discrimination: math.Exp(item.logSlope),
+			DifficultySE:   item.difficultySE,
+			Attempts:       item.attempts,
+			Correct:        item.correct,
+			CalibratedAt:   calibratedAt,
+		}
+		items = append(items, calibration)
+		byQuestion[item.questionID] = calibration
+	}
+
+	run.Items = len(items)
+	run.Attempts = len(attempts)
+	run.Iterations = fit.iterations
+	if len(attempts) > 0 {
+		theta, se := estimateAbility(attempts, byQuestion)
+		run.Ability = &theta
+		run.AbilitySE = &se
+	}
+	return a.withoutJournal(func() error {
+		return a.applyCalibration(items, byID)
+	})
+}
+
+// applyCalibration stores fitted items and derives the difficulty of questions from them
+func (a *App) applyCalibration(items []ItemCalibration, byID map[string]Question) error {
+	if err := a.db.ReplaceItemCalibrations(items); err != nil {
+		return fmt.Errorf("failed to store item calibrations: %v", err)
+	}
+
+	// Derive the 1-5 difficulty of sufficiently answered questions
+	sort.Slice(items, func(i, j int) bool { return items[i].QuestionID < items[j].QuestionID })
+	for _, item := range items {
+		if item.Attempts < minAttemptsForDifficulty {
+			continue
+		}
+		level := difficultyLevel(item.Difficulty)
+		current := byID[item.QuestionID].Difficulty
+		if current != nil && *current == level {
+			continue
+		}
+
+		if err := a.ensureQuestionRevisionBaseline(item.QuestionID); err != nil {
+			log.Printf("Warning: Failed to record baseline revision for question %s: %v", item.QuestionID, err)
+		}
+		if err := a.db.UpdateQuestionDifficulty(item.QuestionID, &level, a.now()); err != nil {
+			log.Printf("Warning: Failed to update difficulty for question %s: %v", item.QuestionID, err)
+			continue
+		}
+		reason := fmt.Sprintf("IRT calibration b=%.2f a=%.2f over %d attempts", item.Difficulty, item.Discrimination, item.Attempts)
+		if err := a.recordQuestionRevision(item.QuestionID, RevisionActorAutoDifficulty, reason); err != nil {
+			log.Printf("Warning: Failed to record revision for question %s: %v", item.QuestionID, err)
+		}
+	}
+	return nil
+}
+
+// StartCalibration runs the calibration in the background after calibrationDelay, so
+// requests arriving in the meantime share the run. If a run is already in progress,
+// another run is queued to start when it finishes.
+func (a *App) StartCalibration(model string) {
+	a.calibrationMu.Lock()
+	defer a.calibrationMu.Unlock()
+
+	a.calibrationModel = model
+	if a.calibrationRunning {
+		a.calibrationPending = true
+		return
+	}
+	a.calibrationRunning = true
+	a.calibrationWG.Add(1)
+	go a.calibrationLoop()
+}
+
+// calibrationLoop runs calibrations until no further run was requested
+func (a *App) calibrationLoop() {
+	defer a.calibrationWG.Done()
+	for {
+		time.Sleep(a.calibrationDelay)
+		a.calibrationMu.Lock()
+		model := a.calibrationModel
+		a.calibrationPending = false
+		a.calibrationMu.Unlock()
+
+		if _, err := a.CalibrateDifficulties(model); err != nil {
+			log.Printf("Warning: Calibration failed: %v", err)
+		}
+
+		a.calibrationMu.Lock()
+		if !a.calibrationPending {
+			a.calibrationRunning = false
+			a.calibrationMu.Unlock()
+			return
+		}
+		a.calibrationMu.Unlock()
+	}
+}
+
+// waitForCalibration blocks until background calibration has finished
+func (a *App) waitForCalibration() {
+	a.calibrationWG.Wait()
+}
+
+// GetCalibrationStatus returns the most recent calibration run, or nil if none ran yet
+func (a *App) GetCalibrationStatus() (*CalibrationRun, error) {
+	run, err := a.db.GetLatestCalibrationRun("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calibration status: %v", err)
+	}
+	return run, nil
+}
+
+// GetItemCalibrations returns the fitted IRT parameters of every calibrated question
+func (a *App) GetItemCalibrations() ([]ItemCalibration, error) {
+	items, err := a.db.GetItemCalibrations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item calibrations: %v", err)
+	}
+	return items, nil
+}
+
+// GetItemCalibration returns the fitted IRT parameters of a question, or nil if it has
+// not been calibrated yet
+func (a *App) GetItemCalibration(questionID string) (*ItemCalibration, error) {
+	item, err := a.db.GetItemCalibration(questionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item calibration: %v", err)
+	}
+	return item, nil
+}
+
+// GetLearnerAbility returns the ability estimate from the latest successful calibration,
+// or nil if none is available
+func (a *App) GetLearnerAbility() (*LearnerAbility, error) {
+	run, err := a.db.GetLatestCalibrationRun(CalibrationStatusCompleted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get learner ability: %v", err)
+	}
+	if run == nil || run.Ability == nil {
+		return nil, nil
+	}
+	ability := &LearnerAbility{
+		Theta:    *run.Ability,
+		Attempts: run.Attempts,
+	}
+	if run.AbilitySE != nil {
+		ability.StandardError = *run.AbilitySE
+	}
+	if run.FinishedAt != nil {
+		ability.EstimatedAt = *run.FinishedAt
+	}
+	return ability, nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// simulateAttempts answers each item once per session using the 2PL model
+func simulateAttempts(rng *rand.Rand, difficulties, abilities []float64) []questionAttempt {
+	var attempts []questionAttempt
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	for s, theta := range abilities {
+		for i, b := range difficulties {
+			attempts = append(attempts, questionAttempt{
+				SessionID:  fmt.Sprintf("s%d", s),
+				QuestionID: fmt.Sprintf("q%d", i),
+				AnsweredAt: start.AddDate(0, 0, s),
+				IsCorrect:  rng.Float64() < irtProbability(theta, b, 1),
+			})
+		}
+	}
+	return attempts
+}
+
+// TestFitIRTRecoversDifficultyOrder tests that fitted difficulties follow the simulated ones
+func TestFitIRTRecoversDifficultyOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	difficulties := []float64{-2, -1, 0, 1, 2}
+	abilities := make([]float64, 60)
+	for i := range abilities {
+		abilities[i] = rng.NormFloat64()
+	}
+
+	for _, model := range []string{CalibrationModel1PL, CalibrationModel2PL} {
+		fit := fitIRT(simulateAttempts(rng, difficulties, abilities), model)
+		if len(fit.items) != len(difficulties) {
+			t.Fatalf("%s: expected %d items, got %d", model, len(difficulties), len(fit.items))
+		}
+		for i := 1; i < len(fit.items); i++ {
+			if fit.items[i].difficulty <= fit.items[i-1].difficulty {
+				t.Errorf("%s: expected increasing difficulties, got %.2f then %.2f",
+					model, fit.items[i-1].difficulty, fit.items[i].difficulty)
+			}
+		}
+		if model == CalibrationModel2PL && !fit.items[0].fitSlope {
+			t.Errorf("Expected 2PL slopes for items with %d attempts", fit.items[0].attempts)
+		}
+	}
+}
+
+// TestCalibrateDifficulties tests stored parameters, derived difficulties and the background job
+func TestCalibrateDifficulties(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	data := []map[string]interface{}{
+		{"question": "Easy", "options": []string{"a"}, "answer": []string{"a"}, "difficulty": float64(3)},
+		{"question": "Hard", "options": []string{"a"}, "answer": []string{"a"}, "difficulty": float64(3)},
+	}
+	app.ImportQuestions(data, "")
+	questions, _ := app.GetQuestions()
+	ids := make(map[string]string)
+	for _, q := range questions {
+		ids[q.Question] = q.ID
+	}
+
+	// "Easy" is always answered correctly and "Hard" almost never
+	for s := 0; s < 8; s++ {
+		details, _ := json.Marshal([]map[string]interface{}{
+			{"questionId": ids["Easy"], "isCorrect": true},
+			{"questionId": ids["Hard"], "isCorrect": s == 0},
+		})
+		at := time.Now().AddDate(0, 0, s-8).Format(time.RFC3339)
+		session := &PracticeSession{ID: fmt.Sprintf("session-%d", s), Mode: "practice", StartTime: at,
+			EndTime: &at, TotalQuestions: 2, Details: details, CreatedAt: at}
+		if err := db.CreatePracticeSession(session); err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+	}
+
+	app.StartCalibration(CalibrationModel1PL)
+	app.waitForCalibration()
+
+	run, err := app.GetCalibrationStatus()
+	if err != nil || run == nil {
+		t.Fatalf("Expected a calibration run, got %v (%v)", run, err)
+	}
+	if run.Status != CalibrationStatusCompleted || run.Items != 2 || run.Attempts != 16 {
+		t.Errorf("Unexpected run: %+v", run)
+	}
+
+	easy, _ := app.GetItemCalibration(ids["Easy"])
+	hard, _ := app.GetItemCalibration(ids["Hard"])
+	if easy == nil || hard == nil {
+		t.Fatalf("Expected both questions to be calibrated")
+	}
+	if easy.Difficulty >= hard.Difficulty || easy.Model != CalibrationModel1PL || easy.Discrimination != 1 {
+		t.Errorf("Unexpected parameters: easy %+v, hard %+v", easy, hard)
+	}
+
+	easyQuestion, _ := db.GetQuestionByID(ids["Easy"])
+	hardQuestion, _ := db.GetQuestionByID(ids["Hard"])
+	if *easyQuestion.Difficulty >= 3 || *hardQuestion.Difficulty <= 3 {
+		t.Errorf("Expected derived difficulties below and above 3, got %d and %d",
+			*easyQuestion.Difficulty, *hardQuestion.Difficulty)
+	}
+
+	ability, err := app.GetLearnerAbility()
+	if err != nil || ability == nil || ability.Attempts != 16 {
+		t.Errorf("Expected ability over 16 attempts, got %+v (%v)", ability, err)
+	}
+
+	if _, err := app.CalibrateDifficulties("3pl"); err == nil {
+		t.Error("Expected unknown model to be rejected")
+	}
+
+	// Requests arriving while a run waits share it
+	var runs int
+	db.db.QueryRow(`SELECT COUNT(*) FROM calibration_runs`).Scan(&runs)
+	app.calibrationDelay = 50 * time.Millisecond
+	for i := 0; i < 3; i++ {
+		app.StartCalibration(CalibrationModel1PL)
+	}
+	app.waitForCalibration()
+	var after int
+	db.db.QueryRow(`SELECT COUNT(*) FROM calibration_runs`).Scan(&after)
+	if after != runs+1 {
+		t.Errorf("Expected one run for three requests, got %d", after-runs)
+	}
+
+	// A reset removes the calibrations and undoing it brings them back
+	if err := app.ResetAllData(); err != nil {
+		t.Fatalf("Failed to reset data: %v", err)
+	}
+	if items, _ := app.GetItemCalibrations(); len(items) != 0 {
+		t.Errorf("Expected no calibrations after reset, got %d", len(items))
+	}
+	if _, err := app.Undo(); err != nil {
+		t.Fatalf("Failed to undo reset: %v", err)
+	}
+	if items, _ := app.GetItemCalibrations(); len(items) != 2 {
+		t.Errorf("Expected the calibrations restored, got %d", len(items))
+	}
+
+	// Undoing a deleted question brings back its calibration
+	if err := app.DeleteQuestion(ids["Easy"]); err != nil {
+		t.Fatalf("Failed to delete question: %v", err)
+	}
+	if _, err := app.Undo(); err != nil {
+		t.Fatalf("Failed to undo delete: %v", err)
+	}
+	if item, _ := app.GetItemCalibration(ids["Easy"]); item == nil {
+		t.Error("Expected the calibration of the restored question")
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// confidenceLevels lists the confidence levels from least to most confident
+var confidenceLevels = []string{ConfidenceGuess, ConfidenceUnsure, ConfidenceSure}
+
+// isValidConfidence reports whether a confidence rating is known; empty means unrated
+func isValidConfidence(confidence string) bool {
+	if confidence == "" {
+		return true
+	}
+	for _, level := range confidenceLevels {
+		if confidence == level {
+			return true
+		}
+	}
+	return false
+}
+
+// weekStart returns the Monday starting the week of a time
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	year, month, day := t.AddDate(0, 0, -offset).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// GetConfidenceReport compares the confidence attached to answers with their results:
+// accuracy per confidence level, the questions answered wrong while sure, and a weekly
+// overconfidence trend. Unrated answers are left out.
+func (a *App) GetConfidenceReport() (*ConfidenceReport, error) {
+	attempts, err := a.db.GetQuestionAttempts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attempts: %v", err)
+	}
+
+	report := &ConfidenceReport{
+		Buckets:          []ConfidenceBucket{},
+		ConfidentlyWrong: []ConfidentlyWrongItem{},
+		Trend:            []ConfidenceTrendPoint{},
+	}
+	buckets := make(map[string]*ConfidenceBucket)
+	for _, level := range confidenceLevels {
+		buckets[level] = &ConfidenceBucket{Confidence: level}
+	}
+	wrong := make(map[string]*ConfidentlyWrongItem)
+	var wrongOrder []string
+	weeks := make(map[string]*ConfidenceTrendPoint)
+	sureCorrect := make(map[string]int)
+
+	for _, attempt := range attempts {
+		bucket, ok := buckets[attempt.Confidence]
+		if !ok {
+			continue
+		}
+		report.RatedAttempts++
+		bucket.Attempts++
+		if attempt.IsCorrect {
+			bucket.Correct++
+		}
+
+		week := weekStart(attempt.AnsweredAt).Format("2006-01-02")
+		point, ok := weeks[week]
+		if !ok {
+			point = &ConfidenceTrendPoint{WeekStart: week}
+			weeks[week] = point
+		}
+		point.RatedAttempts++
+		if attempt.Confidence != ConfidenceSure {
+			continue
+		}
+		point.SureAttempts++
+		if attempt.IsCorrect {
+			sureCorrect[week]++
+			continue
+		}
+
+		item, ok := wrong[attempt.QuestionID]
+		if !ok {
+			item = &ConfidentlyWrongItem{QuestionID: attempt.QuestionID}
+			wrong[attempt.QuestionID] = item
+			wrongOrder = append(wrongOrder, attempt.QuestionID)
+		}
+		item.Count++
+		item.LastAnsweredAt = attempt.AnsweredAt.Format(time.RFC3339)
+	}
+
+	for _, level := range confidenceLevels {
+		bucket := buckets[level]
+		if bucket.Attempts > 0 {
+			bucket.Accuracy = float64(bucket.Correct) / float64(bucket.Attempts)
+		}
+		report.Buckets = append(report.Buckets, *bucket)
+	}
+
+	for _, questionID := range wrongOrder {
+		item := wrong[questionID]
+		q, err := a.db.GetQuestionByID(questionID)
+		if err != nil {
+			continue // The question has been deleted since
+		}
+		item.Question = q.Question
+		item.Tags = q.Tags
+		report.ConfidentlyWrong = append(report.ConfidentlyWrong, *item)
+	}
+	sort.SliceStable(report.ConfidentlyWrong, func(i, j int) bool {
+		left, right := report.ConfidentlyWrong[i], report.ConfidentlyWrong[j]
+		if left.Count != right.Count {
+			return left.Count > right.Count
+		}
+		return left.LastAnsweredAt > right.LastAnsweredAt
+	})
+
+	for week, point := range weeks {
+		if point.SureAttempts > 0 {
+			point.SureAccuracy = float64(sureCorrect[week]) / float64(point.SureAttempts)
+			point.Overconfidence = 1 - point.SureAccuracy
+		}
+		report.Trend = append(report.Trend, *point)
+	}
+	sort.Slice(report.Trend, func(i, j int) bool {
+		return report.Trend[i].WeekStart < report.Trend[j].WeekStart
+	})
+	return report, nil
+}
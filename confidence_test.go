@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestConfidenceReport tests accuracy per confidence level, confidently wrong items and the trend
+func TestConfidenceReport(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	data := []map[string]interface{}{
+		{"question": "Q1", "options": []string{"A", "B"}, "answer": []string{"a"}},
+		{"question": "Q2", "options": []string{"A", "B"}, "answer": []string{"a"}},
+	}
+	app.ImportQuestions(data, "")
+	questions, _ := app.GetQuestions()
+	ids := make(map[string]string)
+	for _, q := range questions {
+		ids[q.Question] = q.ID
+	}
+
+	addSession := func(id, at string, records []QuestionRecord) {
+		details, _ := json.Marshal(records)
+		db.CreatePracticeSession(&PracticeSession{ID: id, Mode: "practice", StartTime: at, TotalQuestions: len(records), Details: details, CreatedAt: at})
+	}
+	// Week of 2025-06-02: sure answers are half wrong
+	addSession("s1", "2025-06-03T10:00:00Z", []QuestionRecord{
+		{QuestionID: ids["Q1"], IsCorrect: false, Confidence: ConfidenceSure},
+		{QuestionID: ids["Q2"], IsCorrect: true, Confidence: ConfidenceSure},
+		{QuestionID: ids["Q2"], IsCorrect: false, Confidence: ConfidenceGuess},
+		{QuestionID: ids["Q1"], IsCorrect: true},
+	})
+	// Week of 2025-06-09: sure answers are right, except Q1 again
+	addSession("s2", "2025-06-10T10:00:00Z", []QuestionRecord{
+		{QuestionID: ids["Q1"], IsCorrect: false, Confidence: ConfidenceSure},
+		{QuestionID: ids["Q2"], IsCorrect: true, Confidence: ConfidenceSure},
+		{QuestionID: ids["Q2"], IsCorrect: true, Confidence: ConfidenceSure},
+		{QuestionID: ids["Q2"], IsCorrect: true, Confidence: ConfidenceUnsure},
+	})
+
+	report, err := app.GetConfidenceReport()
+	if err != nil {
+		t.Fatalf("Failed to get confidence report: %v", err)
+	}
+	if report.RatedAttempts != 7 || len(report.Buckets) != 3 {
+		t.Fatalf("Unexpected report: %+v", report)
+	}
+	sure := report.Buckets[2]
+	if sure.Confidence != ConfidenceSure || sure.Attempts != 5 || sure.Correct != 3 {
+		t.Errorf("Unexpected sure bucket: %+v", sure)
+	}
+	if len(report.ConfidentlyWrong) != 1 || report.ConfidentlyWrong[0].Question != "Q1" || report.ConfidentlyWrong[0].Count != 2 {
+		t.Errorf("Expected Q1 confidently wrong twice, got %+v", report.ConfidentlyWrong)
+	}
+	if len(report.Trend) != 2 || report.Trend[0].WeekStart != "2025-06-02" ||
+		report.Trend[0].Overconfidence != 0.5 || report.Trend[1].SureAttempts != 3 {
+		t.Errorf("Unexpected trend: %+v", report.Trend)
+	}
+}
+
+// TestConfidentlyWrongPriority tests that answers wrong while sure are reviewed first
+func TestConfidentlyWrongPriority(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	data := []map[string]interface{}{
+		{"question": "Q1", "options": []string{"A", "B"}, "answer": []string{"a"}},
+		{"question": "Q2", "options": []string{"A", "B"}, "answer": []string{"a"}},
+	}
+	app.ImportQuestions(data, "")
+
+	session, _ := app.StartSession("", PracticeModeTest, nil)
+	ids := make(map[string]string)
+	for _, q := range session.Questions {
+		ids[q.Question] = q.ID
+	}
+	q1, q2 := ids["Q1"], ids["Q2"]
+	if _, err := app.SubmitSessionAnswer(session.ID, q1, []string{"b"}, 5, "certain"); err == nil {
+		t.Error("Expected an unknown confidence level to be rejected")
+	}
+	app.SubmitSessionAnswer(session.ID, q1, []string{"b"}, 5, ConfidenceGuess)
+	app.SubmitSessionAnswer(session.ID, q2, []string{"b"}, 5, ConfidenceSure)
+	if _, err := app.FinishSession(session.ID); err != nil {
+		t.Fatalf("Failed to finish session: %v", err)
+	}
+	app.waitForCalibration()
+
+	wrong, _ := app.GetWrongQuestions()
+	if len(wrong) != 2 || wrong[0].QuestionID != q2 || wrong[0].Priority != WrongPriorityConfidentlyWrong {
+		t.Fatalf("Expected the confidently wrong question first, got %+v", wrong)
+	}
+
+	// Answering Q1 wrong while sure later raises its priority
+	session, _ = app.StartSession("", PracticeModePractice, []string{q1})
+	app.SubmitSessionAnswer(session.ID, q1, []string{"b"}, 5, ConfidenceSure)
+	app.FinishSession(session.ID)
+	app.waitForCalibration()
+
+	wrong, _ = app.GetWrongQuestions()
+	for _, wq := range wrong {
+		if wq.Priority != WrongPriorityConfidentlyWrong {
+			t.Errorf("Expected both questions to have high priority, got %+v", wq)
+		}
+	}
+}
@@ -116,11 +116,165 @@ func (d *Database) migrate() error {
 			FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE,
 			UNIQUE(question_id)
 		)`,
+		`CREATE TABLE IF NOT EXISTS question_revisions (
+			id TEXT PRIMARY KEY,
+			question_id TEXT NOT NULL,
+			revision INTEGER NOT NULL,
+			actor TEXT NOT NULL,
+			reason TEXT,
+			snapshot JSON NOT NULL,
+			changes JSON,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(question_id, revision)
+		)`,
+		`CREATE TABLE IF NOT EXISTS operation_journal (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			label TEXT NOT NULL,
+			changes JSON NOT NULL,
+			undone BOOLEAN DEFAULT FALSE,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS item_calibrations (
+			question_id TEXT PRIMARY KEY,
+			model TEXT NOT NULL,
+			difficulty REAL NOT NULL,
+			discrimination REAL NOT NULL DEFAULT 1,
+			difficulty_se REAL DEFAULT 0,
+			attempts INTEGER DEFAULT 0,
+			correct INTEGER DEFAULT 0,
+			calibrated_at DATETIME NOT NULL,
+			FOREIGN KEY (question_id) REFERENCES questions(id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS calibration_runs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			model TEXT NOT NULL,
+			status TEXT NOT NULL,
+			items INTEGER DEFAULT 0,
+			attempts INTEGER DEFAULT 0,
+			iterations INTEGER DEFAULT 0,
+			ability REAL,
+			ability_se REAL,
+			error TEXT,
+			started_at DATETIME NOT NULL,
+			finished_at DATETIME
+		)`,
+		`CREATE TABLE IF NOT EXISTS adaptive_sessions (
+			id TEXT PRIMARY KEY,
+			status TEXT NOT NULL,
+			state JSON NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS exam_blueprints (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			description TEXT,
+			definition JSON NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS active_sessions (
+			id TEXT PRIMARY KEY,
+			status TEXT NOT NULL,
+			state JSON NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS session_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			session_id TEXT NOT NULL,
+			question_id TEXT NOT NULL DEFAULT '',
+			type TEXT NOT NULL,
+			from_answer JSON DEFAULT NULL,
+			to_answer JSON DEFAULT NULL,
+			from_correct BOOLEAN DEFAULT NULL,
+			to_correct BOOLEAN DEFAULT NULL,
+			at TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS question_templates (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			group_id TEXT DEFAULT '',
+			definition JSON NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS template_instances (
+			id TEXT PRIMARY KEY,
+			template_id TEXT NOT NULL,
+			seed INTEGER NOT NULL,
+			question JSON NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS question_suspensions (
+			question_id TEXT PRIMARY KEY,
+			reason TEXT NOT NULL,
+			suspended_at TEXT NOT NULL,
+			FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE
+		)`,
+		`CREATE TABLE IF NOT EXISTS study_plans (
+			id TEXT PRIMARY KEY,
+			status TEXT NOT NULL,
+			state JSON NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS study_goals (
+			id TEXT PRIMARY KEY,
+			period TEXT NOT NULL,
+			metric TEXT NOT NULL,
+			target REAL NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS achievements (
+			id TEXT PRIMARY KEY,
+			unlocked_at TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS readiness_stats (
+			question_id TEXT PRIMARY KEY,
+			weight REAL NOT NULL,
+			correct REAL NOT NULL,
+			attempts INTEGER NOT NULL,
+			last_at TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS question_exposures (
+			question_id TEXT PRIMARY KEY,
+			exposures INTEGER NOT NULL,
+			correct_streak INTEGER NOT NULL,
+			first_seen_at TEXT NOT NULL,
+			last_seen_at TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS timed_exams (
+			id TEXT PRIMARY KEY,
+			status TEXT NOT NULL,
+			state JSON NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS tags (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			parent_id TEXT,
+			path TEXT NOT NULL UNIQUE,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (parent_id) REFERENCES tags(id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS question_tags (
+			question_id TEXT,
+			tag_id TEXT,
+			PRIMARY KEY (question_id, tag_id),
+			FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE,
+			FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+		)`,
 		`CREATE INDEX IF NOT EXISTS idx_questions_created_at ON questions(created_at)`,
 		`CREATE INDEX IF NOT EXISTS idx_practice_sessions_group_id ON practice_sessions(group_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_practice_sessions_created_at ON practice_sessions(created_at)`,
 		`CREATE INDEX IF NOT EXISTS idx_wrong_questions_added_at ON wrong_questions(added_at)`,
 		`CREATE INDEX IF NOT EXISTS idx_wrong_questions_reviewed_at ON wrong_questions(reviewed_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_question_tags_tag_id ON question_tags(tag_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_tags_parent_id ON tags(parent_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_session_events_session_id ON session_events(session_id)`,
 	}
 
 	for _, query := range queries {
@@ -134,6 +288,51 @@ func (d *Database) migrate() error {
 		return fmt.Errorf("failed to add index column: %v", err)
 	}
 
+	if err := d.addGroupPositionColumnIfNotExists(); err != nil {
+		return fmt.Errorf("failed to add group position column: %v", err)
+	}
+
+	if err := d.addColumnIfNotExists("question_groups", "smart_query", "JSON DEFAULT NULL"); err != nil {
+		return err
+	}
+
+	if err := d.addColumnIfNotExists("practice_sessions", "section_timing", "JSON DEFAULT NULL"); err != nil {
+		return err
+	}
+
+	if err := d.addColumnIfNotExists("wrong_questions", "priority", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
+	if err := d.addColumnIfNotExists("wrong_questions", "ease", "REAL DEFAULT 2.5"); err != nil {
+		return err
+	}
+
+	if err := d.addColumnIfNotExists("wrong_questions", "interval_days", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
+	if err := d.addColumnIfNotExists("wrong_questions", "due_at", "TEXT DEFAULT NULL"); err != nil {
+		return err
+	}
+
+	if err := d.addColumnIfNotExists("wrong_questions", "lapses", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+
+	if err := d.addColumnIfNotExists("wrong_questions", "leech", "BOOLEAN DEFAULT FALSE"); err != nil {
+		return err
+	}
+
+	if err := d.addRelationPositionColumnIfNotExists(); err != nil {
+		return fmt.Errorf("failed to add group question position column: %v", err)
+	}
+
+	// Populate the tag tables from the legacy JSON tags column
+	if err := d.migrateQuestionTags(); err != nil {
+		return fmt.Errorf("failed to migrate question tags: %v", err)
+	}
+
 	return nil
 }
 
@@ -151,6 +350,59 @@ func (d *Database) addIndexColumnIfNotExists() error {
 	return nil
 }
 
+// addColumnIfNotExists safely adds a column to a table if it doesn't exist
+func (d *Database) addColumnIfNotExists(table, column, definition string) error {
+	// Check if the column exists by attempting to query it
+	_, err := d.db.Exec(fmt.Sprintf("SELECT %s FROM %s LIMIT 1", column, table))
+	if err != nil {
+		_, err = d.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+		if err != nil {
+			return fmt.Errorf("failed to add %s column to %s: %v", column, table, err)
+		}
+	}
+	return nil
+}
+
+// addGroupPositionColumnIfNotExists safely adds the sibling position column to question groups
+func (d *Database) addGroupPositionColumnIfNotExists() error {
+	_, err := d.db.Exec("SELECT position FROM question_groups LIMIT 1")
+	if err != nil {
+		_, err = d.db.Exec("ALTER TABLE question_groups ADD COLUMN position INTEGER DEFAULT 0")
+		if err != nil {
+			return fmt.Errorf("failed to add position column: %v", err)
+		}
+	}
+	return nil
+}
+
+// addRelationPositionColumnIfNotExists safely adds the per-group question position column,
+// seeding positions from the questions' global index
+func (d *Database) addRelationPositionColumnIfNotExists() error {
+	_, err := d.db.Exec("SELECT position FROM question_group_relations LIMIT 1")
+	if err == nil {
+		return nil
+	}
+
+	if _, err := d.db.Exec("ALTER TABLE question_group_relations ADD COLUMN position INTEGER DEFAULT NULL"); err != nil {
+		return fmt.Errorf("failed to add position column: %v", err)
+	}
+
+	_, err = d.db.Exec(`UPDATE question_group_relations SET position = (
+			SELECT ranked.rn FROM (
+				SELECT r.group_id, r.question_id,
+					ROW_NUMBER() OVER (PARTITION BY r.group_id ORDER BY COALESCE(q.[index], 999999), q.created_at DESC) - 1 AS rn
+				FROM question_group_relations r
+				JOIN questions q ON q.id = r.question_id
+			) ranked
+			WHERE ranked.group_id = question_group_relations.group_id
+			  AND ranked.question_id = question_group_relations.question_id
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to seed group question positions: %v", err)
+	}
+	return nil
+}
+
 // handleNullJSON safely handles NULL JSON fields by providing default values
 func handleNullJSON(nullStr sql.NullString, defaultValue string) json.RawMessage {
 	if nullStr.Valid && nullStr.String != "" {
@@ -178,7 +430,14 @@ func (d *Database) CreateQuestion(question *Question) error {
 		question.CreatedAt,
 		question.UpdatedAt,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	// A new question has no tag links yet, so only tagged questions need syncing
+	if len(parseQuestionTags(question.Tags)) == 0 {
+		return nil
+	}
+	return syncQuestionTags(d.db, question.ID, question.Tags, question.UpdatedAt)
 }
 
 func (d *Database) GetQuestions() ([]Question, error) {
@@ -226,7 +485,7 @@ func (d *Database) GetQuestionsByGroup(groupID string) ([]Question, error) {
 			  FROM questions q
 			  JOIN question_group_relations qgr ON q.id = qgr.question_id
 			  WHERE qgr.group_id = ?
-			  ORDER BY COALESCE(q.[index], 999999), q.created_at DESC`
+			  ORDER BY qgr.position IS NULL, qgr.position, COALESCE(q.[index], 999999), q.created_at DESC`
 	
 	rows, err := d.db.Query(query, groupID)
 	if err != nil {
@@ -293,6 +552,12 @@ func (d *Database) GetQuestionByID(questionID string) (*Question, error) {
 	q.Options = handleNullJSON(options, `[]`)
 	q.Answer = handleNullJSON(answer, `[]`)
 	q.Tags = handleNullJSON(tags, `[]`)
+	if err == sql.ErrNoRows {
+		// Variants generated from question templates are graded like stored questions
+		if instance, instanceErr := d.GetTemplateInstance(questionID); instanceErr == nil {
+			return instance, nil
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -318,29 +583,137 @@ func (d *Database) UpdateQuestion(question *Question) error {
 		question.UpdatedAt,
 		question.ID,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return syncQuestionTags(d.db, question.ID, question.Tags, question.UpdatedAt)
 }
 
 // UpdateQuestionDifficulty updates the difficulty of a specific question
-func (d *Database) UpdateQuestionDifficulty(questionID string, difficulty *int) error {
+func (d *Database) UpdateQuestionDifficulty(questionID string, difficulty *int, at time.Time) error {
 	query := `UPDATE questions SET difficulty = ?, updated_at = ? WHERE id = ?`
 	
-	_, err := d.db.Exec(query, difficulty, time.Now().Format(time.RFC3339), questionID)
+	_, err := d.db.Exec(query, difficulty, at.Format(time.RFC3339), questionID)
 	return err
 }
 
+// Question Revisions methods
+
+// CreateQuestionRevision stores a new revision for a question
+func (d *Database) CreateQuestionRevision(revision *QuestionRevision) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Number the revision in the insert itself so concurrent writers cannot collide
+	query := `INSERT INTO question_revisions (id, question_id, revision, actor, reason, snapshot, changes, created_at)
+			  SELECT ?, ?, COALESCE(MAX(revision), 0) + 1, ?, ?, ?, ?, ? FROM question_revisions WHERE question_id = ?`
+
+	_, err = tx.Exec(query,
+		revision.ID,
+		revision.QuestionID,
+		revision.Actor,
+		revision.Reason,
+		revision.Snapshot,
+		revision.Changes,
+		revision.CreatedAt,
+		revision.QuestionID,
+	)
+	if err != nil {
+		return err
+	}
+	if err := tx.QueryRow(`SELECT revision FROM question_revisions WHERE id = ?`, revision.ID).Scan(&revision.Revision); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// scanQuestionRevision scans a question revision row
+func scanQuestionRevision(scanner interface{ Scan(...interface{}) error }) (*QuestionRevision, error) {
+	var r QuestionRevision
+	var reason, snapshot, changes sql.NullString
+	if err := scanner.Scan(
+		&r.ID,
+		&r.QuestionID,
+		&r.Revision,
+		&r.Actor,
+		&reason,
+		&snapshot,
+		&changes,
+		&r.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	r.Reason = reason.String
+	r.Snapshot = handleNullJSON(snapshot, `{}`)
+	r.Changes = handleNullJSON(changes, `[]`)
+	return &r, nil
+}
+
+// GetQuestionRevisions returns all revisions of a question, oldest first
+func (d *Database) GetQuestionRevisions(questionID string) ([]QuestionRevision, error) {
+	query := `SELECT id, question_id, revision, actor, reason, snapshot, changes, created_at
+			  FROM question_revisions WHERE question_id = ? ORDER BY revision ASC`
+
+	rows, err := d.db.Query(query, questionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []QuestionRevision
+	for rows.Next() {
+		r, err := scanQuestionRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *r)
+	}
+	return revisions, rows.Err()
+}
+
+// GetQuestionRevision returns a specific revision of a question
+func (d *Database) GetQuestionRevision(questionID string, revision int) (*QuestionRevision, error) {
+	query := `SELECT id, question_id, revision, actor, reason, snapshot, changes, created_at
+			  FROM question_revisions WHERE question_id = ? AND revision = ?`
+
+	return scanQuestionRevision(d.db.QueryRow(query, questionID, revision))
+}
+
+// GetLatestQuestionRevision returns the most recent revision of a question,
+// or nil if the question has no recorded history
+func (d *Database) GetLatestQuestionRevision(questionID string) (*QuestionRevision, error) {
+	query := `SELECT id, question_id, revision, actor, reason, snapshot, changes, created_at
+			  FROM question_revisions WHERE question_id = ? ORDER BY revision DESC LIMIT 1`
+
+	r, err := scanQuestionRevision(d.db.QueryRow(query, questionID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return r, err
+}
+
 // Question Groups methods
 func (d *Database) CreateQuestionGroup(group *QuestionGroup) error {
-	query := `INSERT INTO question_groups (id, name, description, parent_id, color, icon, created_at, updated_at)
-			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	smartQuery, err := marshalSmartQuery(group.SmartQuery)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO question_groups (id, name, description, parent_id, color, icon, position, smart_query, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	
-	_, err := d.db.Exec(query,
+	_, err = d.db.Exec(query,
 		group.ID,
 		group.Name,
 		group.Description,
 		group.ParentID,
 		group.Color,
 		group.Icon,
+		group.Position,
+		smartQuery,
 		group.CreatedAt,
 		group.UpdatedAt,
 	)
@@ -348,7 +721,7 @@ func (d *Database) CreateQuestionGroup(group *QuestionGroup) error {
 }
 
 func (d *Database) GetQuestionGroups() ([]QuestionGroup, error) {
-	query := `SELECT id, name, description, parent_id, color, icon, created_at, updated_at FROM question_groups ORDER BY created_at DESC`
+	query := `SELECT id, name, description, parent_id, color, icon, COALESCE(position, 0), smart_query, created_at, updated_at FROM question_groups ORDER BY created_at DESC`
 	
 	rows, err := d.db.Query(query)
 	if err != nil {
@@ -359,6 +732,7 @@ func (d *Database) GetQuestionGroups() ([]QuestionGroup, error) {
 	var groups []QuestionGroup
 	for rows.Next() {
 		var g QuestionGroup
+		var smartQuery sql.NullString
 		err := rows.Scan(
 			&g.ID,
 			&g.Name,
@@ -366,30 +740,23 @@ func (d *Database) GetQuestionGroups() ([]QuestionGroup, error) {
 			&g.ParentID,
 			&g.Color,
 			&g.Icon,
+			&g.Position,
+			&smartQuery,
 			&g.CreatedAt,
 			&g.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
-		
-		// Get question IDs for this group
-		questionQuery := `SELECT question_id FROM question_group_relations WHERE group_id = ?`
-		questionRows, err := d.db.Query(questionQuery, g.ID)
-		if err != nil {
+		if g.SmartQuery, err = unmarshalSmartQuery(smartQuery); err != nil {
 			return nil, err
 		}
 		
-		var questionIds []string
-		for questionRows.Next() {
-			var questionId string
-			if err := questionRows.Scan(&questionId); err != nil {
-				questionRows.Close()
-				return nil, err
-			}
-			questionIds = append(questionIds, questionId)
+		// Get question IDs for this group in the group's order
+		questionIds, err := d.GetGroupQuestionIDs(g.ID)
+		if err != nil {
+			return nil, err
 		}
-		questionRows.Close()
 		
 		g.QuestionIds = questionIds
 		groups = append(groups, g)
@@ -403,6 +770,49 @@ func (d *Database) AddQuestionToGroup(groupID, questionID string) error {
 	return err
 }
 
+// GetGroupQuestionIDs returns the IDs of a group's questions in the group's order.
+// Questions without an explicit position follow the ordered ones by global index.
+func (d *Database) GetGroupQuestionIDs(groupID string) ([]string, error) {
+	query := `SELECT qgr.question_id FROM question_group_relations qgr
+			  JOIN questions q ON q.id = qgr.question_id
+			  WHERE qgr.group_id = ?
+			  ORDER BY qgr.position IS NULL, qgr.position, COALESCE(q.[index], 999999), q.created_at DESC`
+
+	rows, err := d.db.Query(query, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// SetGroupQuestionOrder stores the position of each question within a group
+func (d *Database) SetGroupQuestionOrder(groupID string, questionIDs []string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, questionID := range questionIDs {
+		_, err := tx.Exec(`UPDATE question_group_relations SET position = ? WHERE group_id = ? AND question_id = ?`, i, groupID, questionID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 // UpdateQuestionGroup updates a question group's information
 func (d *Database) UpdateQuestionGroup(group *QuestionGroup) error {
 	query := `UPDATE question_groups SET name = ?, description = ?, parent_id = ?, color = ?, icon = ?, updated_at = ? WHERE id = ?`
@@ -421,8 +831,17 @@ func (d *Database) UpdateQuestionGroup(group *QuestionGroup) error {
 
 // Practice Sessions methods
 func (d *Database) CreatePracticeSession(session *PracticeSession) error {
-	query := `INSERT INTO practice_sessions (id, group_id, mode, start_time, end_time, duration, total_questions, correct_count, details, created_at)
-			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	var sectionTiming interface{}
+	if len(session.Sections) > 0 {
+		timingJSON, err := json.Marshal(session.Sections)
+		if err != nil {
+			return err
+		}
+		sectionTiming = string(timingJSON)
+	}
+
+	query := `INSERT INTO practice_sessions (id, group_id, mode, start_time, end_time, duration, total_questions, correct_count, details, section_timing, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	
 	_, err := d.db.Exec(query,
 		session.ID,
@@ -434,13 +853,14 @@ func (d *Database) CreatePracticeSession(session *PracticeSession) error {
 		session.TotalQuestions,
 		session.CorrectCount,
 		session.Details,
+		sectionTiming,
 		session.CreatedAt,
 	)
 	return err
 }
 
 func (d *Database) GetPracticeSessions() ([]PracticeSession, error) {
-	query := `SELECT id, group_id, mode, start_time, end_time, duration, total_questions, correct_count, details, created_at FROM practice_sessions ORDER BY created_at DESC`
+	query := `SELECT id, group_id, mode, start_time, end_time, duration, total_questions, correct_count, details, section_timing, created_at FROM practice_sessions ORDER BY created_at DESC`
 	
 	rows, err := d.db.Query(query)
 	if err != nil {
@@ -451,6 +871,8 @@ func (d *Database) GetPracticeSessions() ([]PracticeSession, error) {
 	var sessions []PracticeSession
 	for rows.Next() {
 		var s PracticeSession
+		var details []byte // Sessions that were started but never saved have no details
+		var sectionTiming sql.NullString
 		err := rows.Scan(
 			&s.ID,
 			&s.GroupID,
@@ -460,12 +882,19 @@ func (d *Database) GetPracticeSessions() ([]PracticeSession, error) {
 			&s.Duration,
 			&s.TotalQuestions,
 			&s.CorrectCount,
-			&s.Details,
+			&details,
+			&sectionTiming,
 			&s.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
+		s.Details = details
+		if sectionTiming.Valid && sectionTiming.String != "" {
+			if err := json.Unmarshal([]byte(sectionTiming.String), &s.Sections); err != nil {
+				return nil, fmt.Errorf("failed to parse section timing of session %s: %v", s.ID, err)
+			}
+		}
 		sessions = append(sessions, s)
 	}
 	return sessions, nil
@@ -507,6 +936,21 @@ func (d *Database) DeleteQuestion(questionID string) error {
 		return err
 	}
 
+	_, err = tx.Exec(`DELETE FROM question_tags WHERE question_id = ?`, questionID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM item_calibrations WHERE question_id = ?`, questionID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM question_revisions WHERE question_id = ?`, questionID)
+	if err != nil {
+		return err
+	}
+
 	// Delete the question
 	_, err = tx.Exec(`DELETE FROM questions WHERE id = ?`, questionID)
 	if err != nil {
@@ -540,9 +984,13 @@ func (d *Database) DeleteQuestionGroup(groupID string) error {
 
 // Wrong Questions methods
 func (d *Database) AddWrongQuestion(wrongQuestion *WrongQuestion) error {
-	query := `INSERT OR REPLACE INTO wrong_questions (id, question_id, added_at, reviewed_at, times_reviewed, last_result, notes)
-			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT OR REPLACE INTO wrong_questions (id, question_id, added_at, reviewed_at, times_reviewed, last_result, notes, priority, ease, interval_days, due_at, lapses, leech)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	
+	ease := wrongQuestion.Ease
+	if ease <= 0 {
+		ease = defaultReviewEase
+	}
 	_, err := d.db.Exec(query,
 		wrongQuestion.ID,
 		wrongQuestion.QuestionID,
@@ -551,13 +999,19 @@ func (d *Database) AddWrongQuestion(wrongQuestion *WrongQuestion) error {
 		wrongQuestion.TimesReviewed,
 		wrongQuestion.LastResult,
 		wrongQuestion.Notes,
+		wrongQuestion.Priority,
+		ease,
+		wrongQuestion.IntervalDays,
+		wrongQuestion.DueAt,
+		wrongQuestion.Lapses,
+		wrongQuestion.Leech,
 	)
 	return err
 }
 
 func (d *Database) GetWrongQuestions() ([]WrongQuestion, error) {
-	query := `SELECT id, question_id, added_at, reviewed_at, times_reviewed, last_result, notes 
-			  FROM wrong_questions ORDER BY added_at DESC`
+	query := `SELECT id, question_id, added_at, reviewed_at, times_reviewed, last_result, notes, priority, ease, interval_days, due_at, lapses, leech
+			  FROM wrong_questions ORDER BY priority DESC, added_at DESC`
 	
 	rows, err := d.db.Query(query)
 	if err != nil {
@@ -576,6 +1030,12 @@ func (d *Database) GetWrongQuestions() ([]WrongQuestion, error) {
 			&wq.TimesReviewed,
 			&wq.LastResult,
 			&wq.Notes,
+			&wq.Priority,
+			&wq.Ease,
+			&wq.IntervalDays,
+			&wq.DueAt,
+			&wq.Lapses,
+			&wq.Leech,
 		)
 		if err != nil {
 			return nil, err
@@ -586,11 +1046,11 @@ func (d *Database) GetWrongQuestions() ([]WrongQuestion, error) {
 }
 
 func (d *Database) GetWrongQuestionsWithDetails() ([]map[string]interface{}, error) {
-	query := `SELECT wq.id, wq.question_id, wq.added_at, wq.reviewed_at, wq.times_reviewed, wq.last_result, wq.notes,
+	query := `SELECT wq.id, wq.question_id, wq.added_at, wq.reviewed_at, wq.times_reviewed, wq.last_result, wq.notes, wq.priority, wq.ease, wq.interval_days, wq.due_at, wq.lapses, wq.leech,
 				q.question, q.options, q.answer, q.explanation, q.tags, q.image_url, q.difficulty, q.source
 			  FROM wrong_questions wq
 			  JOIN questions q ON wq.question_id = q.id
-			  ORDER BY wq.added_at DESC`
+			  ORDER BY wq.priority DESC, wq.added_at DESC`
 	
 	rows, err := d.db.Query(query)
 	if err != nil {
@@ -611,6 +1071,12 @@ func (d *Database) GetWrongQuestionsWithDetails() ([]map[string]interface{}, err
 			&wq.TimesReviewed,
 			&wq.LastResult,
 			&wq.Notes,
+			&wq.Priority,
+			&wq.Ease,
+			&wq.IntervalDays,
+			&wq.DueAt,
+			&wq.Lapses,
+			&wq.Leech,
 			&q.Question,
 			&options,
 			&answer,
@@ -648,12 +1114,17 @@ func (d *Database) GetWrongQuestionsWithDetails() ([]map[string]interface{}, err
 	return results, nil
 }
 
-func (d *Database) UpdateWrongQuestionReview(questionID string, isCorrect bool, notes string) error {
-	query := `UPDATE wrong_questions 
-			  SET reviewed_at = ?, times_reviewed = times_reviewed + 1, last_result = ?, notes = ?
-			  WHERE question_id = ?`
-	
-	_, err := d.db.Exec(query, time.Now().Format(time.RFC3339), isCorrect, notes, questionID)
+func (d *Database) UpdateWrongQuestionReview(questionID string, isCorrect bool, notes string, at time.Time) error {
+	grade := FlashcardGradeAgain
+	if isCorrect {
+		grade = FlashcardGradeGood
+	}
+	if err := d.ReviewWrongQuestion(questionID, grade, at); err != nil {
+		return err
+	}
+
+	query := `UPDATE wrong_questions SET notes = ? WHERE question_id = ?`
+	_, err := d.db.Exec(query, notes, questionID)
 	return err
 }
 
@@ -663,6 +1134,13 @@ func (d *Database) RemoveWrongQuestion(questionID string) error {
 	return err
 }
 
+// RecordWrongQuestionLapse counts a lapse of a wrong question answered incorrectly again
+// and raises its priority, never lowering it
+func (d *Database) RecordWrongQuestionLapse(questionID string, priority int) error {
+	_, err := d.db.Exec(`UPDATE wrong_questions SET priority = MAX(COALESCE(priority, 0), ?), lapses = COALESCE(lapses, 0) + 1 WHERE question_id = ?`, priority, questionID)
+	return err
+}
+
 func (d *Database) IsQuestionMarkedWrong(questionID string) (bool, error) {
 	query := `SELECT COUNT(*) FROM wrong_questions WHERE question_id = ?`
 	var count int
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// sameAnswers reports whether two answers select the same options
+func sameAnswers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	left := append([]string{}, a...)
+	right := append([]string{}, b...)
+	sort.Strings(left)
+	sort.Strings(right)
+	for i := range left {
+		if left[i] != right[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// nullableAnswer marshals an answer for storage, keeping absent answers NULL
+func nullableAnswer(answer []string) (interface{}, error) {
+	if answer == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(answer)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Session event database methods
+
+// AddSessionEvent appends an event to the log of its session
+func (d *Database) AddSessionEvent(event *SessionEvent) error {
+	fromAnswer, err := nullableAnswer(event.FromAnswer)
+	if err != nil {
+		return err
+	}
+	toAnswer, err := nullableAnswer(event.ToAnswer)
+	if err != nil {
+		return err
+	}
+	result, err := d.db.Exec(`INSERT INTO session_events (session_id, question_id, type, from_answer, to_answer, from_correct, to_correct, at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.SessionID, event.QuestionID, event.Type, fromAnswer, toAnswer, event.FromCorrect, event.ToCorrect, event.At)
+	if err != nil {
+		return err
+	}
+	event.ID, err = result.LastInsertId()
+	return err
+}
+
+// querySessionEvents runs a query over session_events and scans the events in order
+func (d *Database) querySessionEvents(where string, args ...interface{}) ([]SessionEvent, error) {
+	rows, err := d.db.Query(`SELECT id, session_id, question_id, type, from_answer, to_answer, from_correct, to_correct, at
+			  FROM session_events WHERE `+where+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []SessionEvent{}
+	for rows.Next() {
+		var event SessionEvent
+		var fromAnswer, toAnswer sql.NullString
+		var fromCorrect, toCorrect sql.NullBool
+		if err := rows.Scan(&event.ID, &event.SessionID, &event.QuestionID, &event.Type,
+			&fromAnswer, &toAnswer, &fromCorrect, &toCorrect, &event.At); err != nil {
+			return nil, err
+		}
+		if fromAnswer.Valid {
+			json.Unmarshal([]byte(fromAnswer.String), &event.FromAnswer)
+		}
+		if toAnswer.Valid {
+			json.Unmarshal([]byte(toAnswer.String), &event.ToAnswer)
+		}
+		if fromCorrect.Valid {
+			event.FromCorrect = &fromCorrect.Bool
+		}
+		if toCorrect.Valid {
+			event.ToCorrect = &toCorrect.Bool
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// GetSessionEvents returns the events of a session in the order they were recorded
+func (d *Database) GetSessionEvents(sessionID string) ([]SessionEvent, error) {
+	return d.querySessionEvents(`session_id = ?`, sessionID)
+}
+
+// GetLastAnswerEvent returns the latest answer event of a question in a session, or nil
+func (d *Database) GetLastAnswerEvent(sessionID, questionID string) (*SessionEvent, error) {
+	events, err := d.querySessionEvents(`session_id = ? AND question_id = ? AND type IN (?, ?)`,
+		sessionID, questionID, SessionEventAnswerSelected, SessionEventAnswerChanged)
+	if err != nil || len(events) == 0 {
+		return nil, err
+	}
+	return &events[len(events)-1], nil
+}
+
+// Session event methods
+
+// RecordSessionEvent records a step of an active session with the backend clock. Answer
+// events are graded and classified as a first selection or a change from the previous
+// answer; selecting the current answer again records nothing and returns nil. Their
+// results are withheld while the session conceals answers.
+func (a *App) RecordSessionEvent(sessionID, eventType, questionID string, answer []string) (*SessionEvent, error) {
+	if sessionID == "" {
+		return nil, fmt.Errorf("session ID is required")
+	}
+	session, err := a.db.GetActiveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != SessionStatusActive {
+		return nil, fmt.Errorf("session %s has already finished", sessionID)
+	}
+	switch eventType {
+	case SessionEventViewed, SessionEventMarked, SessionEventUnmarked, SessionEventAnswerSelected, SessionEventAnswerChanged:
+		if questionID == "" {
+			return nil, fmt.Errorf("a question ID is required for %s events", eventType)
+		}
+		if !containsString(session.QuestionIDs, questionID) {
+			return nil, fmt.Errorf("question %s is not part of the session", questionID)
+		}
+	case SessionEventPaused, SessionEventResumed, SessionEventFinished:
+		questionID = ""
+	default:
+		return nil, fmt.Errorf("unknown session event type %q", eventType)
+	}
+
+	if eventType == SessionEventAnswerSelected || eventType == SessionEventAnswerChanged {
+		question, err := a.db.GetQuestionByID(questionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get question: %v", err)
+		}
+		return a.recordAnswerEvent(session, questionID, answer, a.gradeAnswer(*question, answer))
+	}
+	return a.recordSessionEvent(sessionID, eventType, questionID)
+}
+
+// recordSessionEvent records an event without an answer
+func (a *App) recordSessionEvent(sessionID, eventType, questionID string) (*SessionEvent, error) {
+	event := &SessionEvent{
+		SessionID:  sessionID,
+		QuestionID: questionID,
+		Type:       eventType,
+		At:         a.now().Format(time.RFC3339Nano),
+	}
+	if err := a.withoutJournal(func() error { return a.db.AddSessionEvent(event) }); err != nil {
+		return nil, fmt.Errorf("failed to record session event: %v", err)
+	}
+	return event, nil
+}
+
+// recordAnswerEvent records an already graded answer, as a change when the question
+// was answered before in the session. The results are stored but left out of the
+// returned event while the session conceals answers.
+func (a *App) recordAnswerEvent(session *ActiveSession, questionID string, answer []string, isCorrect bool) (*SessionEvent, error) {
+	if answer == nil {
+		answer = []string{}
+	}
+	previous, err := a.db.GetLastAnswerEvent(session.ID, questionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get previous answer: %v", err)
+	}
+	if previous != nil && sameAnswers(previous.ToAnswer, answer) {
+		return nil, nil
+	}
+
+	event := &SessionEvent{
+		SessionID:  session.ID,
+		QuestionID: questionID,
+		Type:       SessionEventAnswerSelected,
+		ToAnswer:   answer,
+		ToCorrect:  &isCorrect,
+		At:         a.now().Format(time.RFC3339Nano),
+	}
+	if previous != nil {
+		event.Type = SessionEventAnswerChanged
+		event.FromAnswer = previous.ToAnswer
+		event.FromCorrect = previous.ToCorrect
+	}
+	if err := a.withoutJournal(func() error { return a.db.AddSessionEvent(event) }); err != nil {
+		return nil, fmt.Errorf("failed to record session event: %v", err)
+	}
+	if session.concealsAnswers() {
+		event.FromCorrect, event.ToCorrect = nil, nil
+	}
+	return event, nil
+}
+
+// GetSessionEvents returns the event log of a session for replay. Answer results are
+// left out until the session has finished.
+func (a *App) GetSessionEvents(sessionID string) ([]SessionEvent, error) {
+	session, err := a.db.GetActiveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := a.db.GetSessionEvents(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session events: %v", err)
+	}
+	if session.Status != SessionStatusFinished {
+		for i := range events {
+			events[i].FromCorrect, events[i].ToCorrect = nil, nil
+		}
+	}
+	return events, nil
+}
+
+// GetQuestionTimelines replays the event log of a session and reports how long each
+// question was on screen, before its first answer and after its final one. A question
+// is on screen from its viewed event until another question is viewed, the session is
+// paused or it finishes. Answer results are left out until the session has finished.
+func (a *App) GetQuestionTimelines(sessionID string) ([]QuestionTimeline, error) {
+	events, err := a.GetSessionEvents(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	timelines := make(map[string]*QuestionTimeline)
+	var order []string
+	timeline := func(questionID string) *QuestionTimeline {
+		if t, ok := timelines[questionID]; ok {
+			return t
+		}
+		t := &QuestionTimeline{QuestionID: questionID, Events: []SessionEvent{}}
+		timelines[questionID] = t
+		order = append(order, questionID)
+		return t
+	}
+
+	// Seconds viewed when each question was last answered
+	lastAnswer := make(map[string]float64)
+	current, paused := "", ""
+	var since time.Time
+	// closeVisit adds the time since the last checkpoint to the question on screen
+	closeVisit := func(at time.Time) {
+		if current != "" {
+			timeline(current).TimeViewed += at.Sub(since).Seconds()
+		}
+		since = at
+	}
+	for _, event := range events {
+		at, err := time.Parse(time.RFC3339Nano, event.At)
+		if err != nil {
+			continue
+		}
+		switch event.Type {
+		case SessionEventViewed:
+			closeVisit(at)
+			current = event.QuestionID
+			timeline(current).Visits++
+		case SessionEventPaused:
+			closeVisit(at)
+			current, paused = "", current
+		case SessionEventResumed:
+			since = at
+			current = paused
+		case SessionEventFinished:
+			closeVisit(at)
+			current = ""
+		case SessionEventAnswerSelected, SessionEventAnswerChanged:
+			if event.QuestionID == current {
+				closeVisit(at)
+			}
+			t := timeline(event.QuestionID)
+			viewed := t.TimeViewed
+			lastAnswer[event.QuestionID] = viewed
+			if t.TimeToFirstAnswer == nil {
+				t.TimeToFirstAnswer = &viewed
+			}
+			if event.Type == SessionEventAnswerChanged {
+				t.AnswerChanges++
+			}
+		}
+		if event.QuestionID != "" {
+			t := timeline(event.QuestionID)
+			t.Events = append(t.Events, event)
+		}
+	}
+
+	result := make([]QuestionTimeline, 0, len(order))
+	for _, questionID := range order {
+		t := timelines[questionID]
+		if viewed, ok := lastAnswer[questionID]; ok {
+			after := t.TimeViewed - viewed
+			t.TimeAfterLastAnswer = &after
+		}
+		result = append(result, *t)
+	}
+	return result, nil
+}
+
+// GetAnswerChangeStats counts over all finished sessions how often a changed answer
+// went from wrong to right, from right to wrong or stayed wrong
+func (a *App) GetAnswerChangeStats() (*AnswerChangeStats, error) {
+	events, err := a.db.querySessionEvents(`type = ? AND session_id IN (SELECT id FROM active_sessions WHERE status = ?)`,
+		SessionEventAnswerChanged, SessionStatusFinished)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get answer changes: %v", err)
+	}
+
+	stats := &AnswerChangeStats{}
+	for _, event := range events {
+		if event.FromCorrect == nil || event.ToCorrect == nil {
+			continue
+		}
+		stats.Changes++
+		switch {
+		case !*event.FromCorrect && *event.ToCorrect:
+			stats.WrongToRight++
+		case *event.FromCorrect && !*event.ToCorrect:
+			stats.RightToWrong++
+		case !*event.FromCorrect && !*event.ToCorrect:
+			stats.WrongToWrong++
+		}
+	}
+	stats.NetGain = stats.WrongToRight - stats.RightToWrong
+	return stats, nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSessionEventLog tests answer change classification, timelines and change statistics
+func TestSessionEventLog(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	start := time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC)
+	now := start
+	app.clock = func() time.Time { return now }
+	at := func(seconds int) { now = start.Add(time.Duration(seconds) * time.Second) }
+
+	data := []map[string]interface{}{
+		{"question": "Q1", "options": []string{"A", "B"}, "answer": []string{"a"}},
+		{"question": "Q2", "options": []string{"A", "B"}, "answer": []string{"b"}},
+	}
+	app.ImportQuestions(data, "")
+
+	session, err := app.StartSession("", PracticeModeTest, nil)
+	if err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	ids := make(map[string]string)
+	for _, q := range session.Questions {
+		ids[q.Question] = q.ID
+	}
+	q1, q2 := ids["Q1"], ids["Q2"]
+
+	if _, err := app.RecordSessionEvent(session.ID, "scrolled", q1, nil); err == nil {
+		t.Error("Expected an unknown event type to be rejected")
+	}
+	if _, err := app.RecordSessionEvent("bogus", SessionEventPaused, "", nil); err == nil {
+		t.Error("Expected an unknown session to be rejected")
+	}
+
+	// Q1: viewed 10s, answered right, changed to wrong, then pause 100s
+	app.RecordSessionEvent(session.ID, SessionEventViewed, q1, nil)
+	at(4)
+	app.SubmitSessionAnswer(session.ID, q1, []string{"a"}, 4, "")
+	at(6)
+	app.SubmitSessionAnswer(session.ID, q1, []string{"b"}, 2, "")
+	at(7)
+	app.RecordSessionEvent(session.ID, SessionEventMarked, q1, nil)
+	at(10)
+	app.RecordSessionEvent(session.ID, SessionEventPaused, "", nil)
+	at(110)
+	app.RecordSessionEvent(session.ID, SessionEventResumed, "", nil)
+	at(115)
+
+	// Q2: answered wrong and changed to right
+	app.RecordSessionEvent(session.ID, SessionEventViewed, q2, nil)
+	at(120)
+	app.SubmitSessionAnswer(session.ID, q2, []string{"a"}, 5, "")
+	at(121)
+	if event, _ := app.RecordSessionEvent(session.ID, SessionEventAnswerSelected, q2, []string{"a"}); event != nil {
+		t.Errorf("Expected selecting the same answer again to record nothing, got %+v", event)
+	}
+	if event, _ := app.RecordSessionEvent(session.ID, SessionEventAnswerSelected, q2, []string{"c"}); event == nil || event.ToCorrect != nil || event.FromCorrect != nil {
+		t.Errorf("Expected the result to be withheld during the test, got %+v", event)
+	}
+	if events, _ := app.GetSessionEvents(session.ID); events[2].FromCorrect != nil || events[2].ToCorrect != nil {
+		t.Errorf("Expected results to be withheld until the session finishes, got %+v", events[2])
+	}
+	if timelines, _ := app.GetQuestionTimelines(session.ID); timelines[0].Events[2].FromCorrect != nil || timelines[0].Events[2].ToCorrect != nil {
+		t.Errorf("Expected timelines to withhold results until the session finishes, got %+v", timelines[0].Events[2])
+	}
+	if stats, _ := app.GetAnswerChangeStats(); stats.Changes != 0 {
+		t.Errorf("Expected running sessions to be left out of the change stats, got %+v", stats)
+	}
+	at(125)
+	app.SubmitSessionAnswer(session.ID, q2, []string{"b"}, 5, "")
+	at(130)
+	finished, err := app.FinishSession(session.ID)
+	if err != nil {
+		t.Fatalf("Failed to finish session: %v", err)
+	}
+	app.waitForCalibration()
+	if session.StartedAt != start.Format(time.RFC3339) || *finished.FinishedAt != now.Format(time.RFC3339) {
+		t.Errorf("Expected the session to be stamped with the app clock, got %s to %s", session.StartedAt, *finished.FinishedAt)
+	}
+	if _, err := app.RecordSessionEvent(session.ID, SessionEventResumed, "", nil); err == nil {
+		t.Error("Expected events of a finished session to be rejected")
+	}
+
+	events, err := app.GetSessionEvents(session.ID)
+	if err != nil {
+		t.Fatalf("Failed to get session events: %v", err)
+	}
+	if len(events) != 11 || events[len(events)-1].Type != SessionEventFinished {
+		t.Fatalf("Expected 11 events ending with finished, got %+v", events)
+	}
+	change := events[2]
+	if change.Type != SessionEventAnswerChanged || change.FromAnswer[0] != "a" || change.ToAnswer[0] != "b" ||
+		!*change.FromCorrect || *change.ToCorrect {
+		t.Errorf("Expected a right to wrong change, got %+v", change)
+	}
+
+	timelines, err := app.GetQuestionTimelines(session.ID)
+	if err != nil {
+		t.Fatalf("Failed to get timelines: %v", err)
+	}
+	if len(timelines) != 2 {
+		t.Fatalf("Expected 2 timelines, got %d", len(timelines))
+	}
+	first := timelines[0]
+	if first.Visits != 1 || first.TimeViewed != 15 || *first.TimeToFirstAnswer != 4 ||
+		*first.TimeAfterLastAnswer != 9 || first.AnswerChanges != 1 {
+		t.Errorf("Unexpected timeline for Q1: %+v", first)
+	}
+	second := timelines[1]
+	if second.TimeViewed != 15 || *second.TimeToFirstAnswer != 5 || *second.TimeAfterLastAnswer != 5 {
+		t.Errorf("Unexpected timeline for Q2: %+v", second)
+	}
+
+	stats, err := app.GetAnswerChangeStats()
+	if err != nil {
+		t.Fatalf("Failed to get answer change stats: %v", err)
+	}
+	if stats.Changes != 3 || stats.RightToWrong != 1 || stats.WrongToRight != 1 || stats.WrongToWrong != 1 || stats.NetGain != 0 {
+		t.Errorf("Unexpected answer change stats: %+v", stats)
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// masteryStreak is the number of correct answers in a row that masters a question
+const masteryStreak = 2
+
+// exposureStat holds the times one question was served, with the answers given
+type exposureStat struct {
+	exposures int
+	streak    int
+	firstAt   time.Time
+	lastAt    time.Time
+}
+
+// add folds an answer into the statistic. Only the latest answer moves the streak,
+// so answers folded in out of order just count as exposures.
+func (s *exposureStat) add(correct bool, at time.Time) {
+	if s.exposures == 0 || at.After(s.lastAt) {
+		if correct {
+			s.streak++
+		} else {
+			s.streak = 0
+		}
+		s.lastAt = at
+	}
+	if s.exposures == 0 || at.Before(s.firstAt) {
+		s.firstAt = at
+	}
+	s.exposures++
+}
+
+// exposure returns the statistic as the frontend sees it
+func (s *exposureStat) exposure(questionID string) QuestionExposure {
+	return QuestionExposure{
+		QuestionID:    questionID,
+		Exposures:     s.exposures,
+		CorrectStreak: s.streak,
+		Mastered:      s.streak >= masteryStreak,
+		FirstSeenAt:   s.firstAt.Format(time.RFC3339),
+		LastSeenAt:    s.lastAt.Format(time.RFC3339),
+	}
+}
+
+// coverageStats summarizes how much of a set of questions has been seen and mastered
+func coverageStats(key, name string, questionIDs []string, stats map[string]*exposureStat) CoverageStats {
+	coverage := CoverageStats{Key: key, Name: name, Total: len(questionIDs)}
+	for _, id := range questionIDs {
+		stat := stats[id]
+		if stat == nil || stat.exposures == 0 {
+			coverage.NeverSeen++
+			continue
+		}
+		coverage.Seen++
+		coverage.Exposures += stat.exposures
+		if stat.streak >= masteryStreak {
+			coverage.Mastered++
+		}
+	}
+	if coverage.Total > 0 {
+		coverage.SeenPercent = float64(coverage.Seen) / float64(coverage.Total) * 100
+		coverage.MasteredPercent = float64(coverage.Mastered) / float64(coverage.Total) * 100
+		coverage.NeverSeenPercent = float64(coverage.NeverSeen) / float64(coverage.Total) * 100
+	}
+	return coverage
+}
+
+// orderByExposure sorts questions from the least to the most answered, the longest
+// unseen first among equals, keeping the order of questions never answered
+func orderByExposure(questions []Question, stats map[string]*exposureStat) {
+	sort.SliceStable(questions, func(i, j int) bool {
+		a, b := stats[questions[i].ID], stats[questions[j].ID]
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		if a.exposures != b.exposures {
+			return a.exposures < b.exposures
+		}
+		return a.lastAt.Before(b.lastAt)
+	})
+}
+
+// Exposure database methods
+
+// GetQuestionExposures returns the answer statistics by question
+func (d *Database) GetQuestionExposures() (map[string]*exposureStat, error) {
+	rows, err := d.db.Query(`SELECT question_id, exposures, correct_streak, first_seen_at, last_seen_at FROM question_exposures`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make(map[string]*exposureStat)
+	for rows.Next() {
+		var id, firstAt, lastAt string
+		stat := &exposureStat{}
+		if err := rows.Scan(&id, &stat.exposures, &stat.streak, &firstAt, &lastAt); err != nil {
+			return nil, err
+		}
+		stat.firstAt, _ = time.Parse(time.RFC3339Nano, firstAt)
+		stat.lastAt, _ = time.Parse(time.RFC3339Nano, lastAt)
+		stats[id] = stat
+	}
+	return stats, rows.Err()
+}
+
+// SaveQuestionExposures upserts the statistics of the given questions
+func (d *Database) SaveQuestionExposures(stats map[string]*exposureStat) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for id, stat := range stats {
+		_, err := tx.Exec(`INSERT INTO question_exposures (question_id, exposures, correct_streak, first_seen_at, last_seen_at) VALUES (?, ?, ?, ?, ?)
+				  ON CONFLICT(question_id) DO UPDATE SET exposures = excluded.exposures, correct_streak = excluded.correct_streak,
+				  first_seen_at = excluded.first_seen_at, last_seen_at = excluded.last_seen_at`,
+			id, stat.exposures, stat.streak, stat.firstAt.Format(time.RFC3339Nano), stat.lastAt.Format(time.RFC3339Nano))
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Exposure methods
+
+// exposureCache returns the question exposures as a cache kept up to date as sessions
+// are saved
+func (a *App) exposureCache() attemptCache[*exposureStat] {
+	return attemptCache[*exposureStat]{
+		name:  "question exposures",
+		table: "question_exposures",
+		load:  a.db.GetQuestionExposures,
+		save:  a.db.SaveQuestionExposures,
+		fresh: func() *exposureStat { return &exposureStat{} },
+	}
+}
+
+// recordExposures counts the questions of a saved session towards their exposures
+func (a *App) recordExposures(session *PracticeSession) {
+	a.exposureCache().record(a, session)
+}
+
+// preferUnderexposed reorders assembled questions so the least answered come first
+// when the preferUnderexposed setting is on. Failures leave the order unchanged.
+func (a *App) preferUnderexposed(questions []Question) {
+	value, err := a.db.GetSetting("preferUnderexposed")
+	if err != nil {
+		return
+	}
+	var prefer bool
+	if json.Unmarshal(value, &prefer) != nil || !prefer {
+		return
+	}
+	stats, err := a.db.GetQuestionExposures()
+	if err != nil {
+		log.Printf("Warning: Failed to load question exposures: %v", err)
+		return
+	}
+	orderByExposure(questions, stats)
+}
+
+// GetQuestionExposures returns how often every question in the bank has been served in
+// a saved session, answered or not, least exposed first. Questions never served have no
+// seen times.
+func (a *App) GetQuestionExposures() ([]QuestionExposure, error) {
+	questions, err := a.db.GetQuestions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questions: %v", err)
+	}
+	stats, err := a.db.GetQuestionExposures()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load question exposures: %v", err)
+	}
+	orderByExposure(questions, stats)
+
+	exposures := make([]QuestionExposure, 0, len(questions))
+	for _, q := range questions {
+		if stat := stats[q.ID]; stat != nil {
+			exposures = append(exposures, stat.exposure(q.ID))
+		} else {
+			exposures = append(exposures, QuestionExposure{QuestionID: q.ID})
+		}
+	}
+	return exposures, nil
+}
+
+// GetCoverage returns how much of the bank, each group and each tag has been seen and
+// mastered. A question is mastered once its last answers were correct masteryStreak
+// times in a row. Tags roll up to their ancestors.
+func (a *App) GetCoverage() (*CoverageReport, error) {
+	questions, err := a.db.GetQuestions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questions: %v", err)
+	}
+	stats, err := a.db.GetQuestionExposures()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load question exposures: %v", err)
+	}
+	groups, err := a.GetQuestionGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question groups: %v", err)
+	}
+
+	ids := make([]string, 0, len(questions))
+	byTag := make(map[string][]string)
+	for _, q := range questions {
+		ids = append(ids, q.ID)
+		for _, topic := range questionTopics(q) {
+			byTag[topic] = append(byTag[topic], q.ID)
+		}
+	}
+
+	report := &CoverageReport{
+		Overall: coverageStats("", "All questions", ids, stats),
+		Groups:  make([]CoverageStats, 0, len(groups)),
+		Tags:    make([]CoverageStats, 0, len(byTag)),
+	}
+	for _, group := range groups {
+		if group.Coverage == nil {
+			coverage := coverageStats(group.ID, group.Name, group.QuestionIds, stats)
+			group.Coverage = &coverage
+		}
+		report.Groups = append(report.Groups, *group.Coverage)
+	}
+	for tag, tagIDs := range byTag {
+		report.Tags = append(report.Tags, coverageStats(tag, tag, tagIDs, stats))
+	}
+	sort.Slice(report.Tags, func(i, j int) bool {
+		if report.Tags[i].SeenPercent != report.Tags[j].SeenPercent {
+			return report.Tags[i].SeenPercent < report.Tags[j].SeenPercent
+		}
+		return report.Tags[i].Key < report.Tags[j].Key
+	})
+	return report, nil
+}
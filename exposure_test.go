@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestQuestionExposureAndCoverage tests exposure counting, mastery, group and tag
+// coverage and preferring under-exposed questions
+func TestQuestionExposureAndCoverage(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	group, err := app.CreateQuestionGroup("Cardio", "", "", "", "")
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+	app.ImportQuestions([]map[string]interface{}{
+		{"question": "Q1", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Cardiology/Valves"}},
+		{"question": "Q2", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Cardiology"}},
+	}, group.ID)
+	app.ImportQuestions([]map[string]interface{}{
+		{"question": "Q3", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Renal"}},
+	}, "")
+	questions, _ := app.GetQuestions()
+	ids := make(map[string]string)
+	for _, q := range questions {
+		ids[q.Question] = q.ID
+	}
+
+	start := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	addSession := func(id string, day int, records []QuestionRecord) {
+		details, _ := json.Marshal(records)
+		stamp := start.AddDate(0, 0, day).Format(time.RFC3339)
+		session := &PracticeSession{ID: id, Mode: "practice", StartTime: stamp, TotalQuestions: len(records), Details: details, CreatedAt: stamp}
+		db.CreatePracticeSession(session)
+		app.recordExposures(session)
+	}
+	addSession("s1", 0, []QuestionRecord{{QuestionID: ids["Q1"], IsCorrect: true}, {QuestionID: ids["Q3"], IsCorrect: true}})
+	addSession("s2", 1, []QuestionRecord{{QuestionID: ids["Q1"], IsCorrect: true}, {QuestionID: ids["Q3"], IsCorrect: false}})
+	addSession("s3", 2, []QuestionRecord{{QuestionID: ids["Q3"], IsCorrect: true}})
+
+	exposures, err := app.GetQuestionExposures()
+	if err != nil {
+		t.Fatalf("Failed to get exposures: %v", err)
+	}
+	if len(exposures) != 3 || exposures[0].QuestionID != ids["Q2"] || exposures[0].Exposures != 0 {
+		t.Fatalf("Expected the unseen question first, got %+v", exposures)
+	}
+	if exposures[1].QuestionID != ids["Q1"] || exposures[1].Exposures != 2 || !exposures[1].Mastered ||
+		exposures[1].LastSeenAt != start.AddDate(0, 0, 1).Format(time.RFC3339) {
+		t.Errorf("Expected Q1 to be mastered, got %+v", exposures[1])
+	}
+	if exposures[2].Exposures != 3 || exposures[2].CorrectStreak != 1 || exposures[2].Mastered {
+		t.Errorf("Expected the wrong answer to reset Q3's streak, got %+v", exposures[2])
+	}
+
+	// The incremental counts match a rebuild from the whole history
+	db.db.Exec("DELETE FROM question_exposures")
+	app.recordExposures(&PracticeSession{})
+	if rebuilt, _ := app.GetQuestionExposures(); rebuilt[2] != exposures[2] || rebuilt[1] != exposures[1] {
+		t.Errorf("Expected the rebuild to match, got %+v", rebuilt)
+	}
+
+	groups, _ := app.GetQuestionGroups()
+	if len(groups) != 1 || groups[0].Coverage == nil || groups[0].Coverage.Seen != 1 || groups[0].Coverage.SeenPercent != 50 ||
+		groups[0].Coverage.Mastered != 1 || groups[0].Coverage.NeverSeen != 1 {
+		t.Errorf("Unexpected group coverage: %+v", groups[0].Coverage)
+	}
+
+	coverage, err := app.GetCoverage()
+	if err != nil {
+		t.Fatalf("Failed to get coverage: %v", err)
+	}
+	if coverage.Overall.Total != 3 || coverage.Overall.Seen != 2 || coverage.Overall.Exposures != 5 || len(coverage.Groups) != 1 {
+		t.Errorf("Unexpected overall coverage: %+v", coverage)
+	}
+	tags := make(map[string]CoverageStats)
+	for _, tag := range coverage.Tags {
+		tags[tag.Key] = tag
+	}
+	if tags["Cardiology"].Total != 2 || tags["Cardiology"].Seen != 1 || tags["Cardiology/Valves"].MasteredPercent != 100 || tags["Renal"].Mastered != 0 {
+		t.Errorf("Unexpected tag coverage: %+v", coverage.Tags)
+	}
+
+	app.SetUserSetting("preferUnderexposed", true)
+	practice, _ := app.GetPracticeQuestions("")
+	if len(practice) != 3 || practice[0].ID != ids["Q2"] || practice[2].ID != ids["Q3"] {
+		t.Errorf("Expected the least answered questions first, got %v", []string{practice[0].Question, practice[1].Question, practice[2].Question})
+	}
+
+	// A question served but left unanswered counts as an exposure
+	session, err := app.StartSession("", PracticeModeTest, []string{ids["Q2"]})
+	if err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	app.FinishSession(session.ID)
+	app.waitForCalibration()
+	served := make(map[string]QuestionExposure)
+	exposures, _ = app.GetQuestionExposures()
+	for _, exposure := range exposures {
+		served[exposure.QuestionID] = exposure
+	}
+	if served[ids["Q2"]].Exposures != 1 || served[ids["Q2"]].Mastered {
+		t.Errorf("Expected the unanswered question to be exposed once, got %+v", served[ids["Q2"]])
+	}
+
+	// Undoing rebuilds the exposures from the history
+	db.db.Exec("DELETE FROM question_exposures")
+	app.SetUserSetting("preferUnderexposed", false)
+	if _, err := app.Undo(); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if rebuilt, _ := app.GetQuestionExposures(); len(rebuilt) != 3 || rebuilt[2].Exposures != 3 || rebuilt[0].Exposures != 1 {
+		t.Errorf("Expected the exposures rebuilt after undo, got %+v", rebuilt)
+	}
+}
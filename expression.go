@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// expressionFunctions are the functions template expressions may call
+var expressionFunctions = map[string]func(args []float64) (float64, error){
+	"abs":   unaryFunction(math.Abs),
+	"sqrt":  unaryFunction(math.Sqrt),
+	"floor": unaryFunction(math.Floor),
+	"ceil":  unaryFunction(math.Ceil),
+	"ln":    unaryFunction(math.Log),
+	"log10": unaryFunction(math.Log10),
+	"exp":   unaryFunction(math.Exp),
+	"round": func(args []float64) (float64, error) {
+		if len(args) == 1 {
+			return math.Round(args[0]), nil
+		}
+		if len(args) != 2 {
+			return 0, fmt.Errorf("round takes 1 or 2 arguments")
+		}
+		scale := math.Pow(10, math.Round(args[1]))
+		return math.Round(args[0]*scale) / scale, nil
+	},
+	"min": func(args []float64) (float64, error) {
+		if len(args) == 0 {
+			return 0, fmt.Errorf("min needs at least one argument")
+		}
+		result := args[0]
+		for _, v := range args[1:] {
+			result = math.Min(result, v)
+		}
+		return result, nil
+	},
+	"max": func(args []float64) (float64, error) {
+		if len(args) == 0 {
+			return 0, fmt.Errorf("max needs at least one argument")
+		}
+		result := args[0]
+		for _, v := range args[1:] {
+			result = math.Max(result, v)
+		}
+		return result, nil
+	},
+}
+
+// unaryFunction adapts a one-argument math function for expressions
+func unaryFunction(f func(float64) float64) func(args []float64) (float64, error) {
+	return func(args []float64) (float64, error) {
+		if len(args) != 1 {
+			return 0, fmt.Errorf("expected 1 argument, got %d", len(args))
+		}
+		return f(args[0]), nil
+	}
+}
+
+// expressionParser evaluates arithmetic expressions with + - * / % ^, parentheses,
+// variables and the functions above by recursive descent
+type expressionParser struct {
+	input string
+	pos   int
+	vars  map[string]float64
+}
+
+// evaluateExpression evaluates an expression against numeric variables
+func evaluateExpression(expression string, vars map[string]float64) (float64, error) {
+	p := &expressionParser{input: expression, vars: vars}
+	value, err := p.parseSum()
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return 0, fmt.Errorf("unexpected %q at position %d", p.input[p.pos:], p.pos)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("expression %q is not a finite number", expression)
+	}
+	return value, nil
+}
+
+// skipSpaces advances past blanks
+func (p *expressionParser) skipSpaces() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// accept consumes the next character when it is one of the given operators
+func (p *expressionParser) accept(operators string) (byte, bool) {
+	p.skipSpaces()
+	if p.pos < len(p.input) && strings.IndexByte(operators, p.input[p.pos]) >= 0 {
+		p.pos++
+		return p.input[p.pos-1], true
+	}
+	return 0, false
+}
+
+// parseSum parses terms joined by + and -
+func (p *expressionParser) parseSum() (float64, error) {
+	value, err := p.parseProduct()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op, ok := p.accept("+-")
+		if !ok {
+			return value, nil
+		}
+		right, err := p.parseProduct()
+		if err != nil {
+			return 0, err
+		}
+		if op == '+' {
+			value += right
+		} else {
+			value -= right
+		}
+	}
+}
+
+// parseProduct parses factors joined by *, / and %
+func (p *expressionParser) parseProduct() (float64, error) {
+	value, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op, ok := p.accept("*/%")
+		if !ok {
+			return value, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case op == '*':
+			value *= right
+		case right == 0:
+			return 0, fmt.Errorf("division by zero")
+		case op == '/':
+			value /= right
+		default:
+			value = math.Mod(value, right)
+		}
+	}
+}
+
+// parseUnary parses an optionally negated power
+func (p *expressionParser) parseUnary() (float64, error) {
+	if op, ok := p.accept("+-"); ok {
+		value, err := p.parseUnary()
+		if op == '-' {
+			value = -value
+		}
+		return value, err
+	}
+	return p.parsePower()
+}
+
+// parsePower parses a right-associative ^
+func (p *expressionParser) parsePower() (float64, error) {
+	base, err := p.parseAtom()
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := p.accept("^"); ok {
+		exponent, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		return math.Pow(base, exponent), nil
+	}
+	return base, nil
+}
+
+// parseAtom parses a number, a variable, a function call or a parenthesized expression
+func (p *expressionParser) parseAtom() (float64, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return 0, fmt.Errorf("unexpected end of expression")
+	}
+
+	if _, ok := p.accept("("); ok {
+		value, err := p.parseSum()
+		if err != nil {
+			return 0, err
+		}
+		if _, ok := p.accept(")"); !ok {
+			return 0, fmt.Errorf("missing closing parenthesis")
+		}
+		return value, nil
+	}
+
+	start := p.pos
+	c := rune(p.input[p.pos])
+	if unicode.IsDigit(c) || c == '.' {
+		for p.pos < len(p.input) && (unicode.IsDigit(rune(p.input[p.pos])) || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		return strconv.ParseFloat(p.input[start:p.pos], 64)
+	}
+	if !unicode.IsLetter(c) && c != '_' {
+		return 0, fmt.Errorf("unexpected %q at position %d", c, p.pos)
+	}
+	for p.pos < len(p.input) && (unicode.IsLetter(rune(p.input[p.pos])) || unicode.IsDigit(rune(p.input[p.pos])) || p.input[p.pos] == '_') {
+		p.pos++
+	}
+	name := p.input[start:p.pos]
+
+	if _, ok := p.accept("("); !ok {
+		value, ok := p.vars[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown variable %q", name)
+		}
+		return value, nil
+	}
+	function, ok := expressionFunctions[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown function %q", name)
+	}
+	var args []float64
+	if _, ok := p.accept(")"); !ok {
+		for {
+			arg, err := p.parseSum()
+			if err != nil {
+				return 0, err
+			}
+			args = append(args, arg)
+			if _, ok := p.accept(","); ok {
+				continue
+			}
+			if _, ok := p.accept(")"); !ok {
+				return 0, fmt.Errorf("missing closing parenthesis after arguments of %s", name)
+			}
+			break
+		}
+	}
+	value, err := function(args)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %v", name, err)
+	}
+	return value, nil
+}
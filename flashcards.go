@@ -0,0 +1,201 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Review scheduling parameters, following SM-2 with four self-grades
+const (
+	defaultReviewEase = 2.5
+	minimumReviewEase = 1.3
+	easyBonus         = 1.3
+)
+
+// isValidFlashcardGrade reports whether a flashcard self-grade is known
+func isValidFlashcardGrade(grade string) bool {
+	switch grade {
+	case FlashcardGradeAgain, FlashcardGradeHard, FlashcardGradeGood, FlashcardGradeEasy:
+		return true
+	}
+	return false
+}
+
+// scheduleReview returns the ease and interval in days after a review with the given
+// grade. Again restarts the card, Hard grows it slowly and lowers the ease, Good grows
+// it by the ease and Easy grows it further and raises the ease.
+func scheduleReview(ease float64, intervalDays int, grade string) (float64, int) {
+	if ease <= 0 {
+		ease = defaultReviewEase
+	}
+	switch grade {
+	case FlashcardGradeAgain:
+		return math.Max(minimumReviewEase, ease-0.2), 0
+	case FlashcardGradeHard:
+		return math.Max(minimumReviewEase, ease-0.15), int(math.Max(1, math.Round(float64(intervalDays)*1.2)))
+	case FlashcardGradeEasy:
+		if intervalDays == 0 {
+			return ease + 0.15, 4
+		}
+		return ease + 0.15, int(math.Round(float64(intervalDays) * ease * easyBonus))
+	default:
+		if intervalDays == 0 {
+			return ease, 1
+		}
+		return ease, int(math.Max(float64(intervalDays+1), math.Round(float64(intervalDays)*ease)))
+	}
+}
+
+// Review scheduling database methods
+
+// ReviewWrongQuestion records a review of a wrong question and schedules the next one;
+// a failed review counts as a lapse. Questions that are not on the wrong question list
+// are left alone.
+func (d *Database) ReviewWrongQuestion(questionID, grade string, at time.Time) error {
+	var ease sql.NullFloat64
+	var intervalDays sql.NullInt64
+	err := d.db.QueryRow(`SELECT ease, interval_days FROM wrong_questions WHERE question_id = ?`, questionID).Scan(&ease, &intervalDays)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	nextEase, nextInterval := scheduleReview(ease.Float64, int(intervalDays.Int64), grade)
+	lapse := 0
+	if grade == FlashcardGradeAgain {
+		lapse = 1
+	}
+	dueAt := at.AddDate(0, 0, nextInterval).Format(time.RFC3339)
+	_, err = d.db.Exec(`UPDATE wrong_questions
+			  SET reviewed_at = ?, times_reviewed = times_reviewed + 1, last_result = ?, ease = ?, interval_days = ?, due_at = ?,
+			  lapses = COALESCE(lapses, 0) + ?
+			  WHERE question_id = ?`,
+		at.Format(time.RFC3339), grade != FlashcardGradeAgain, nextEase, nextInterval, dueAt, lapse, questionID)
+	return err
+}
+
+// Flashcard methods
+
+// RevealFlashcard reveals the answer and explanation of a card in a flashcard session
+func (a *App) RevealFlashcard(sessionID, questionID string) (*SessionQuestion, error) {
+	session, err := a.activeFlashcardSession(sessionID, questionID)
+	if err != nil {
+		return nil, err
+	}
+	question, err := a.db.GetQuestionByID(questionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question: %v", err)
+	}
+
+	if !containsString(session.Revealed, questionID) {
+		session.Revealed = append(session.Revealed, questionID)
+		if err := a.saveActiveSession(session); err != nil {
+			return nil, fmt.Errorf("failed to save session: %v", err)
+		}
+	}
+	revealed := revealQuestion(*question)
+	return &revealed, nil
+}
+
+// GradeFlashcard records the learner's self-grade for a revealed card. The grade is
+// stored with the session and schedules the next review when the question is on the
+// wrong question list; cards graded Again are added to it when the session finishes.
+func (a *App) GradeFlashcard(sessionID, questionID, grade string, timeSpent int) error {
+	if !isValidFlashcardGrade(grade) {
+		return fmt.Errorf("unknown flashcard grade %q", grade)
+	}
+	session, err := a.activeFlashcardSession(sessionID, questionID)
+	if err != nil {
+		return err
+	}
+	if !containsString(session.Revealed, questionID) {
+		return fmt.Errorf("reveal the answer of question %s before grading it", questionID)
+	}
+	for _, record := range session.Records {
+		if record.QuestionID == questionID {
+			return fmt.Errorf("question %s has already been graded", questionID)
+		}
+	}
+
+	session.Records = append(session.Records, QuestionRecord{
+		QuestionID: questionID,
+		UserAnswer: []string{},
+		IsCorrect:  grade != FlashcardGradeAgain,
+		TimeSpent:  timeSpent,
+		Grade:      grade,
+	})
+	if err := a.saveActiveSession(session); err != nil {
+		return fmt.Errorf("failed to save session: %v", err)
+	}
+	if err := a.db.ReviewWrongQuestion(questionID, grade, a.now()); err != nil {
+		return fmt.Errorf("failed to schedule review: %v", err)
+	}
+	a.detectLeech(questionID)
+	return nil
+}
+
+// activeFlashcardSession loads a running flashcard session containing the question
+func (a *App) activeFlashcardSession(sessionID, questionID string) (*ActiveSession, error) {
+	session, err := a.db.GetActiveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Mode != PracticeModeFlashcard {
+		return nil, fmt.Errorf("session %s is not a flashcard session", sessionID)
+	}
+	if session.Status != SessionStatusActive {
+		return nil, fmt.Errorf("session %s has already finished", sessionID)
+	}
+	if !containsString(session.QuestionIDs, questionID) {
+		return nil, fmt.Errorf("question %s is not part of the session", questionID)
+	}
+	return session, nil
+}
+
+// GetDueWrongQuestions returns the wrong questions due for review, highest priority and
+// longest overdue first
+func (a *App) GetDueWrongQuestions() ([]Question, error) {
+	wrongQuestions, err := a.db.GetWrongQuestionsWithDetails()
+	if err != nil {
+		return nil, err
+	}
+
+	now := a.now()
+	type dueQuestion struct {
+		question Question
+		priority int
+		dueAt    time.Time
+	}
+	var due []dueQuestion
+	for _, item := range wrongQuestions {
+		wq, _ := item["wrongQuestion"].(WrongQuestion)
+		q, ok := item["question"].(Question)
+		if !ok {
+			continue
+		}
+		dueAt := time.Time{}
+		if wq.DueAt != nil {
+			if dueAt, err = time.Parse(time.RFC3339, *wq.DueAt); err == nil && dueAt.After(now) {
+				continue
+			}
+		}
+		due = append(due, dueQuestion{question: q, priority: wq.Priority, dueAt: dueAt})
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		if due[i].priority != due[j].priority {
+			return due[i].priority > due[j].priority
+		}
+		return due[i].dueAt.Before(due[j].dueAt)
+	})
+
+	questions := make([]Question, 0, len(due))
+	for _, item := range due {
+		questions = append(questions, item.question)
+	}
+	return a.withoutSuspended(questions)
+}
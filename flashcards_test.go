@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestScheduleReview tests interval growth and ease changes for each grade
+func TestScheduleReview(t *testing.T) {
+	ease, interval := scheduleReview(0, 0, FlashcardGradeGood)
+	if ease != defaultReviewEase || interval != 1 {
+		t.Errorf("Expected a first good review to schedule 1 day, got %.2f/%d", ease, interval)
+	}
+	ease, interval = scheduleReview(ease, interval, FlashcardGradeGood)
+	if interval != 3 {
+		t.Errorf("Expected a second good review to schedule 3 days, got %d", interval)
+	}
+	ease, interval = scheduleReview(ease, 10, FlashcardGradeEasy)
+	if ease != 2.65 || interval != 33 {
+		t.Errorf("Expected an easy review to grow the interval and ease, got %.2f/%d", ease, interval)
+	}
+	ease, interval = scheduleReview(1.4, 10, FlashcardGradeAgain)
+	if ease != minimumReviewEase || interval != 0 {
+		t.Errorf("Expected again to restart the card, got %.2f/%d", ease, interval)
+	}
+}
+
+// TestFlashcardSession tests reveal on demand, self-grading and review scheduling
+func TestFlashcardSession(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	data := []map[string]interface{}{
+		{"question": "Capital of France", "options": []string{}, "answer": []string{"Paris"}, "explanation": "Seine"},
+		{"question": "Capital of Italy", "options": []string{}, "answer": []string{"Rome"}, "explanation": "Tiber"},
+	}
+	app.ImportQuestions(data, "")
+
+	session, err := app.StartSession("", PracticeModeFlashcard, nil)
+	if err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	cards := make(map[string]SessionQuestion)
+	for _, q := range session.Questions {
+		cards[q.Question] = q
+	}
+	france, italy := cards["Capital of France"].ID, cards["Capital of Italy"].ID
+	app.AddWrongQuestion(france, "")
+
+	if card := cards["Capital of France"]; card.Answer != nil || card.Explanation != "" {
+		t.Errorf("Expected cards to be served without answers, got %+v", card)
+	}
+	if err := app.GradeFlashcard(session.ID, france, FlashcardGradeGood, 5); err == nil {
+		t.Error("Expected grading before revealing to be rejected")
+	}
+	if _, err := app.SubmitSessionAnswer(session.ID, france, []string{"Paris"}, 5, ""); err == nil {
+		t.Error("Expected multiple choice answers to be rejected in flashcard sessions")
+	}
+
+	card, err := app.RevealFlashcard(session.ID, france)
+	if err != nil {
+		t.Fatalf("Failed to reveal card: %v", err)
+	}
+	if card.Explanation != "Seine" || string(card.Answer) != `["Paris"]` {
+		t.Errorf("Expected the answer to be revealed, got %+v", card)
+	}
+	if err := app.GradeFlashcard(session.ID, france, "perfect", 5); err == nil {
+		t.Error("Expected an unknown grade to be rejected")
+	}
+	if err := app.GradeFlashcard(session.ID, france, FlashcardGradeGood, 5); err != nil {
+		t.Fatalf("Failed to grade card: %v", err)
+	}
+	if err := app.GradeFlashcard(session.ID, france, FlashcardGradeEasy, 5); err == nil {
+		t.Error("Expected grading a card twice to be rejected")
+	}
+
+	session, _ = app.GetSession(session.ID)
+	for _, q := range session.Questions {
+		if (q.Answer != nil) != (q.ID == france) {
+			t.Errorf("Expected only the revealed card to show its answer, got %+v", session.Questions)
+		}
+	}
+
+	app.RevealFlashcard(session.ID, italy)
+	app.GradeFlashcard(session.ID, italy, FlashcardGradeAgain, 8)
+	if _, err := app.FinishSession(session.ID); err != nil {
+		t.Fatalf("Failed to finish session: %v", err)
+	}
+	app.waitForCalibration()
+
+	wrong, _ := app.GetWrongQuestions()
+	scheduled := make(map[string]WrongQuestion)
+	for _, wq := range wrong {
+		scheduled[wq.QuestionID] = wq
+	}
+	if wq := scheduled[france]; wq.TimesReviewed != 1 || wq.IntervalDays != 1 || wq.DueAt == nil || !wq.LastResult {
+		t.Errorf("Expected the good card to be scheduled in a day, got %+v", wq)
+	}
+	if _, ok := scheduled[italy]; !ok {
+		t.Error("Expected the card graded again to be added to the wrong questions")
+	}
+
+	due, _ := app.GetDueWrongQuestions()
+	if len(due) != 1 || due[0].ID != italy {
+		t.Errorf("Expected only the forgotten card to be due, got %+v", questionTexts(due))
+	}
+
+	sessions, _ := app.GetPracticeSessions()
+	if len(sessions) != 1 || sessions[0].Mode != PracticeModeFlashcard || sessions[0].CorrectCount != 1 {
+		t.Fatalf("Expected the flashcard session in the session list, got %+v", sessions)
+	}
+	var records []QuestionRecord
+	json.Unmarshal(sessions[0].Details, &records)
+	grades := make(map[string]string)
+	for _, record := range records {
+		grades[record.QuestionID] = record.Grade
+	}
+	if len(records) != 2 || grades[france] != FlashcardGradeGood || grades[italy] != FlashcardGradeAgain {
+		t.Errorf("Expected grades in the session details, got %+v", records)
+	}
+
+	// Self-grades stay out of item statistics and calibration
+	items, _ := app.AnalyzeItems("")
+	for _, item := range items {
+		if item.Attempts != 0 {
+			t.Errorf("Expected no graded attempts from flashcards, got %+v", item)
+		}
+	}
+	if run, _ := app.GetCalibrationStatus(); run == nil || run.Attempts != 0 {
+		t.Errorf("Expected calibration to skip flashcard grades, got %+v", run)
+	}
+}
@@ -6,34 +6,144 @@ export function AddWrongQuestion(arg1:string,arg2:string):Promise<void>;
 
 export function AddWrongQuestionsFromSession(arg1:Record<string, any>):Promise<void>;
 
+export function AnalyzeItems(arg1:string):Promise<Array<main.ItemAnalysis>>;
+
+export function CalibrateDifficulties(arg1:string):Promise<main.CalibrationRun>;
+
 export function ClearDemoData():Promise<main.ImportResult>;
 
+export function CompleteTimedExamSection(arg1:string):Promise<main.TimedExam>;
+
+export function CreateExamBlueprint(arg1:main.ExamBlueprint):Promise<main.ExamBlueprint>;
+
 export function CreatePracticeSession(arg1:string,arg2:string,arg3:number):Promise<main.PracticeSession>;
 
 export function CreateQuestion(arg1:main.Question):Promise<main.Question>;
 
 export function CreateQuestionGroup(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string):Promise<main.QuestionGroup>;
 
+export function CreateQuestionTemplate(arg1:main.QuestionTemplate):Promise<main.QuestionTemplate>;
+
+export function CreateSmartGroup(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:main.SmartGroupQuery):Promise<main.QuestionGroup>;
+
+export function CreateStudyPlan(arg1:main.StudyPlanOptions):Promise<main.StudyPlan>;
+
+export function DeleteExamBlueprint(arg1:string):Promise<void>;
+
 export function DeleteQuestion(arg1:string):Promise<void>;
 
 export function DeleteQuestionGroup(arg1:string):Promise<void>;
 
+export function DeleteQuestionGroupWithMode(arg1:string,arg2:string):Promise<void>;
+
+export function DeleteQuestionTemplate(arg1:string):Promise<void>;
+
+export function DeleteStudyPlan(arg1:string):Promise<void>;
+
+export function DiffQuestionRevisions(arg1:string,arg2:number,arg3:number):Promise<Array<main.QuestionFieldChange>>;
+
 export function ExportGroupAsCSV(arg1:string):Promise<string>;
 
+export function ExportItemAnalysisCSV(arg1:string):Promise<string>;
+
 export function ExportSelectiveData(arg1:main.ExportOptions):Promise<Record<string, any>>;
 
+export function ExportStudyPlanICS(arg1:string):Promise<string>;
+
 export function ExportUserData():Promise<Record<string, any>>;
 
+export function FinishSession(arg1:string):Promise<main.ActiveSession>;
+
+export function FinishTimedExam(arg1:string):Promise<main.TimedExam>;
+
+export function GenerateExamFromBlueprint(arg1:string):Promise<main.GeneratedExam>;
+
+export function GenerateQuestionFromTemplate(arg1:string,arg2:number):Promise<main.Question>;
+
+export function GetAchievements():Promise<Array<main.Achievement>>;
+
+export function GetActivityHeatmap(arg1:main.TimeSeriesQuery):Promise<Array<main.HeatmapDay>>;
+
+export function GetAdaptiveSession(arg1:string):Promise<main.AdaptiveSessionState>;
+
+export function GetAnswerChangeStats():Promise<main.AnswerChangeStats>;
+
+export function GetCalibrationStatus():Promise<main.CalibrationRun>;
+
+export function GetConfidenceReport():Promise<main.ConfidenceReport>;
+
+export function GetCoverage():Promise<main.CoverageReport>;
+
+export function GetDueWrongQuestions():Promise<Array<main.Question>>;
+
+export function GetExamBlueprint(arg1:string):Promise<main.ExamBlueprint>;
+
+export function GetExamBlueprints():Promise<Array<main.ExamBlueprint>>;
+
+export function GetGoalProgress():Promise<main.GoalProgress>;
+
+export function GetGoals():Promise<Array<main.Goal>>;
+
+export function GetGroupDescendants(arg1:string):Promise<Array<string>>;
+
+export function GetGroupTree(arg1:string):Promise<Array<main.GroupTreeNode>>;
+
+export function GetItemCalibration(arg1:string):Promise<main.ItemCalibration>;
+
+export function GetItemCalibrations():Promise<Array<main.ItemCalibration>>;
+
+export function GetLearnerAbility():Promise<main.LearnerAbility>;
+
+export function GetLearningTimeSeries(arg1:main.TimeSeriesQuery):Promise<main.TimeSeries>;
+
+export function GetLeeches():Promise<Array<main.Leech>>;
+
+export function GetPracticeQuestions(arg1:string):Promise<Array<main.Question>>;
+
 export function GetPracticeSessions():Promise<Array<main.PracticeSession>>;
 
 export function GetQuestionByID(arg1:string):Promise<main.Question>;
 
+export function GetQuestionExposures():Promise<Array<main.QuestionExposure>>;
+
 export function GetQuestionGroups():Promise<Array<main.QuestionGroup>>;
 
+export function GetQuestionRevisions(arg1:string):Promise<Array<main.QuestionRevision>>;
+
+export function GetQuestionTemplate(arg1:string):Promise<main.QuestionTemplate>;
+
+export function GetQuestionTemplates(arg1:string):Promise<Array<main.QuestionTemplate>>;
+
+export function GetQuestionTimelines(arg1:string):Promise<Array<main.QuestionTimeline>>;
+
 export function GetQuestions():Promise<Array<main.Question>>;
 
 export function GetQuestionsByGroup(arg1:string):Promise<Array<main.Question>>;
 
+export function GetReadiness(arg1:string):Promise<main.ReadinessReport>;
+
+export function GetSession(arg1:string):Promise<main.ActiveSession>;
+
+export function GetSessionEvents(arg1:string):Promise<Array<main.SessionEvent>>;
+
+export function GetSessionPacing(arg1:string):Promise<main.SessionPacing>;
+
+export function GetStudyPlan(arg1:string):Promise<main.StudyPlan>;
+
+export function GetStudyPlans():Promise<Array<main.StudyPlan>>;
+
+export function GetStudyRecommendations():Promise<Array<main.StudyRecommendation>>;
+
+export function GetSuspendedQuestions():Promise<Array<main.QuestionSuspension>>;
+
+export function GetTemplateAnalytics():Promise<Array<main.TemplateAnalytics>>;
+
+export function GetTimedExam(arg1:string):Promise<main.TimedExam>;
+
+export function GetTimingReport(arg1:string):Promise<main.TimingReport>;
+
+export function GetTopicRetention():Promise<Array<main.TopicRetention>>;
+
 export function GetUserSetting(arg1:string):Promise<any>;
 
 export function GetUserSettings():Promise<Record<string, any>>;
@@ -46,6 +156,8 @@ export function GetWrongQuestionsForPractice():Promise<Array<main.Question>>;
 
 export function GetWrongQuestionsWithDetails():Promise<Array<Record<string, any>>>;
 
+export function GradeFlashcard(arg1:string,arg2:string,arg3:string,arg4:number):Promise<void>;
+
 export function Greet(arg1:string):Promise<string>;
 
 export function ImportQuestions(arg1:Array<Record<string, any>>,arg2:string):Promise<main.ImportResult>;
@@ -58,22 +170,86 @@ export function InitializeDemoData():Promise<main.ImportResult>;
 
 export function IsQuestionMarkedWrong(arg1:string):Promise<boolean>;
 
+export function ListRecentOperations(arg1:number):Promise<Array<main.JournalOperation>>;
+
+export function ListTagsWithCounts():Promise<Array<main.TagWithCount>>;
+
+export function MergeTags(arg1:string,arg2:string):Promise<main.Tag>;
+
+export function MoveGroup(arg1:string,arg2:string,arg3:number):Promise<void>;
+
+export function MoveQuestionInGroup(arg1:string,arg2:string,arg3:number):Promise<void>;
+
+export function MoveTag(arg1:string,arg2:string):Promise<main.Tag>;
+
+export function PreviewSmartGroup(arg1:main.SmartGroupQuery):Promise<Array<main.Question>>;
+
+export function RecordSessionEvent(arg1:string,arg2:string,arg3:string,arg4:Array<string>):Promise<main.SessionEvent>;
+
+export function Redo():Promise<main.JournalOperation>;
+
+export function RemoveGoal(arg1:string):Promise<void>;
+
 export function RemoveWrongQuestion(arg1:string):Promise<void>;
 
+export function RenameTag(arg1:string,arg2:string):Promise<main.Tag>;
+
+export function ReorderGroupQuestions(arg1:string,arg2:Array<string>):Promise<void>;
+
 export function ResetAllData():Promise<void>;
 
+export function ResetLeech(arg1:string):Promise<void>;
+
+export function ResumeTimedExam(arg1:string):Promise<main.TimedExam>;
+
+export function RevealFlashcard(arg1:string,arg2:string):Promise<main.SessionQuestion>;
+
+export function RollbackQuestion(arg1:string,arg2:number):Promise<main.Question>;
+
 export function SaveFileToDownloads(arg1:string,arg2:string):Promise<string>;
 
 export function SavePracticeSession(arg1:Record<string, any>):Promise<void>;
 
+export function SetGoal(arg1:string,arg2:string,arg3:number):Promise<main.Goal>;
+
 export function SetUserSetting(arg1:string,arg2:any):Promise<void>;
 
+export function StartAdaptiveSession(arg1:string,arg2:main.AdaptiveSessionOptions):Promise<main.AdaptiveSessionState>;
+
+export function StartCalibration(arg1:string):Promise<void>;
+
+export function StartRecommendation(arg1:string):Promise<main.ActiveSession>;
+
+export function StartSession(arg1:string,arg2:string,arg3:Array<string>):Promise<main.ActiveSession>;
+
+export function StartTimedExam(arg1:string):Promise<main.TimedExam>;
+
+export function SubmitAnswerAndGetNext(arg1:string,arg2:string,arg3:Array<string>,arg4:number):Promise<main.AdaptiveSessionState>;
+
+export function SubmitSessionAnswer(arg1:string,arg2:string,arg3:Array<string>,arg4:number,arg5:string):Promise<main.AnswerFeedback>;
+
+export function SubmitTimedExamAnswer(arg1:string,arg2:string,arg3:Array<string>):Promise<main.TimedExam>;
+
+export function SuspendQuestion(arg1:string):Promise<void>;
+
 export function ToggleWrongQuestion(arg1:string,arg2:string):Promise<boolean>;
 
+export function Undo():Promise<main.JournalOperation>;
+
+export function UnsuspendQuestion(arg1:string):Promise<void>;
+
+export function UpdateExamBlueprint(arg1:main.ExamBlueprint):Promise<void>;
+
 export function UpdateQuestion(arg1:main.Question):Promise<void>;
 
 export function UpdateQuestionGroup(arg1:main.QuestionGroup):Promise<void>;
 
+export function UpdateQuestionTemplate(arg1:main.QuestionTemplate):Promise<void>;
+
+export function UpdateQuestionWithReason(arg1:main.Question,arg2:string):Promise<void>;
+
+export function UpdateSmartGroupQuery(arg1:string,arg2:main.SmartGroupQuery):Promise<void>;
+
 export function UpdateUserSettings(arg1:Record<string, any>):Promise<void>;
 
 export function UpdateWrongQuestionReview(arg1:string,arg2:boolean,arg3:string):Promise<void>;
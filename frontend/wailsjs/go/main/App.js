@@ -10,10 +10,26 @@ export function AddWrongQuestionsFromSession(arg1) {
   return window['go']['main']['App']['AddWrongQuestionsFromSession'](arg1);
 }
 
+export function AnalyzeItems(arg1) {
+  return window['go']['main']['App']['AnalyzeItems'](arg1);
+}
+
+export function CalibrateDifficulties(arg1) {
+  return window['go']['main']['App']['CalibrateDifficulties'](arg1);
+}
+
 export function ClearDemoData() {
   return window['go']['main']['App']['ClearDemoData']();
 }
 
+export function CompleteTimedExamSection(arg1) {
+  return window['go']['main']['App']['CompleteTimedExamSection'](arg1);
+}
+
+export function CreateExamBlueprint(arg1) {
+  return window['go']['main']['App']['CreateExamBlueprint'](arg1);
+}
+
 export function CreatePracticeSession(arg1, arg2, arg3) {
   return window['go']['main']['App']['CreatePracticeSession'](arg1, arg2, arg3);
 }
@@ -26,6 +42,22 @@ export function CreateQuestionGroup(arg1, arg2, arg3, arg4, arg5) {
   return window['go']['main']['App']['CreateQuestionGroup'](arg1, arg2, arg3, arg4, arg5);
 }
 
+export function CreateQuestionTemplate(arg1) {
+  return window['go']['main']['App']['CreateQuestionTemplate'](arg1);
+}
+
+export function CreateSmartGroup(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['CreateSmartGroup'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
+export function CreateStudyPlan(arg1) {
+  return window['go']['main']['App']['CreateStudyPlan'](arg1);
+}
+
+export function DeleteExamBlueprint(arg1) {
+  return window['go']['main']['App']['DeleteExamBlueprint'](arg1);
+}
+
 export function DeleteQuestion(arg1) {
   return window['go']['main']['App']['DeleteQuestion'](arg1);
 }
@@ -34,18 +66,138 @@ export function DeleteQuestionGroup(arg1) {
   return window['go']['main']['App']['DeleteQuestionGroup'](arg1);
 }
 
+export function DeleteQuestionGroupWithMode(arg1, arg2) {
+  return window['go']['main']['App']['DeleteQuestionGroupWithMode'](arg1, arg2);
+}
+
+export function DeleteQuestionTemplate(arg1) {
+  return window['go']['main']['App']['DeleteQuestionTemplate'](arg1);
+}
+
+export function DeleteStudyPlan(arg1) {
+  return window['go']['main']['App']['DeleteStudyPlan'](arg1);
+}
+
+export function DiffQuestionRevisions(arg1, arg2, arg3) {
+  return window['go']['main']['App']['DiffQuestionRevisions'](arg1, arg2, arg3);
+}
+
 export function ExportGroupAsCSV(arg1) {
   return window['go']['main']['App']['ExportGroupAsCSV'](arg1);
 }
 
+export function ExportItemAnalysisCSV(arg1) {
+  return window['go']['main']['App']['ExportItemAnalysisCSV'](arg1);
+}
+
 export function ExportSelectiveData(arg1) {
   return window['go']['main']['App']['ExportSelectiveData'](arg1);
 }
 
+export function ExportStudyPlanICS(arg1) {
+  return window['go']['main']['App']['ExportStudyPlanICS'](arg1);
+}
+
 export function ExportUserData() {
   return window['go']['main']['App']['ExportUserData']();
 }
 
+export function FinishSession(arg1) {
+  return window['go']['main']['App']['FinishSession'](arg1);
+}
+
+export function FinishTimedExam(arg1) {
+  return window['go']['main']['App']['FinishTimedExam'](arg1);
+}
+
+export function GenerateExamFromBlueprint(arg1) {
+  return window['go']['main']['App']['GenerateExamFromBlueprint'](arg1);
+}
+
+export function GenerateQuestionFromTemplate(arg1, arg2) {
+  return window['go']['main']['App']['GenerateQuestionFromTemplate'](arg1, arg2);
+}
+
+export function GetAchievements() {
+  return window['go']['main']['App']['GetAchievements']();
+}
+
+export function GetActivityHeatmap(arg1) {
+  return window['go']['main']['App']['GetActivityHeatmap'](arg1);
+}
+
+export function GetAdaptiveSession(arg1) {
+  return window['go']['main']['App']['GetAdaptiveSession'](arg1);
+}
+
+export function GetAnswerChangeStats() {
+  return window['go']['main']['App']['GetAnswerChangeStats']();
+}
+
+export function GetCalibrationStatus() {
+  return window['go']['main']['App']['GetCalibrationStatus']();
+}
+
+export function GetConfidenceReport() {
+  return window['go']['main']['App']['GetConfidenceReport']();
+}
+
+export function GetCoverage() {
+  return window['go']['main']['App']['GetCoverage']();
+}
+
+export function GetDueWrongQuestions() {
+  return window['go']['main']['App']['GetDueWrongQuestions']();
+}
+
+export function GetExamBlueprint(arg1) {
+  return window['go']['main']['App']['GetExamBlueprint'](arg1);
+}
+
+export function GetExamBlueprints() {
+  return window['go']['main']['App']['GetExamBlueprints']();
+}
+
+export function GetGoalProgress() {
+  return window['go']['main']['App']['GetGoalProgress']();
+}
+
+export function GetGoals() {
+  return window['go']['main']['App']['GetGoals']();
+}
+
+export function GetGroupDescendants(arg1) {
+  return window['go']['main']['App']['GetGroupDescendants'](arg1);
+}
+
+export function GetGroupTree(arg1) {
+  return window['go']['main']['App']['GetGroupTree'](arg1);
+}
+
+export function GetItemCalibration(arg1) {
+  return window['go']['main']['App']['GetItemCalibration'](arg1);
+}
+
+export function GetItemCalibrations() {
+  return window['go']['main']['App']['GetItemCalibrations']();
+}
+
+export function GetLearnerAbility() {
+  return window['go']['main']['App']['GetLearnerAbility']();
+}
+
+export function GetLearningTimeSeries(arg1) {
+  return window['go']['main']['App']['GetLearningTimeSeries'](arg1);
+}
+
+export function GetLeeches() {
+  return window['go']['main']['App']['GetLeeches']();
+}
+
+export function GetPracticeQuestions(arg1) {
+  return window['go']['main']['App']['GetPracticeQuestions'](arg1);
+}
+
 export function GetPracticeSessions() {
   return window['go']['main']['App']['GetPracticeSessions']();
 }
@@ -54,10 +206,30 @@ export function GetQuestionByID(arg1) {
   return window['go']['main']['App']['GetQuestionByID'](arg1);
 }
 
+export function GetQuestionExposures() {
+  return window['go']['main']['App']['GetQuestionExposures']();
+}
+
 export function GetQuestionGroups() {
   return window['go']['main']['App']['GetQuestionGroups']();
 }
 
+export function GetQuestionRevisions(arg1) {
+  return window['go']['main']['App']['GetQuestionRevisions'](arg1);
+}
+
+export function GetQuestionTemplate(arg1) {
+  return window['go']['main']['App']['GetQuestionTemplate'](arg1);
+}
+
+export function GetQuestionTemplates(arg1) {
+  return window['go']['main']['App']['GetQuestionTemplates'](arg1);
+}
+
+export function GetQuestionTimelines(arg1) {
+  return window['go']['main']['App']['GetQuestionTimelines'](arg1);
+}
+
 export function GetQuestions() {
   return window['go']['main']['App']['GetQuestions']();
 }
@@ -66,6 +238,54 @@ export function GetQuestionsByGroup(arg1) {
   return window['go']['main']['App']['GetQuestionsByGroup'](arg1);
 }
 
+export function GetReadiness(arg1) {
+  return window['go']['main']['App']['GetReadiness'](arg1);
+}
+
+export function GetSession(arg1) {
+  return window['go']['main']['App']['GetSession'](arg1);
+}
+
+export function GetSessionEvents(arg1) {
+  return window['go']['main']['App']['GetSessionEvents'](arg1);
+}
+
+export function GetSessionPacing(arg1) {
+  return window['go']['main']['App']['GetSessionPacing'](arg1);
+}
+
+export function GetStudyPlan(arg1) {
+  return window['go']['main']['App']['GetStudyPlan'](arg1);
+}
+
+export function GetStudyPlans() {
+  return window['go']['main']['App']['GetStudyPlans']();
+}
+
+export function GetStudyRecommendations() {
+  return window['go']['main']['App']['GetStudyRecommendations']();
+}
+
+export function GetSuspendedQuestions() {
+  return window['go']['main']['App']['GetSuspendedQuestions']();
+}
+
+export function GetTemplateAnalytics() {
+  return window['go']['main']['App']['GetTemplateAnalytics']();
+}
+
+export function GetTimedExam(arg1) {
+  return window['go']['main']['App']['GetTimedExam'](arg1);
+}
+
+export function GetTimingReport(arg1) {
+  return window['go']['main']['App']['GetTimingReport'](arg1);
+}
+
+export function GetTopicRetention() {
+  return window['go']['main']['App']['GetTopicRetention']();
+}
+
 export function GetUserSetting(arg1) {
   return window['go']['main']['App']['GetUserSetting'](arg1);
 }
@@ -90,6 +310,10 @@ export function GetWrongQuestionsWithDetails() {
   return window['go']['main']['App']['GetWrongQuestionsWithDetails']();
 }
 
+export function GradeFlashcard(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GradeFlashcard'](arg1, arg2, arg3, arg4);
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
@@ -114,14 +338,78 @@ export function IsQuestionMarkedWrong(arg1) {
   return window['go']['main']['App']['IsQuestionMarkedWrong'](arg1);
 }
 
+export function ListRecentOperations(arg1) {
+  return window['go']['main']['App']['ListRecentOperations'](arg1);
+}
+
+export function ListTagsWithCounts() {
+  return window['go']['main']['App']['ListTagsWithCounts']();
+}
+
+export function MergeTags(arg1, arg2) {
+  return window['go']['main']['App']['MergeTags'](arg1, arg2);
+}
+
+export function MoveGroup(arg1, arg2, arg3) {
+  return window['go']['main']['App']['MoveGroup'](arg1, arg2, arg3);
+}
+
+export function MoveQuestionInGroup(arg1, arg2, arg3) {
+  return window['go']['main']['App']['MoveQuestionInGroup'](arg1, arg2, arg3);
+}
+
+export function MoveTag(arg1, arg2) {
+  return window['go']['main']['App']['MoveTag'](arg1, arg2);
+}
+
+export function PreviewSmartGroup(arg1) {
+  return window['go']['main']['App']['PreviewSmartGroup'](arg1);
+}
+
+export function RecordSessionEvent(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['RecordSessionEvent'](arg1, arg2, arg3, arg4);
+}
+
+export function Redo() {
+  return window['go']['main']['App']['Redo']();
+}
+
+export function RemoveGoal(arg1) {
+  return window['go']['main']['App']['RemoveGoal'](arg1);
+}
+
 export function RemoveWrongQuestion(arg1) {
   return window['go']['main']['App']['RemoveWrongQuestion'](arg1);
 }
 
+export function RenameTag(arg1, arg2) {
+  return window['go']['main']['App']['RenameTag'](arg1, arg2);
+}
+
+export function ReorderGroupQuestions(arg1, arg2) {
+  return window['go']['main']['App']['ReorderGroupQuestions'](arg1, arg2);
+}
+
 export function ResetAllData() {
   return window['go']['main']['App']['ResetAllData']();
 }
 
+export function ResetLeech(arg1) {
+  return window['go']['main']['App']['ResetLeech'](arg1);
+}
+
+export function ResumeTimedExam(arg1) {
+  return window['go']['main']['App']['ResumeTimedExam'](arg1);
+}
+
+export function RevealFlashcard(arg1, arg2) {
+  return window['go']['main']['App']['RevealFlashcard'](arg1, arg2);
+}
+
+export function RollbackQuestion(arg1, arg2) {
+  return window['go']['main']['App']['RollbackQuestion'](arg1, arg2);
+}
+
 export function SaveFileToDownloads(arg1, arg2) {
   return window['go']['main']['App']['SaveFileToDownloads'](arg1, arg2);
 }
@@ -130,14 +418,66 @@ export function SavePracticeSession(arg1) {
   return window['go']['main']['App']['SavePracticeSession'](arg1);
 }
 
+export function SetGoal(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetGoal'](arg1, arg2, arg3);
+}
+
 export function SetUserSetting(arg1, arg2) {
   return window['go']['main']['App']['SetUserSetting'](arg1, arg2);
 }
 
+export function StartAdaptiveSession(arg1, arg2) {
+  return window['go']['main']['App']['StartAdaptiveSession'](arg1, arg2);
+}
+
+export function StartCalibration(arg1) {
+  return window['go']['main']['App']['StartCalibration'](arg1);
+}
+
+export function StartRecommendation(arg1) {
+  return window['go']['main']['App']['StartRecommendation'](arg1);
+}
+
+export function StartSession(arg1, arg2, arg3) {
+  return window['go']['main']['App']['StartSession'](arg1, arg2, arg3);
+}
+
+export function StartTimedExam(arg1) {
+  return window['go']['main']['App']['StartTimedExam'](arg1);
+}
+
+export function SubmitAnswerAndGetNext(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SubmitAnswerAndGetNext'](arg1, arg2, arg3, arg4);
+}
+
+export function SubmitSessionAnswer(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['SubmitSessionAnswer'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function SubmitTimedExamAnswer(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SubmitTimedExamAnswer'](arg1, arg2, arg3);
+}
+
+export function SuspendQuestion(arg1) {
+  return window['go']['main']['App']['SuspendQuestion'](arg1);
+}
+
 export function ToggleWrongQuestion(arg1, arg2) {
   return window['go']['main']['App']['ToggleWrongQuestion'](arg1, arg2);
 }
 
+export function Undo() {
+  return window['go']['main']['App']['Undo']();
+}
+
+export function UnsuspendQuestion(arg1) {
+  return window['go']['main']['App']['UnsuspendQuestion'](arg1);
+}
+
+export function UpdateExamBlueprint(arg1) {
+  return window['go']['main']['App']['UpdateExamBlueprint'](arg1);
+}
+
 export function UpdateQuestion(arg1) {
   return window['go']['main']['App']['UpdateQuestion'](arg1);
 }
@@ -146,6 +486,18 @@ export function UpdateQuestionGroup(arg1) {
   return window['go']['main']['App']['UpdateQuestionGroup'](arg1);
 }
 
+export function UpdateQuestionTemplate(arg1) {
+  return window['go']['main']['App']['UpdateQuestionTemplate'](arg1);
+}
+
+export function UpdateQuestionWithReason(arg1, arg2) {
+  return window['go']['main']['App']['UpdateQuestionWithReason'](arg1, arg2);
+}
+
+export function UpdateSmartGroupQuery(arg1, arg2) {
+  return window['go']['main']['App']['UpdateSmartGroupQuery'](arg1, arg2);
+}
+
 export function UpdateUserSettings(arg1) {
   return window['go']['main']['App']['UpdateUserSettings'](arg1);
 }
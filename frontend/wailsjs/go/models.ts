@@ -1,43 +1,107 @@
 export namespace main {
 	
-	export class DateRange {
-	    startDate: string;
-	    endDate: string;
+	export class Achievement {
+	    id: string;
+	    title: string;
+	    description: string;
+	    unlocked: boolean;
+	    unlockedAt?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new DateRange(source);
+	        return new Achievement(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.startDate = source["startDate"];
-	        this.endDate = source["endDate"];
+	        this.id = source["id"];
+	        this.title = source["title"];
+	        this.description = source["description"];
+	        this.unlocked = source["unlocked"];
+	        this.unlockedAt = source["unlockedAt"];
 	    }
 	}
-	export class ExportOptions {
-	    includeQuestions: boolean;
-	    includeGroups: boolean;
-	    includeSessions: boolean;
-	    includeSettings: boolean;
-	    includeWrongQuestions: boolean;
-	    groupIds: string[];
-	    dateRange?: DateRange;
-	    format: string;
+	export class SessionQuestion {
+	    id: string;
+	    question: string;
+	    options: number[];
+	    answer?: number[];
+	    explanation?: string;
+	    tags: number[];
+	    imageUrl: string;
+	    difficulty?: number;
+	    multiAnswer: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new ExportOptions(source);
+	        return new SessionQuestion(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.includeQuestions = source["includeQuestions"];
-	        this.includeGroups = source["includeGroups"];
-	        this.includeSessions = source["includeSessions"];
-	        this.includeSettings = source["includeSettings"];
-	        this.includeWrongQuestions = source["includeWrongQuestions"];
-	        this.groupIds = source["groupIds"];
-	        this.dateRange = this.convertValues(source["dateRange"], DateRange);
-	        this.format = source["format"];
+	        this.id = source["id"];
+	        this.question = source["question"];
+	        this.options = source["options"];
+	        this.answer = source["answer"];
+	        this.explanation = source["explanation"];
+	        this.tags = source["tags"];
+	        this.imageUrl = source["imageUrl"];
+	        this.difficulty = source["difficulty"];
+	        this.multiAnswer = source["multiAnswer"];
+	    }
+	}
+	export class QuestionRecord {
+	    questionId: string;
+	    userAnswer: string[];
+	    isCorrect: boolean;
+	    timeSpent: number;
+	    marked: boolean;
+	    confidence?: string;
+	    grade?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QuestionRecord(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.questionId = source["questionId"];
+	        this.userAnswer = source["userAnswer"];
+	        this.isCorrect = source["isCorrect"];
+	        this.timeSpent = source["timeSpent"];
+	        this.marked = source["marked"];
+	        this.confidence = source["confidence"];
+	        this.grade = source["grade"];
+	    }
+	}
+	export class ActiveSession {
+	    id: string;
+	    groupId: string;
+	    mode: string;
+	    status: string;
+	    questionIds: string[];
+	    records: QuestionRecord[];
+	    correctCount: number;
+	    revealed: string[];
+	    startedAt: string;
+	    finishedAt?: string;
+	    questions: SessionQuestion[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ActiveSession(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.groupId = source["groupId"];
+	        this.mode = source["mode"];
+	        this.status = source["status"];
+	        this.questionIds = source["questionIds"];
+	        this.records = this.convertValues(source["records"], QuestionRecord);
+	        this.correctCount = source["correctCount"];
+	        this.revealed = source["revealed"];
+	        this.startedAt = source["startedAt"];
+	        this.finishedAt = source["finishedAt"];
+	        this.questions = this.convertValues(source["questions"], SessionQuestion);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -58,138 +122,2063 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class ImportResult {
-	    success: boolean;
-	    imported: number;
-	    errors: string[];
-	    duplicates: number;
+	export class AdaptiveSessionOptions {
+	    maxQuestions: number;
+	    minQuestions: number;
+	    targetStandardError: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new ImportResult(source);
+	        return new AdaptiveSessionOptions(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.success = source["success"];
-	        this.imported = source["imported"];
-	        this.errors = source["errors"];
-	        this.duplicates = source["duplicates"];
+	        this.maxQuestions = source["maxQuestions"];
+	        this.minQuestions = source["minQuestions"];
+	        this.targetStandardError = source["targetStandardError"];
 	    }
 	}
-	export class PracticeSession {
-	    id: string;
+	export class AdaptiveSessionState {
+	    sessionId: string;
 	    groupId: string;
-	    mode: string;
-	    startTime: string;
-	    endTime?: string;
-	    duration: number;
-	    totalQuestions: number;
+	    status: string;
+	    stopReason: string;
+	    options: AdaptiveSessionOptions;
+	    answered: number;
 	    correctCount: number;
-	    details: number[];
-	    createdAt: string;
+	    ability: number;
+	    standardError: number;
+	    confidenceLow: number;
+	    confidenceHigh: number;
+	    nextQuestionId: string;
+	    nextQuestion?: SessionQuestion;
+	    records: QuestionRecord[];
+	    startedAt: string;
+	    finishedAt?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new PracticeSession(source);
+	        return new AdaptiveSessionState(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
+	        this.sessionId = source["sessionId"];
 	        this.groupId = source["groupId"];
-	        this.mode = source["mode"];
-	        this.startTime = source["startTime"];
-	        this.endTime = source["endTime"];
-	        this.duration = source["duration"];
-	        this.totalQuestions = source["totalQuestions"];
+	        this.status = source["status"];
+	        this.stopReason = source["stopReason"];
+	        this.options = this.convertValues(source["options"], AdaptiveSessionOptions);
+	        this.answered = source["answered"];
 	        this.correctCount = source["correctCount"];
-	        this.details = source["details"];
-	        this.createdAt = source["createdAt"];
+	        this.ability = source["ability"];
+	        this.standardError = source["standardError"];
+	        this.confidenceLow = source["confidenceLow"];
+	        this.confidenceHigh = source["confidenceHigh"];
+	        this.nextQuestionId = source["nextQuestionId"];
+	        this.nextQuestion = this.convertValues(source["nextQuestion"], SessionQuestion);
+	        this.records = this.convertValues(source["records"], QuestionRecord);
+	        this.startedAt = source["startedAt"];
+	        this.finishedAt = source["finishedAt"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class Question {
-	    id: string;
-	    question: string;
-	    options: number[];
-	    answer: number[];
-	    explanation: string;
-	    tags: number[];
-	    imageUrl: string;
-	    difficulty?: number;
-	    source: string;
-	    index?: number;
-	    createdAt: string;
-	    updatedAt: string;
+	export class AnswerChangeStats {
+	    changes: number;
+	    wrongToRight: number;
+	    rightToWrong: number;
+	    wrongToWrong: number;
+	    netGain: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new Question(source);
+	        return new AnswerChangeStats(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.question = source["question"];
-	        this.options = source["options"];
+	        this.changes = source["changes"];
+	        this.wrongToRight = source["wrongToRight"];
+	        this.rightToWrong = source["rightToWrong"];
+	        this.wrongToWrong = source["wrongToWrong"];
+	        this.netGain = source["netGain"];
+	    }
+	}
+	export class AnswerFeedback {
+	    questionId: string;
+	    revealed: boolean;
+	    isCorrect: boolean;
+	    answer?: number[];
+	    explanation?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AnswerFeedback(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.questionId = source["questionId"];
+	        this.revealed = source["revealed"];
+	        this.isCorrect = source["isCorrect"];
 	        this.answer = source["answer"];
 	        this.explanation = source["explanation"];
-	        this.tags = source["tags"];
-	        this.imageUrl = source["imageUrl"];
-	        this.difficulty = source["difficulty"];
-	        this.source = source["source"];
-	        this.index = source["index"];
-	        this.createdAt = source["createdAt"];
-	        this.updatedAt = source["updatedAt"];
 	    }
 	}
-	export class QuestionGroup {
-	    id: string;
+	export class BlueprintSection {
 	    name: string;
-	    description: string;
-	    parentId?: string;
-	    color: string;
-	    icon: string;
-	    questionIds: string[];
-	    createdAt: string;
-	    updatedAt: string;
+	    tag: string;
+	    groupId: string;
+	    weight: number;
+	    count: number;
+	    timeLimit: number;
+	    difficulty: Record<number, number>;
 	
 	    static createFrom(source: any = {}) {
-	        return new QuestionGroup(source);
+	        return new BlueprintSection(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
 	        this.name = source["name"];
-	        this.description = source["description"];
-	        this.parentId = source["parentId"];
-	        this.color = source["color"];
-	        this.icon = source["icon"];
-	        this.questionIds = source["questionIds"];
-	        this.createdAt = source["createdAt"];
-	        this.updatedAt = source["updatedAt"];
+	        this.tag = source["tag"];
+	        this.groupId = source["groupId"];
+	        this.weight = source["weight"];
+	        this.count = source["count"];
+	        this.timeLimit = source["timeLimit"];
+	        this.difficulty = source["difficulty"];
 	    }
 	}
-	export class WrongQuestion {
-	    id: string;
-	    questionId: string;
-	    addedAt: string;
-	    reviewedAt?: string;
-	    timesReviewed: number;
-	    lastResult: boolean;
-	    notes: string;
+	export class BlueprintShortfall {
+	    section: string;
+	    difficulty?: number;
+	    requested: number;
+	    available: number;
+	    substituted: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new WrongQuestion(source);
+	        return new BlueprintShortfall(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.section = source["section"];
+	        this.difficulty = source["difficulty"];
+	        this.requested = source["requested"];
+	        this.available = source["available"];
+	        this.substituted = source["substituted"];
+	    }
+	}
+	export class CalibrationRun {
+	    id: number;
+	    model: string;
+	    status: string;
+	    items: number;
+	    attempts: number;
+	    iterations: number;
+	    ability?: number;
+	    abilitySe?: number;
+	    error: string;
+	    startedAt: string;
+	    finishedAt?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CalibrationRun(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
+	        this.model = source["model"];
+	        this.status = source["status"];
+	        this.items = source["items"];
+	        this.attempts = source["attempts"];
+	        this.iterations = source["iterations"];
+	        this.ability = source["ability"];
+	        this.abilitySe = source["abilitySe"];
+	        this.error = source["error"];
+	        this.startedAt = source["startedAt"];
+	        this.finishedAt = source["finishedAt"];
+	    }
+	}
+	export class ConfidenceBucket {
+	    confidence: string;
+	    attempts: number;
+	    correct: number;
+	    accuracy: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfidenceBucket(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.confidence = source["confidence"];
+	        this.attempts = source["attempts"];
+	        this.correct = source["correct"];
+	        this.accuracy = source["accuracy"];
+	    }
+	}
+	export class ConfidenceTrendPoint {
+	    weekStart: string;
+	    ratedAttempts: number;
+	    sureAttempts: number;
+	    sureAccuracy: number;
+	    overconfidence: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfidenceTrendPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.weekStart = source["weekStart"];
+	        this.ratedAttempts = source["ratedAttempts"];
+	        this.sureAttempts = source["sureAttempts"];
+	        this.sureAccuracy = source["sureAccuracy"];
+	        this.overconfidence = source["overconfidence"];
+	    }
+	}
+	export class ConfidentlyWrongItem {
+	    questionId: string;
+	    question: string;
+	    tags: number[];
+	    count: number;
+	    lastAnsweredAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfidentlyWrongItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.questionId = source["questionId"];
-	        this.addedAt = source["addedAt"];
-	        this.reviewedAt = source["reviewedAt"];
-	        this.timesReviewed = source["timesReviewed"];
-	        this.lastResult = source["lastResult"];
-	        this.notes = source["notes"];
+	        this.question = source["question"];
+	        this.tags = source["tags"];
+	        this.count = source["count"];
+	        this.lastAnsweredAt = source["lastAnsweredAt"];
+	    }
+	}
+	export class ConfidenceReport {
+	    ratedAttempts: number;
+	    buckets: ConfidenceBucket[];
+	    confidentlyWrong: ConfidentlyWrongItem[];
+	    trend: ConfidenceTrendPoint[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfidenceReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ratedAttempts = source["ratedAttempts"];
+	        this.buckets = this.convertValues(source["buckets"], ConfidenceBucket);
+	        this.confidentlyWrong = this.convertValues(source["confidentlyWrong"], ConfidentlyWrongItem);
+	        this.trend = this.convertValues(source["trend"], ConfidenceTrendPoint);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class CoverageStats {
+	    key: string;
+	    name: string;
+	    total: number;
+	    seen: number;
+	    mastered: number;
+	    neverSeen: number;
+	    seenPercent: number;
+	    masteredPercent: number;
+	    neverSeenPercent: number;
+	    exposures: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CoverageStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.name = source["name"];
+	        this.total = source["total"];
+	        this.seen = source["seen"];
+	        this.mastered = source["mastered"];
+	        this.neverSeen = source["neverSeen"];
+	        this.seenPercent = source["seenPercent"];
+	        this.masteredPercent = source["masteredPercent"];
+	        this.neverSeenPercent = source["neverSeenPercent"];
+	        this.exposures = source["exposures"];
+	    }
+	}
+	export class CoverageReport {
+	    overall: CoverageStats;
+	    groups: CoverageStats[];
+	    tags: CoverageStats[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CoverageReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.overall = this.convertValues(source["overall"], CoverageStats);
+	        this.groups = this.convertValues(source["groups"], CoverageStats);
+	        this.tags = this.convertValues(source["tags"], CoverageStats);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class DateRange {
+	    startDate: string;
+	    endDate: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DateRange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.startDate = source["startDate"];
+	        this.endDate = source["endDate"];
+	    }
+	}
+	export class ExamBreak {
+	    afterSection: number;
+	    duration: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExamBreak(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.afterSection = source["afterSection"];
+	        this.duration = source["duration"];
+	    }
+	}
+	export class ExamBlueprint {
+	    id: string;
+	    name: string;
+	    description: string;
+	    totalQuestions: number;
+	    timeLimit: number;
+	    avoidRecentDays: number;
+	    difficulty: Record<number, number>;
+	    sections: BlueprintSection[];
+	    breaks: ExamBreak[];
+	    passingScore: number;
+	    createdAt: string;
+	    updatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExamBlueprint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.totalQuestions = source["totalQuestions"];
+	        this.timeLimit = source["timeLimit"];
+	        this.avoidRecentDays = source["avoidRecentDays"];
+	        this.difficulty = source["difficulty"];
+	        this.sections = this.convertValues(source["sections"], BlueprintSection);
+	        this.breaks = this.convertValues(source["breaks"], ExamBreak);
+	        this.passingScore = source["passingScore"];
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ExportOptions {
+	    includeQuestions: boolean;
+	    includeGroups: boolean;
+	    includeSessions: boolean;
+	    includeSettings: boolean;
+	    includeWrongQuestions: boolean;
+	    groupIds: string[];
+	    dateRange?: DateRange;
+	    format: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.includeQuestions = source["includeQuestions"];
+	        this.includeGroups = source["includeGroups"];
+	        this.includeSessions = source["includeSessions"];
+	        this.includeSettings = source["includeSettings"];
+	        this.includeWrongQuestions = source["includeWrongQuestions"];
+	        this.groupIds = source["groupIds"];
+	        this.dateRange = this.convertValues(source["dateRange"], DateRange);
+	        this.format = source["format"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Question {
+	    id: string;
+	    question: string;
+	    options: number[];
+	    answer: number[];
+	    explanation: string;
+	    tags: number[];
+	    imageUrl: string;
+	    difficulty?: number;
+	    source: string;
+	    index?: number;
+	    createdAt: string;
+	    updatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Question(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.question = source["question"];
+	        this.options = source["options"];
+	        this.answer = source["answer"];
+	        this.explanation = source["explanation"];
+	        this.tags = source["tags"];
+	        this.imageUrl = source["imageUrl"];
+	        this.difficulty = source["difficulty"];
+	        this.source = source["source"];
+	        this.index = source["index"];
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	}
+	export class GeneratedExamSection {
+	    name: string;
+	    timeLimit: number;
+	    requested: number;
+	    recentlySeen: number;
+	    questions: Question[];
+	
+	    static createFrom(source: any = {}) {
+	        return new GeneratedExamSection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.timeLimit = source["timeLimit"];
+	        this.requested = source["requested"];
+	        this.recentlySeen = source["recentlySeen"];
+	        this.questions = this.convertValues(source["questions"], Question);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GeneratedExam {
+	    blueprintId: string;
+	    name: string;
+	    totalQuestions: number;
+	    timeLimit: number;
+	    sections: GeneratedExamSection[];
+	    shortfalls: BlueprintShortfall[];
+	    generatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GeneratedExam(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.blueprintId = source["blueprintId"];
+	        this.name = source["name"];
+	        this.totalQuestions = source["totalQuestions"];
+	        this.timeLimit = source["timeLimit"];
+	        this.sections = this.convertValues(source["sections"], GeneratedExamSection);
+	        this.shortfalls = this.convertValues(source["shortfalls"], BlueprintShortfall);
+	        this.generatedAt = source["generatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Goal {
+	    id: string;
+	    period: string;
+	    metric: string;
+	    target: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Goal(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.period = source["period"];
+	        this.metric = source["metric"];
+	        this.target = source["target"];
+	    }
+	}
+	export class GoalStatus {
+	    goal: Goal;
+	    current: number;
+	    progress: number;
+	    met: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GoalStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.goal = this.convertValues(source["goal"], Goal);
+	        this.current = source["current"];
+	        this.progress = source["progress"];
+	        this.met = source["met"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GoalProgress {
+	    goals: GoalStatus[];
+	    todayComplete: boolean;
+	    weekComplete: boolean;
+	    currentStreak: number;
+	    longestStreak: number;
+	    graceDays: number;
+	    graceDaysLeft: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GoalProgress(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.goals = this.convertValues(source["goals"], GoalStatus);
+	        this.todayComplete = source["todayComplete"];
+	        this.weekComplete = source["weekComplete"];
+	        this.currentStreak = source["currentStreak"];
+	        this.longestStreak = source["longestStreak"];
+	        this.graceDays = source["graceDays"];
+	        this.graceDaysLeft = source["graceDaysLeft"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class GroupTreeNode {
+	    id: string;
+	    name: string;
+	    description: string;
+	    parentId?: string;
+	    color: string;
+	    icon: string;
+	    position: number;
+	    depth: number;
+	    questionCount: number;
+	    totalQuestionCount: number;
+	    children: GroupTreeNode[];
+	
+	    static createFrom(source: any = {}) {
+	        return new GroupTreeNode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.parentId = source["parentId"];
+	        this.color = source["color"];
+	        this.icon = source["icon"];
+	        this.position = source["position"];
+	        this.depth = source["depth"];
+	        this.questionCount = source["questionCount"];
+	        this.totalQuestionCount = source["totalQuestionCount"];
+	        this.children = this.convertValues(source["children"], GroupTreeNode);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HeatmapDay {
+	    date: string;
+	    questions: number;
+	    studyMinutes: number;
+	    level: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HeatmapDay(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.questions = source["questions"];
+	        this.studyMinutes = source["studyMinutes"];
+	        this.level = source["level"];
+	    }
+	}
+	export class ImportResult {
+	    success: boolean;
+	    imported: number;
+	    errors: string[];
+	    duplicates: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.imported = source["imported"];
+	        this.errors = source["errors"];
+	        this.duplicates = source["duplicates"];
+	    }
+	}
+	export class OptionStatistics {
+	    optionId: string;
+	    text: string;
+	    isKey: boolean;
+	    count: number;
+	    frequency: number;
+	    flags: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new OptionStatistics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.optionId = source["optionId"];
+	        this.text = source["text"];
+	        this.isKey = source["isKey"];
+	        this.count = source["count"];
+	        this.frequency = source["frequency"];
+	        this.flags = source["flags"];
+	    }
+	}
+	export class ItemAnalysis {
+	    questionId: string;
+	    question: string;
+	    attempts: number;
+	    correct: number;
+	    omitted: number;
+	    pValue: number;
+	    pointBiserial?: number;
+	    options: OptionStatistics[];
+	    flags: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ItemAnalysis(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.questionId = source["questionId"];
+	        this.question = source["question"];
+	        this.attempts = source["attempts"];
+	        this.correct = source["correct"];
+	        this.omitted = source["omitted"];
+	        this.pValue = source["pValue"];
+	        this.pointBiserial = source["pointBiserial"];
+	        this.options = this.convertValues(source["options"], OptionStatistics);
+	        this.flags = source["flags"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ItemCalibration {
+	    questionId: string;
+	    model: string;
+	    difficulty: number;
+	    discrimination: number;
+	    difficultySe: number;
+	    attempts: number;
+	    correct: number;
+	    calibratedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ItemCalibration(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.questionId = source["questionId"];
+	        this.model = source["model"];
+	        this.difficulty = source["difficulty"];
+	        this.discrimination = source["discrimination"];
+	        this.difficultySe = source["difficultySe"];
+	        this.attempts = source["attempts"];
+	        this.correct = source["correct"];
+	        this.calibratedAt = source["calibratedAt"];
+	    }
+	}
+	export class JournalOperation {
+	    id: number;
+	    label: string;
+	    undone: boolean;
+	    changeCount: number;
+	    createdAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new JournalOperation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.label = source["label"];
+	        this.undone = source["undone"];
+	        this.changeCount = source["changeCount"];
+	        this.createdAt = source["createdAt"];
+	    }
+	}
+	export class LearnerAbility {
+	    theta: number;
+	    standardError: number;
+	    attempts: number;
+	    estimatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LearnerAbility(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.theta = source["theta"];
+	        this.standardError = source["standardError"];
+	        this.attempts = source["attempts"];
+	        this.estimatedAt = source["estimatedAt"];
+	    }
+	}
+	export class LeechFailure {
+	    sessionId: string;
+	    mode: string;
+	    answeredAt: string;
+	    userAnswer: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LeechFailure(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessionId = source["sessionId"];
+	        this.mode = source["mode"];
+	        this.answeredAt = source["answeredAt"];
+	        this.userAnswer = source["userAnswer"];
+	    }
+	}
+	export class WrongQuestion {
+	    id: string;
+	    questionId: string;
+	    addedAt: string;
+	    reviewedAt?: string;
+	    timesReviewed: number;
+	    lastResult: boolean;
+	    notes: string;
+	    priority: number;
+	    ease: number;
+	    intervalDays: number;
+	    dueAt?: string;
+	    lapses: number;
+	    leech: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new WrongQuestion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.questionId = source["questionId"];
+	        this.addedAt = source["addedAt"];
+	        this.reviewedAt = source["reviewedAt"];
+	        this.timesReviewed = source["timesReviewed"];
+	        this.lastResult = source["lastResult"];
+	        this.notes = source["notes"];
+	        this.priority = source["priority"];
+	        this.ease = source["ease"];
+	        this.intervalDays = source["intervalDays"];
+	        this.dueAt = source["dueAt"];
+	        this.lapses = source["lapses"];
+	        this.leech = source["leech"];
+	    }
+	}
+	export class Leech {
+	    question: Question;
+	    wrongQuestion: WrongQuestion;
+	    suspended: boolean;
+	    attempts: number;
+	    correct: number;
+	    failures: LeechFailure[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Leech(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.question = this.convertValues(source["question"], Question);
+	        this.wrongQuestion = this.convertValues(source["wrongQuestion"], WrongQuestion);
+	        this.suspended = source["suspended"];
+	        this.attempts = source["attempts"];
+	        this.correct = source["correct"];
+	        this.failures = this.convertValues(source["failures"], LeechFailure);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class SectionTiming {
+	    name: string;
+	    timeLimit: number;
+	    startedAt: string;
+	    completedAt: string;
+	    duration: number;
+	    questions: number;
+	    answered: number;
+	    correct: number;
+	    timedOut: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SectionTiming(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.timeLimit = source["timeLimit"];
+	        this.startedAt = source["startedAt"];
+	        this.completedAt = source["completedAt"];
+	        this.duration = source["duration"];
+	        this.questions = source["questions"];
+	        this.answered = source["answered"];
+	        this.correct = source["correct"];
+	        this.timedOut = source["timedOut"];
+	    }
+	}
+	export class PracticeSession {
+	    id: string;
+	    groupId: string;
+	    mode: string;
+	    startTime: string;
+	    endTime?: string;
+	    duration: number;
+	    totalQuestions: number;
+	    correctCount: number;
+	    details: number[];
+	    sections: SectionTiming[];
+	    createdAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PracticeSession(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.groupId = source["groupId"];
+	        this.mode = source["mode"];
+	        this.startTime = source["startTime"];
+	        this.endTime = source["endTime"];
+	        this.duration = source["duration"];
+	        this.totalQuestions = source["totalQuestions"];
+	        this.correctCount = source["correctCount"];
+	        this.details = source["details"];
+	        this.sections = this.convertValues(source["sections"], SectionTiming);
+	        this.createdAt = source["createdAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class QuestionExposure {
+	    questionId: string;
+	    exposures: number;
+	    correctStreak: number;
+	    mastered: boolean;
+	    firstSeenAt: string;
+	    lastSeenAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QuestionExposure(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.questionId = source["questionId"];
+	        this.exposures = source["exposures"];
+	        this.correctStreak = source["correctStreak"];
+	        this.mastered = source["mastered"];
+	        this.firstSeenAt = source["firstSeenAt"];
+	        this.lastSeenAt = source["lastSeenAt"];
+	    }
+	}
+	export class QuestionFieldChange {
+	    field: string;
+	    from: number[];
+	    to: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new QuestionFieldChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	    }
+	}
+	export class SmartGroupCondition {
+	    field: string;
+	    op: string;
+	    value: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new SmartGroupCondition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.op = source["op"];
+	        this.value = source["value"];
+	    }
+	}
+	export class SmartGroupQuery {
+	    match: string;
+	    conditions: SmartGroupCondition[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SmartGroupQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.match = source["match"];
+	        this.conditions = this.convertValues(source["conditions"], SmartGroupCondition);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class QuestionGroup {
+	    id: string;
+	    name: string;
+	    description: string;
+	    parentId?: string;
+	    color: string;
+	    icon: string;
+	    position: number;
+	    smartQuery?: SmartGroupQuery;
+	    questionIds: string[];
+	    coverage?: CoverageStats;
+	    createdAt: string;
+	    updatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QuestionGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.parentId = source["parentId"];
+	        this.color = source["color"];
+	        this.icon = source["icon"];
+	        this.position = source["position"];
+	        this.smartQuery = this.convertValues(source["smartQuery"], SmartGroupQuery);
+	        this.questionIds = source["questionIds"];
+	        this.coverage = this.convertValues(source["coverage"], CoverageStats);
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class QuestionRevision {
+	    id: string;
+	    questionId: string;
+	    revision: number;
+	    actor: string;
+	    reason: string;
+	    snapshot: number[];
+	    changes: number[];
+	    createdAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QuestionRevision(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.questionId = source["questionId"];
+	        this.revision = source["revision"];
+	        this.actor = source["actor"];
+	        this.reason = source["reason"];
+	        this.snapshot = source["snapshot"];
+	        this.changes = source["changes"];
+	        this.createdAt = source["createdAt"];
+	    }
+	}
+	export class QuestionSuspension {
+	    questionId: string;
+	    reason: string;
+	    suspendedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QuestionSuspension(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.questionId = source["questionId"];
+	        this.reason = source["reason"];
+	        this.suspendedAt = source["suspendedAt"];
+	    }
+	}
+	export class TemplateVariable {
+	    name: string;
+	    min: number;
+	    max: number;
+	    step: number;
+	    values: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TemplateVariable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.min = source["min"];
+	        this.max = source["max"];
+	        this.step = source["step"];
+	        this.values = source["values"];
+	    }
+	}
+	export class QuestionTemplate {
+	    id: string;
+	    name: string;
+	    groupId: string;
+	    stem: string;
+	    variables: TemplateVariable[];
+	    answer: string;
+	    distractors: string[];
+	    decimals?: number;
+	    unit: string;
+	    explanation: string;
+	    tags: number[];
+	    difficulty?: number;
+	    version: number;
+	    createdAt: string;
+	    updatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QuestionTemplate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.groupId = source["groupId"];
+	        this.stem = source["stem"];
+	        this.variables = this.convertValues(source["variables"], TemplateVariable);
+	        this.answer = source["answer"];
+	        this.distractors = source["distractors"];
+	        this.decimals = source["decimals"];
+	        this.unit = source["unit"];
+	        this.explanation = source["explanation"];
+	        this.tags = source["tags"];
+	        this.difficulty = source["difficulty"];
+	        this.version = source["version"];
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SessionEvent {
+	    id: number;
+	    sessionId: string;
+	    questionId: string;
+	    type: string;
+	    fromAnswer: string[];
+	    toAnswer: string[];
+	    fromCorrect?: boolean;
+	    toCorrect?: boolean;
+	    at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionEvent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.sessionId = source["sessionId"];
+	        this.questionId = source["questionId"];
+	        this.type = source["type"];
+	        this.fromAnswer = source["fromAnswer"];
+	        this.toAnswer = source["toAnswer"];
+	        this.fromCorrect = source["fromCorrect"];
+	        this.toCorrect = source["toCorrect"];
+	        this.at = source["at"];
+	    }
+	}
+	export class QuestionTimeline {
+	    questionId: string;
+	    visits: number;
+	    timeViewed: number;
+	    timeToFirstAnswer?: number;
+	    timeAfterLastAnswer?: number;
+	    answerChanges: number;
+	    events: SessionEvent[];
+	
+	    static createFrom(source: any = {}) {
+	        return new QuestionTimeline(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.questionId = source["questionId"];
+	        this.visits = source["visits"];
+	        this.timeViewed = source["timeViewed"];
+	        this.timeToFirstAnswer = source["timeToFirstAnswer"];
+	        this.timeAfterLastAnswer = source["timeAfterLastAnswer"];
+	        this.answerChanges = source["answerChanges"];
+	        this.events = this.convertValues(source["events"], SessionEvent);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReadinessArea {
+	    name: string;
+	    weight: number;
+	    questions: number;
+	    attempted: number;
+	    coverage: number;
+	    accuracy: number;
+	    predicted: number;
+	    gap: number;
+	    lastPracticed?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReadinessArea(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.weight = source["weight"];
+	        this.questions = source["questions"];
+	        this.attempted = source["attempted"];
+	        this.coverage = source["coverage"];
+	        this.accuracy = source["accuracy"];
+	        this.predicted = source["predicted"];
+	        this.gap = source["gap"];
+	        this.lastPracticed = source["lastPracticed"];
+	    }
+	}
+	export class ReadinessReport {
+	    blueprintId: string;
+	    passingScore: number;
+	    predictedScore: number;
+	    lower: number;
+	    upper: number;
+	    passProbability: number;
+	    practiceScore: number;
+	    mockScore?: number;
+	    mockExams: number;
+	    coverage: number;
+	    areas: ReadinessArea[];
+	    computedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReadinessReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.blueprintId = source["blueprintId"];
+	        this.passingScore = source["passingScore"];
+	        this.predictedScore = source["predictedScore"];
+	        this.lower = source["lower"];
+	        this.upper = source["upper"];
+	        this.passProbability = source["passProbability"];
+	        this.practiceScore = source["practiceScore"];
+	        this.mockScore = source["mockScore"];
+	        this.mockExams = source["mockExams"];
+	        this.coverage = source["coverage"];
+	        this.areas = this.convertValues(source["areas"], ReadinessArea);
+	        this.computedAt = source["computedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class SessionPacing {
+	    sessionId: string;
+	    answered: number;
+	    remaining: number;
+	    elapsedSeconds: number;
+	    budgetSeconds: number;
+	    secondsAhead: number;
+	    projectedSeconds: number;
+	    totalBudgetSeconds: number;
+	    suggestedSecondsPerQuestion: number;
+	    status: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionPacing(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessionId = source["sessionId"];
+	        this.answered = source["answered"];
+	        this.remaining = source["remaining"];
+	        this.elapsedSeconds = source["elapsedSeconds"];
+	        this.budgetSeconds = source["budgetSeconds"];
+	        this.secondsAhead = source["secondsAhead"];
+	        this.projectedSeconds = source["projectedSeconds"];
+	        this.totalBudgetSeconds = source["totalBudgetSeconds"];
+	        this.suggestedSecondsPerQuestion = source["suggestedSecondsPerQuestion"];
+	        this.status = source["status"];
+	    }
+	}
+	
+	
+	
+	export class StudyPlanDay {
+	    date: string;
+	    newQuestionIds: string[];
+	    reviewQuestions: number;
+	    plannedQuestions: number;
+	    plannedMinutes: number;
+	    completedQuestions: number;
+	    completedMinutes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StudyPlanDay(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.newQuestionIds = source["newQuestionIds"];
+	        this.reviewQuestions = source["reviewQuestions"];
+	        this.plannedQuestions = source["plannedQuestions"];
+	        this.plannedMinutes = source["plannedMinutes"];
+	        this.completedQuestions = source["completedQuestions"];
+	        this.completedMinutes = source["completedMinutes"];
+	    }
+	}
+	export class StudyPlan {
+	    id: string;
+	    name: string;
+	    status: string;
+	    examDate: string;
+	    groupIds: string[];
+	    minutesPerDay: number;
+	    secondsPerQuestion: number;
+	    days: StudyPlanDay[];
+	    unscheduled: number;
+	    plannedToDate: number;
+	    completedToDate: number;
+	    pace: string;
+	    createdAt: string;
+	    updatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StudyPlan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.status = source["status"];
+	        this.examDate = source["examDate"];
+	        this.groupIds = source["groupIds"];
+	        this.minutesPerDay = source["minutesPerDay"];
+	        this.secondsPerQuestion = source["secondsPerQuestion"];
+	        this.days = this.convertValues(source["days"], StudyPlanDay);
+	        this.unscheduled = source["unscheduled"];
+	        this.plannedToDate = source["plannedToDate"];
+	        this.completedToDate = source["completedToDate"];
+	        this.pace = source["pace"];
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class StudyPlanOptions {
+	    name: string;
+	    examDate: string;
+	    groupIds: string[];
+	    minutesPerDay: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StudyPlanOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.examDate = source["examDate"];
+	        this.groupIds = source["groupIds"];
+	        this.minutesPerDay = source["minutesPerDay"];
+	    }
+	}
+	export class StudyRecommendation {
+	    id: string;
+	    kind: string;
+	    title: string;
+	    reason: string;
+	    topic?: string;
+	    mode: string;
+	    questionIds: string[];
+	    estimatedMinutes: number;
+	    score: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StudyRecommendation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.kind = source["kind"];
+	        this.title = source["title"];
+	        this.reason = source["reason"];
+	        this.topic = source["topic"];
+	        this.mode = source["mode"];
+	        this.questionIds = source["questionIds"];
+	        this.estimatedMinutes = source["estimatedMinutes"];
+	        this.score = source["score"];
+	    }
+	}
+	export class Tag {
+	    id: string;
+	    name: string;
+	    parentId?: string;
+	    path: string;
+	    createdAt: string;
+	    updatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Tag(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.parentId = source["parentId"];
+	        this.path = source["path"];
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	}
+	export class TagWithCount {
+	    id: string;
+	    name: string;
+	    parentId?: string;
+	    path: string;
+	    questionCount: number;
+	    totalCount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TagWithCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.parentId = source["parentId"];
+	        this.path = source["path"];
+	        this.questionCount = source["questionCount"];
+	        this.totalCount = source["totalCount"];
+	    }
+	}
+	export class TemplateAnalytics {
+	    templateId: string;
+	    name: string;
+	    attempts: number;
+	    correct: number;
+	    accuracy: number;
+	    variants: number;
+	    averageTimeSpent: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TemplateAnalytics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.templateId = source["templateId"];
+	        this.name = source["name"];
+	        this.attempts = source["attempts"];
+	        this.correct = source["correct"];
+	        this.accuracy = source["accuracy"];
+	        this.variants = source["variants"];
+	        this.averageTimeSpent = source["averageTimeSpent"];
+	    }
+	}
+	
+	export class TimeSeriesPoint {
+	    period: string;
+	    sessions: number;
+	    questions: number;
+	    correct: number;
+	    accuracy: number;
+	    studyMinutes: number;
+	    accuracyAverage: number;
+	    questionsAverage: number;
+	    studyMinutesAverage: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TimeSeriesPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.period = source["period"];
+	        this.sessions = source["sessions"];
+	        this.questions = source["questions"];
+	        this.correct = source["correct"];
+	        this.accuracy = source["accuracy"];
+	        this.studyMinutes = source["studyMinutes"];
+	        this.accuracyAverage = source["accuracyAverage"];
+	        this.questionsAverage = source["questionsAverage"];
+	        this.studyMinutesAverage = source["studyMinutesAverage"];
+	    }
+	}
+	export class TimeSeries {
+	    granularity: string;
+	    movingAverage: number;
+	    points: TimeSeriesPoint[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TimeSeries(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.granularity = source["granularity"];
+	        this.movingAverage = source["movingAverage"];
+	        this.points = this.convertValues(source["points"], TimeSeriesPoint);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class TimeSeriesQuery {
+	    granularity: string;
+	    from: string;
+	    to: string;
+	    groupId: string;
+	    tag: string;
+	    mode: string;
+	    movingAverage: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TimeSeriesQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.granularity = source["granularity"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.groupId = source["groupId"];
+	        this.tag = source["tag"];
+	        this.mode = source["mode"];
+	        this.movingAverage = source["movingAverage"];
+	    }
+	}
+	export class TimedExamSection {
+	    name: string;
+	    timeLimit: number;
+	    questionIds: string[];
+	    answers: QuestionRecord[];
+	    startedAt?: string;
+	    deadline?: string;
+	    completedAt?: string;
+	    lastAnswerAt?: string;
+	    locked: boolean;
+	    timedOut: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TimedExamSection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.timeLimit = source["timeLimit"];
+	        this.questionIds = source["questionIds"];
+	        this.answers = this.convertValues(source["answers"], QuestionRecord);
+	        this.startedAt = source["startedAt"];
+	        this.deadline = source["deadline"];
+	        this.completedAt = source["completedAt"];
+	        this.lastAnswerAt = source["lastAnswerAt"];
+	        this.locked = source["locked"];
+	        this.timedOut = source["timedOut"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TimedExam {
+	    id: string;
+	    blueprintId: string;
+	    name: string;
+	    status: string;
+	    timeLimit: number;
+	    currentSection: number;
+	    sections: TimedExamSection[];
+	    breaks: ExamBreak[];
+	    breakEndsAt?: string;
+	    startedAt: string;
+	    finishedAt?: string;
+	    shortfalls: BlueprintShortfall[];
+	    currentQuestions: SessionQuestion[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TimedExam(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.blueprintId = source["blueprintId"];
+	        this.name = source["name"];
+	        this.status = source["status"];
+	        this.timeLimit = source["timeLimit"];
+	        this.currentSection = source["currentSection"];
+	        this.sections = this.convertValues(source["sections"], TimedExamSection);
+	        this.breaks = this.convertValues(source["breaks"], ExamBreak);
+	        this.breakEndsAt = source["breakEndsAt"];
+	        this.startedAt = source["startedAt"];
+	        this.finishedAt = source["finishedAt"];
+	        this.shortfalls = this.convertValues(source["shortfalls"], BlueprintShortfall);
+	        this.currentQuestions = this.convertValues(source["currentQuestions"], SessionQuestion);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class TimingBucket {
+	    minSeconds: number;
+	    maxSeconds: number;
+	    answers: number;
+	    accuracy: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TimingBucket(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.minSeconds = source["minSeconds"];
+	        this.maxSeconds = source["maxSeconds"];
+	        this.answers = source["answers"];
+	        this.accuracy = source["accuracy"];
+	    }
+	}
+	export class TimingGroup {
+	    key: string;
+	    answers: number;
+	    averageSeconds: number;
+	    medianSeconds: number;
+	    accuracy: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TimingGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.answers = source["answers"];
+	        this.averageSeconds = source["averageSeconds"];
+	        this.medianSeconds = source["medianSeconds"];
+	        this.accuracy = source["accuracy"];
+	    }
+	}
+	export class TimingItem {
+	    questionId: string;
+	    question: string;
+	    count: number;
+	    answers: number;
+	    averageSeconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TimingItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.questionId = source["questionId"];
+	        this.question = source["question"];
+	        this.count = source["count"];
+	        this.answers = source["answers"];
+	        this.averageSeconds = source["averageSeconds"];
+	    }
+	}
+	export class TimingReport {
+	    answers: number;
+	    averageSeconds: number;
+	    medianSeconds: number;
+	    budgetSeconds: number;
+	    paceRatio: number;
+	    overBudget: number;
+	    overBudgetShare: number;
+	    byTag: TimingGroup[];
+	    byDifficulty: TimingGroup[];
+	    accuracyByTime: TimingBucket[];
+	    rushedSeconds: number;
+	    slowSeconds: number;
+	    rushedWrong: TimingItem[];
+	    slowCorrect: TimingItem[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TimingReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.answers = source["answers"];
+	        this.averageSeconds = source["averageSeconds"];
+	        this.medianSeconds = source["medianSeconds"];
+	        this.budgetSeconds = source["budgetSeconds"];
+	        this.paceRatio = source["paceRatio"];
+	        this.overBudget = source["overBudget"];
+	        this.overBudgetShare = source["overBudgetShare"];
+	        this.byTag = this.convertValues(source["byTag"], TimingGroup);
+	        this.byDifficulty = this.convertValues(source["byDifficulty"], TimingGroup);
+	        this.accuracyByTime = this.convertValues(source["accuracyByTime"], TimingBucket);
+	        this.rushedSeconds = source["rushedSeconds"];
+	        this.slowSeconds = source["slowSeconds"];
+	        this.rushedWrong = this.convertValues(source["rushedWrong"], TimingItem);
+	        this.slowCorrect = this.convertValues(source["slowCorrect"], TimingItem);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TopicRetention {
+	    topic: string;
+	    questions: number;
+	    attempts: number;
+	    accuracy: number;
+	    reviews: number;
+	    fitted: boolean;
+	    initialRecall: number;
+	    halfLifeDays: number;
+	    predictedRecall: number;
+	    daysSincePractice: number;
+	    stale: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TopicRetention(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.topic = source["topic"];
+	        this.questions = source["questions"];
+	        this.attempts = source["attempts"];
+	        this.accuracy = source["accuracy"];
+	        this.reviews = source["reviews"];
+	        this.fitted = source["fitted"];
+	        this.initialRecall = source["initialRecall"];
+	        this.halfLifeDays = source["halfLifeDays"];
+	        this.predictedRecall = source["predictedRecall"];
+	        this.daysSincePractice = source["daysSincePractice"];
+	        this.stale = source["stale"];
 	    }
 	}
 
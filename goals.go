@@ -0,0 +1,407 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"time"
+)
+
+// Goal defaults, used when the settings are missing or invalid
+const (
+	defaultStudyGoal       = 20 // Questions per day
+	defaultStreakGraceDays = 1
+	perfectSessionMinimum  = 10 // Questions a session needs to count as perfect
+)
+
+// studyDay aggregates the answers of one day
+type studyDay struct {
+	questions int
+	correct   int
+	seconds   int
+}
+
+// goalStats is the history achievements are checked against
+type goalStats struct {
+	questions      int
+	seconds        int
+	longestStreak  int
+	perfectSession bool
+	weekComplete   bool
+}
+
+// achievementDefinition describes an achievement and when it unlocks
+type achievementDefinition struct {
+	id          string
+	title       string
+	description string
+	unlocked    func(stats goalStats) bool
+}
+
+// achievementDefinitions lists every achievement in display order
+var achievementDefinitions = []achievementDefinition{
+	{"first_question", "First steps", "Answer your first question", func(s goalStats) bool { return s.questions >= 1 }},
+	{"questions_100", "Century", "Answer 100 questions", func(s goalStats) bool { return s.questions >= 100 }},
+	{"questions_1000", "Thousand strong", "Answer 1,000 questions", func(s goalStats) bool { return s.questions >= 1000 }},
+	{"study_10_hours", "Ten hours in", "Study for 10 hours in total", func(s goalStats) bool { return s.seconds >= 10*3600 }},
+	{"streak_7", "One week streak", "Meet your daily goals 7 days in a row", func(s goalStats) bool { return s.longestStreak >= 7 }},
+	{"streak_30", "One month streak", "Meet your daily goals 30 days in a row", func(s goalStats) bool { return s.longestStreak >= 30 }},
+	{"perfect_session", "Flawless", fmt.Sprintf("Answer every question right in a session of at least %d", perfectSessionMinimum), func(s goalStats) bool { return s.perfectSession }},
+	{"weekly_goals", "Week well spent", "Meet all of your weekly goals", func(s goalStats) bool { return s.weekComplete }},
+}
+
+// validateGoal checks that a goal's period, metric and target are usable
+func validateGoal(period, metric string, target float64) error {
+	if period != GoalPeriodDaily && period != GoalPeriodWeekly {
+		return fmt.Errorf("unknown goal period %q", period)
+	}
+	switch metric {
+	case GoalMetricQuestions, GoalMetricMinutes:
+		if target <= 0 {
+			return fmt.Errorf("goal target must be positive")
+		}
+	case GoalMetricAccuracy:
+		if target <= 0 || target > 100 {
+			return fmt.Errorf("accuracy target must be between 0 and 100")
+		}
+	default:
+		return fmt.Errorf("unknown goal metric %q", metric)
+	}
+	return nil
+}
+
+// goalValue returns the value of a metric over a set of answers
+func goalValue(metric string, day studyDay) float64 {
+	switch metric {
+	case GoalMetricMinutes:
+		return float64(day.seconds) / 60
+	case GoalMetricAccuracy:
+		if day.questions == 0 {
+			return 0
+		}
+		return float64(day.correct) / float64(day.questions) * 100
+	default:
+		return float64(day.questions)
+	}
+}
+
+// goalsMet reports whether every goal of a period is met by the given answers
+func goalsMet(goals []Goal, period string, day studyDay) bool {
+	for _, goal := range goals {
+		if goal.Period == period && goalValue(goal.Metric, day) < goal.Target {
+			return false
+		}
+	}
+	return true
+}
+
+// computeStreaks walks the days from the first answer to today. A day meeting every
+// daily goal extends the streak; a missed day is forgiven while the week, starting
+// Monday, has grace days left, and otherwise ends the streak. Today only counts once
+// its goals are met, so an unfinished day never breaks the streak. It returns the
+// current and longest streaks and the grace days left this week.
+func computeStreaks(days map[string]studyDay, goals []Goal, graceDays int, today time.Time) (int, int, int) {
+	var first time.Time
+	for date := range days {
+		if t, err := time.ParseInLocation(calendarDateLayout, date, time.Local); err == nil && (first.IsZero() || t.Before(first)) {
+			first = t
+		}
+	}
+	today = periodStart(today, GranularityDay)
+	graceUsed := make(map[string]int)
+	current, longest := 0, 0
+	if !first.IsZero() {
+		for day := first; !day.After(today); day = day.AddDate(0, 0, 1) {
+			date := day.Format(calendarDateLayout)
+			week := weekStart(day).Format(calendarDateLayout)
+			switch {
+			case goalsMet(goals, GoalPeriodDaily, days[date]):
+				current++
+			case day.Equal(today):
+			case current > 0 && graceUsed[week] < graceDays:
+				graceUsed[week]++
+			default:
+				current = 0
+			}
+			if current > longest {
+				longest = current
+			}
+		}
+	}
+	graceLeft := graceDays - graceUsed[weekStart(today).Format(calendarDateLayout)]
+	if graceLeft < 0 {
+		graceLeft = 0
+	}
+	return current, longest, graceLeft
+}
+
+// Goal database methods
+
+// SaveGoal inserts or replaces a goal
+func (d *Database) SaveGoal(goal Goal) error {
+	_, err := d.db.Exec(`INSERT OR REPLACE INTO study_goals (id, period, metric, target) VALUES (?, ?, ?, ?)`,
+		goal.ID, goal.Period, goal.Metric, goal.Target)
+	return err
+}
+
+// DeleteGoal deletes a goal
+func (d *Database) DeleteGoal(goalID string) error {
+	_, err := d.db.Exec(`DELETE FROM study_goals WHERE id = ?`, goalID)
+	return err
+}
+
+// GetGoals returns the stored goals
+func (d *Database) GetGoals() ([]Goal, error) {
+	rows, err := d.db.Query(`SELECT id, period, metric, target FROM study_goals ORDER BY period, metric`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var goals []Goal
+	for rows.Next() {
+		var g Goal
+		if err := rows.Scan(&g.ID, &g.Period, &g.Metric, &g.Target); err != nil {
+			return nil, err
+		}
+		goals = append(goals, g)
+	}
+	return goals, rows.Err()
+}
+
+// UnlockAchievement records an achievement, keeping the original unlock time
+func (d *Database) UnlockAchievement(achievementID string, at time.Time) error {
+	_, err := d.db.Exec(`INSERT OR IGNORE INTO achievements (id, unlocked_at) VALUES (?, ?)`, achievementID, at.Format(time.RFC3339))
+	return err
+}
+
+// GetUnlockedAchievements returns the unlock time of each unlocked achievement
+func (d *Database) GetUnlockedAchievements() (map[string]string, error) {
+	rows, err := d.db.Query(`SELECT id, unlocked_at FROM achievements`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	unlocked := make(map[string]string)
+	for rows.Next() {
+		var id, at string
+		if err := rows.Scan(&id, &at); err != nil {
+			return nil, err
+		}
+		unlocked[id] = at
+	}
+	return unlocked, rows.Err()
+}
+
+// Goal methods
+
+// goalID returns the ID of the goal for a period and metric; there is one per pair
+func goalID(period, metric string) string {
+	return period + "_" + metric
+}
+
+// studyGoalSetting returns the studyGoal setting, the daily question target
+func (a *App) studyGoalSetting() int {
+	goal := defaultStudyGoal
+	if value, err := a.db.GetSetting("studyGoal"); err == nil {
+		var configured float64
+		if json.Unmarshal(value, &configured) == nil && configured >= 1 {
+			goal = int(configured)
+		}
+	}
+	return goal
+}
+
+// GetGoals returns the study goals. The daily question goal is the studyGoal setting.
+func (a *App) GetGoals() ([]Goal, error) {
+	stored, err := a.db.GetGoals()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goals: %v", err)
+	}
+	goals := []Goal{{
+		ID:     goalID(GoalPeriodDaily, GoalMetricQuestions),
+		Period: GoalPeriodDaily,
+		Metric: GoalMetricQuestions,
+		Target: float64(a.studyGoalSetting()),
+	}}
+	return append(goals, stored...), nil
+}
+
+// SetGoal sets the target of a daily or weekly goal, replacing the previous target for
+// the same period and metric. Setting the daily question goal updates studyGoal.
+func (a *App) SetGoal(period, metric string, target float64) (*Goal, error) {
+	if err := validateGoal(period, metric, target); err != nil {
+		return nil, err
+	}
+	goal := Goal{ID: goalID(period, metric), Period: period, Metric: metric, Target: target}
+	if period == GoalPeriodDaily && metric == GoalMetricQuestions {
+		goal.Target = math.Round(target)
+		if err := a.SetUserSetting("studyGoal", int(goal.Target)); err != nil {
+			return nil, err
+		}
+		return &goal, nil
+	}
+
+	scopes := []journalScope{{table: "study_goals", where: "id = ?", args: []interface{}{goal.ID}}}
+	err := a.journaled("Set goal", scopes, func() error {
+		return a.db.SaveGoal(goal)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// RemoveGoal removes a goal. The daily question goal always exists and can only be changed.
+func (a *App) RemoveGoal(id string) error {
+	if id == goalID(GoalPeriodDaily, GoalMetricQuestions) {
+		return fmt.Errorf("the daily question goal can only be changed, not removed")
+	}
+	scopes := []journalScope{{table: "study_goals", where: "id = ?", args: []interface{}{id}}}
+	return a.journaled("Remove goal", scopes, func() error {
+		return a.db.DeleteGoal(id)
+	})
+}
+
+// streakGraceDays returns the streakGraceDays setting
+func (a *App) streakGraceDays() int {
+	grace := defaultStreakGraceDays
+	if value, err := a.db.GetSetting("streakGraceDays"); err == nil {
+		var configured float64
+		if json.Unmarshal(value, &configured) == nil && configured >= 0 {
+			grace = int(configured)
+		}
+	}
+	return grace
+}
+
+// goalProgress computes goal progress and streaks, along with the stats achievements
+// are checked against
+func (a *App) goalProgress() (*GoalProgress, goalStats, error) {
+	var stats goalStats
+	goals, err := a.GetGoals()
+	if err != nil {
+		return nil, stats, err
+	}
+	attempts, err := a.db.GetQuestionAttempts()
+	if err != nil {
+		return nil, stats, fmt.Errorf("failed to get question attempts: %v", err)
+	}
+
+	now := a.now().In(time.Local)
+	today := now.Format(calendarDateLayout)
+	week := weekStart(periodStart(now, GranularityDay))
+	days := make(map[string]studyDay)
+	sessions := make(map[string]studyDay)
+	var thisWeek studyDay
+	for _, attempt := range attempts {
+		answered := attempt.AnsweredAt.In(time.Local)
+		correct := 0
+		if attempt.IsCorrect {
+			correct = 1
+		}
+		// Questions left open still keep their session from being perfect
+		session := sessions[attempt.SessionID]
+		session.questions++
+		session.correct += correct
+		sessions[attempt.SessionID] = session
+		if !attempt.answered() {
+			continue
+		}
+		date := answered.Format(calendarDateLayout)
+		day := days[date]
+		day.questions++
+		day.correct += correct
+		day.seconds += attempt.TimeSpent
+		days[date] = day
+		if !answered.Before(week) {
+			thisWeek.questions++
+			thisWeek.correct += correct
+			thisWeek.seconds += attempt.TimeSpent
+		}
+		stats.questions++
+		stats.seconds += attempt.TimeSpent
+	}
+	for _, session := range sessions {
+		if session.questions >= perfectSessionMinimum && session.correct == session.questions {
+			stats.perfectSession = true
+		}
+	}
+
+	graceDays := a.streakGraceDays()
+	progress := &GoalProgress{Goals: []GoalStatus{}, GraceDays: graceDays}
+	progress.CurrentStreak, progress.LongestStreak, progress.GraceDaysLeft = computeStreaks(days, goals, graceDays, now)
+	hasWeekly := false
+	for _, goal := range goals {
+		day := days[today]
+		if goal.Period == GoalPeriodWeekly {
+			day = thisWeek
+			hasWeekly = true
+		}
+		current := goalValue(goal.Metric, day)
+		progress.Goals = append(progress.Goals, GoalStatus{
+			Goal:     goal,
+			Current:  current,
+			Progress: math.Min(100, current/goal.Target*100),
+			Met:      current >= goal.Target,
+		})
+	}
+	progress.TodayComplete = goalsMet(goals, GoalPeriodDaily, days[today])
+	progress.WeekComplete = hasWeekly && goalsMet(goals, GoalPeriodWeekly, thisWeek)
+
+	stats.longestStreak = progress.LongestStreak
+	stats.weekComplete = progress.WeekComplete
+	return progress, stats, nil
+}
+
+// GetGoalProgress returns the progress towards today's and this week's goals and the
+// current and longest streaks
+func (a *App) GetGoalProgress() (*GoalProgress, error) {
+	progress, _, err := a.goalProgress()
+	return progress, err
+}
+
+// unlockAchievements stores the achievements reached by the current history
+func (a *App) unlockAchievements(stats goalStats) error {
+	now := a.now()
+	for _, definition := range achievementDefinitions {
+		if definition.unlocked(stats) {
+			if err := a.db.UnlockAchievement(definition.id, now); err != nil {
+				return fmt.Errorf("failed to unlock achievement %s: %v", definition.id, err)
+			}
+		}
+	}
+	return nil
+}
+
+// evaluateGoals re-evaluates goals and unlocks achievements after a session is saved.
+// Failures are logged since the session itself has already been saved.
+func (a *App) evaluateGoals() {
+	_, stats, err := a.goalProgress()
+	if err == nil {
+		err = a.unlockAchievements(stats)
+	}
+	if err != nil {
+		log.Printf("Warning: Failed to evaluate goals: %v", err)
+	}
+}
+
+// GetAchievements returns every achievement, with its unlock time once unlocked
+func (a *App) GetAchievements() ([]Achievement, error) {
+	unlocked, err := a.db.GetUnlockedAchievements()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get achievements: %v", err)
+	}
+	achievements := make([]Achievement, 0, len(achievementDefinitions))
+	for _, definition := range achievementDefinitions {
+		achievement := Achievement{ID: definition.id, Title: definition.title, Description: definition.description}
+		if at, ok := unlocked[definition.id]; ok {
+			achievement.Unlocked = true
+			achievement.UnlockedAt = &at
+		}
+		achievements = append(achievements, achievement)
+	}
+	return achievements, nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestGoalProgressAndStreaks tests goal progress, grace days and achievement unlocking
+func TestGoalProgressAndStreaks(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	app.clock = func() time.Time { return time.Date(2025, 6, 12, 20, 0, 0, 0, time.Local) }
+
+	app.ImportQuestions([]map[string]interface{}{{"question": "Q1", "options": []string{}, "answer": []string{"a"}}}, "")
+	questions, _ := app.GetQuestions()
+
+	addSession := func(day, count int) {
+		var records []QuestionRecord
+		for i := 0; i < count; i++ {
+			records = append(records, QuestionRecord{QuestionID: questions[0].ID, UserAnswer: []string{"a"}, IsCorrect: true, TimeSpent: 30})
+		}
+		details, _ := json.Marshal(records)
+		at := time.Date(2025, 6, day, 9, 0, 0, 0, time.Local).Format(time.RFC3339)
+		db.CreatePracticeSession(&PracticeSession{ID: fmt.Sprintf("s%d", day), Mode: "practice", StartTime: at, TotalQuestions: count, Details: details, CreatedAt: at})
+	}
+	// Thursday June 5 to Thursday June 12, missing Saturday and Tuesday
+	for _, day := range []int{5, 6, 8, 11} {
+		addSession(day, 2)
+	}
+	addSession(9, 10)
+	addSession(12, 1)
+
+	if _, err := app.SetGoal(GoalPeriodDaily, GoalMetricQuestions, 2); err != nil {
+		t.Fatalf("Failed to set daily goal: %v", err)
+	}
+	if goal, _ := app.GetUserSetting("studyGoal"); goal != float64(2) {
+		t.Errorf("Expected the daily question goal to update studyGoal, got %v", goal)
+	}
+	if _, err := app.SetGoal(GoalPeriodWeekly, GoalMetricMinutes, 5); err != nil {
+		t.Fatalf("Failed to set weekly goal: %v", err)
+	}
+	if _, err := app.SetGoal(GoalPeriodDaily, GoalMetricAccuracy, 120); err == nil {
+		t.Error("Expected an accuracy above 100% to be rejected")
+	}
+	if err := app.RemoveGoal("daily_questions"); err == nil {
+		t.Error("Expected the daily question goal not to be removable")
+	}
+
+	progress, err := app.GetGoalProgress()
+	if err != nil {
+		t.Fatalf("Failed to get goal progress: %v", err)
+	}
+	// One grace day per week forgives both misses; today is still in progress
+	if progress.CurrentStreak != 5 || progress.LongestStreak != 5 || progress.GraceDaysLeft != 0 {
+		t.Errorf("Unexpected streaks: %+v", progress)
+	}
+	if len(progress.Goals) != 2 || progress.Goals[0].Current != 1 || progress.Goals[0].Progress != 50 || progress.TodayComplete {
+		t.Errorf("Unexpected daily goal progress: %+v", progress.Goals)
+	}
+	if !progress.Goals[1].Met || progress.Goals[1].Current != 6.5 || !progress.WeekComplete {
+		t.Errorf("Expected the weekly goal to be met, got %+v", progress.Goals[1])
+	}
+
+	app.SetUserSetting("streakGraceDays", 0)
+	if progress, _ = app.GetGoalProgress(); progress.CurrentStreak != 1 || progress.LongestStreak != 2 {
+		t.Errorf("Expected misses to break the streak without grace days, got %+v", progress)
+	}
+
+	app.evaluateGoals()
+	achievements, err := app.GetAchievements()
+	if err != nil {
+		t.Fatalf("Failed to get achievements: %v", err)
+	}
+	unlocked := make(map[string]bool)
+	for _, achievement := range achievements {
+		unlocked[achievement.ID] = achievement.Unlocked
+		if achievement.Unlocked && achievement.UnlockedAt == nil {
+			t.Errorf("Expected an unlock time for %s", achievement.ID)
+		}
+	}
+	if len(achievements) != len(achievementDefinitions) || !unlocked["first_question"] || !unlocked["perfect_session"] ||
+		!unlocked["weekly_goals"] || unlocked["questions_100"] || unlocked["streak_7"] {
+		t.Errorf("Unexpected achievements: %v", unlocked)
+	}
+}
+
+// TestGoalsIgnoreUnansweredQuestions tests that questions left open when a session
+// finishes do not count towards goals
+func TestGoalsIgnoreUnansweredQuestions(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	app.clock = func() time.Time { return time.Date(2025, 6, 12, 20, 0, 0, 0, time.Local) }
+
+	app.ImportQuestions([]map[string]interface{}{
+		{"question": "Q1", "options": []string{}, "answer": []string{"a"}},
+		{"question": "Q2", "options": []string{}, "answer": []string{"b"}},
+		{"question": "Q3", "options": []string{}, "answer": []string{"c"}},
+	}, "")
+	app.SetGoal(GoalPeriodDaily, GoalMetricQuestions, 2)
+
+	session, _ := app.StartSession("", PracticeModePractice, nil)
+	app.SubmitSessionAnswer(session.ID, session.QuestionIDs[0], []string{"x"}, 5, "")
+	if _, err := app.FinishSession(session.ID); err != nil {
+		t.Fatalf("Failed to finish session: %v", err)
+	}
+	app.waitForCalibration()
+
+	progress, err := app.GetGoalProgress()
+	if err != nil {
+		t.Fatalf("Failed to get goal progress: %v", err)
+	}
+	if progress.Goals[0].Current != 1 || progress.Goals[0].Met {
+		t.Errorf("Expected only the answered question to count, got %+v", progress.Goals[0])
+	}
+}
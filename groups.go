@@ -0,0 +1,363 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// maxGroupDepth guards recursive group queries against cycles left by older versions
+const maxGroupDepth = 64
+
+// Group tree database methods
+
+// GetGroupDescendantIDs returns the IDs of all groups below a group
+func (d *Database) GetGroupDescendantIDs(groupID string) ([]string, error) {
+	query := `WITH RECURSIVE descendants(id, depth) AS (
+				SELECT id, 1 FROM question_groups WHERE parent_id = ?
+				UNION
+				SELECT g.id, d.depth + 1 FROM question_groups g
+				JOIN descendants d ON g.parent_id = d.id
+				WHERE d.depth < ?
+			  )
+			  SELECT DISTINCT id FROM descendants WHERE id != ?`
+
+	rows, err := d.db.Query(query, groupID, maxGroupDepth, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GroupExists reports whether a group with the given ID exists
+func (d *Database) GroupExists(groupID string) (bool, error) {
+	var count int
+	err := d.db.QueryRow(`SELECT COUNT(*) FROM question_groups WHERE id = ?`, groupID).Scan(&count)
+	return count > 0, err
+}
+
+// wouldCreateGroupCycle reports whether making parentID the parent of groupID would
+// make the group its own ancestor
+func (d *Database) wouldCreateGroupCycle(groupID, parentID string) (bool, error) {
+	if parentID == groupID {
+		return true, nil
+	}
+	descendants, err := d.GetGroupDescendantIDs(groupID)
+	if err != nil {
+		return false, err
+	}
+	for _, id := range descendants {
+		if id == parentID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// nextGroupPosition returns the position after the last child of a parent group
+func (d *Database) nextGroupPosition(parentID *string) (int, error) {
+	var position int
+	var err error
+	if parentID == nil {
+		err = d.db.QueryRow(`SELECT COALESCE(MAX(position) + 1, 0) FROM question_groups WHERE parent_id IS NULL`).Scan(&position)
+	} else {
+		err = d.db.QueryRow(`SELECT COALESCE(MAX(position) + 1, 0) FROM question_groups WHERE parent_id = ?`, *parentID).Scan(&position)
+	}
+	return position, err
+}
+
+// GetGroupTree returns the group subtree under rootID, or every top-level group and its
+// descendants when rootID is empty. Question counts are computed in SQL.
+func (d *Database) GetGroupTree(rootID string) ([]GroupTreeNode, error) {
+	query := `WITH RECURSIVE subtree(id, depth) AS (
+				SELECT id, 0 FROM question_groups
+				WHERE (? = '' AND (parent_id IS NULL OR parent_id NOT IN (SELECT id FROM question_groups)))
+				   OR id = ?
+				UNION
+				SELECT g.id, s.depth + 1 FROM question_groups g
+				JOIN subtree s ON g.parent_id = s.id
+				WHERE s.depth < ?
+			  ),
+			  closure(ancestor, id) AS (
+				SELECT id, id FROM subtree
+				UNION
+				SELECT c.ancestor, g.id FROM question_groups g
+				JOIN closure c ON g.parent_id = c.id
+			  )
+			  SELECT g.id, g.name, g.description, g.parent_id, g.color, g.icon, COALESCE(g.position, 0), s.depth,
+				(SELECT COUNT(*) FROM question_group_relations r WHERE r.group_id = g.id),
+				(SELECT COUNT(DISTINCT r.question_id) FROM closure c
+				 JOIN question_group_relations r ON r.group_id = c.id
+				 WHERE c.ancestor = g.id)
+			  FROM subtree s
+			  JOIN question_groups g ON g.id = s.id
+			  ORDER BY s.depth, COALESCE(g.position, 0), g.created_at`
+
+	rows, err := d.db.Query(query, rootID, rootID, maxGroupDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nodes []GroupTreeNode
+	index := make(map[string]int)
+	for rows.Next() {
+		var n GroupTreeNode
+		var description, color, icon sql.NullString
+		if err := rows.Scan(&n.ID, &n.Name, &description, &n.ParentID, &color, &icon, &n.Position, &n.Depth,
+			&n.QuestionCount, &n.TotalQuestionCount); err != nil {
+			return nil, err
+		}
+		if _, seen := index[n.ID]; seen {
+			continue
+		}
+		n.Description = description.String
+		n.Color = color.String
+		n.Icon = icon.String
+		n.Children = []GroupTreeNode{}
+		index[n.ID] = len(nodes)
+		nodes = append(nodes, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Rows are ordered by depth, so parents are always seen before their children
+	children := make(map[string][]string)
+	var roots []string
+	for _, n := range nodes {
+		if n.ParentID != nil && n.ID != rootID {
+			if _, ok := index[*n.ParentID]; ok {
+				children[*n.ParentID] = append(children[*n.ParentID], n.ID)
+				continue
+			}
+		}
+		roots = append(roots, n.ID)
+	}
+
+	var build func(id string) GroupTreeNode
+	build = func(id string) GroupTreeNode {
+		node := nodes[index[id]]
+		for _, childID := range children[id] {
+			node.Children = append(node.Children, build(childID))
+		}
+		return node
+	}
+
+	tree := []GroupTreeNode{}
+	for _, id := range roots {
+		tree = append(tree, build(id))
+	}
+	return tree, nil
+}
+
+// checkParentGroup returns an error unless parentID names an existing group
+func (a *App) checkParentGroup(parentID string) error {
+	exists, err := a.db.GroupExists(parentID)
+	if err != nil {
+		return fmt.Errorf("failed to find parent group: %v", err)
+	}
+	if !exists {
+		return fmt.Errorf("parent group %s not found", parentID)
+	}
+	return nil
+}
+
+// MoveGroup moves a group under a new parent (nil for top level) at the given position
+// among its new siblings, renumbering the siblings
+func (d *Database) MoveGroup(groupID string, parentID *string, position int, at time.Time) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE question_groups SET parent_id = ?, updated_at = ? WHERE id = ?`,
+		parentID, at.Format(time.RFC3339), groupID); err != nil {
+		return err
+	}
+
+	siblingQuery := `SELECT id FROM question_groups WHERE parent_id IS NULL AND id != ? ORDER BY COALESCE(position, 0), created_at`
+	args := []interface{}{groupID}
+	if parentID != nil {
+		siblingQuery = `SELECT id FROM question_groups WHERE parent_id = ? AND id != ? ORDER BY COALESCE(position, 0), created_at`
+		args = []interface{}{*parentID, groupID}
+	}
+	rows, err := tx.Query(siblingQuery, args...)
+	if err != nil {
+		return err
+	}
+	var siblings []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		siblings = append(siblings, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if position < 0 || position > len(siblings) {
+		position = len(siblings)
+	}
+	ordered := append([]string{}, siblings[:position]...)
+	ordered = append(ordered, groupID)
+	ordered = append(ordered, siblings[position:]...)
+
+	for i, id := range ordered {
+		if _, err := tx.Exec(`UPDATE question_groups SET position = ? WHERE id = ?`, i, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DeleteQuestionGroupTree deletes a group, either deleting its descendants as well or
+// moving its children up to its parent
+func (d *Database) DeleteQuestionGroupTree(groupID, mode string, at time.Time) error {
+	descendants, err := d.GetGroupDescendantIDs(groupID)
+	if err != nil {
+		return err
+	}
+
+	var parentID *string
+	if err := d.db.QueryRow(`SELECT parent_id FROM question_groups WHERE id = ?`, groupID).Scan(&parentID); err != nil {
+		return err
+	}
+	nextPosition, err := d.nextGroupPosition(parentID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	toDelete := []string{groupID}
+	switch mode {
+	case GroupDeleteRecursive:
+		toDelete = append(toDelete, descendants...)
+	case GroupDeleteReparent, "":
+		// Keep the children's relative order after the new siblings
+		if _, err := tx.Exec(`UPDATE question_groups SET parent_id = ?, position = ? + COALESCE(position, 0), updated_at = ?
+				  WHERE parent_id = ?`, parentID, nextPosition, at.Format(time.RFC3339), groupID); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown delete mode %q", mode)
+	}
+
+	for _, id := range toDelete {
+		if _, err := tx.Exec(`DELETE FROM question_group_relations WHERE group_id = ?`, id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`DELETE FROM question_groups WHERE id = ?`, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Group tree methods
+
+// GetGroupTree returns the group hierarchy with recursive question counts. An empty
+// rootID returns the whole forest. Smart groups count the questions their query
+// currently matches.
+func (a *App) GetGroupTree(rootID string) ([]GroupTreeNode, error) {
+	tree, err := a.db.GetGroupTree(rootID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group tree: %v", err)
+	}
+	ctx, err := a.loadSmartQueryContext()
+	if err != nil {
+		return nil, err
+	}
+	if len(ctx.queries) == 0 {
+		return tree, nil
+	}
+	for i := range tree {
+		if _, err := ctx.countGroupTree(&tree[i]); err != nil {
+			return nil, fmt.Errorf("failed to count smart group questions: %v", err)
+		}
+	}
+	return tree, nil
+}
+
+// GetGroupDescendants returns the IDs of every group below a group
+func (a *App) GetGroupDescendants(groupID string) ([]string, error) {
+	ids, err := a.db.GetGroupDescendantIDs(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group descendants: %v", err)
+	}
+	return ids, nil
+}
+
+// MoveGroup moves a group and its subtree under a new parent group, or to the top level
+// when newParentID is empty. Position is the index among the new siblings; a negative
+// position appends the group at the end.
+func (a *App) MoveGroup(groupID, newParentID string, position int) error {
+	exists, err := a.db.GroupExists(groupID)
+	if err != nil {
+		return fmt.Errorf("failed to find group: %v", err)
+	}
+	if !exists {
+		return fmt.Errorf("group %s not found", groupID)
+	}
+
+	var parentID *string
+	if newParentID != "" {
+		if err := a.checkParentGroup(newParentID); err != nil {
+			return err
+		}
+		cycle, err := a.db.wouldCreateGroupCycle(groupID, newParentID)
+		if err != nil {
+			return fmt.Errorf("failed to check group hierarchy: %v", err)
+		}
+		if cycle {
+			return fmt.Errorf("cannot move group under itself or one of its descendants")
+		}
+		parentID = &newParentID
+	}
+
+	scopes := []journalScope{{table: "question_groups"}}
+	return a.journaled("Move group", scopes, func() error {
+		if err := a.db.MoveGroup(groupID, parentID, position, a.now()); err != nil {
+			return fmt.Errorf("failed to move group: %v", err)
+		}
+		return nil
+	})
+}
+
+// DeleteQuestionGroupWithMode deletes a group. With GroupDeleteRecursive its descendant
+// groups are deleted too; with GroupDeleteReparent they move up to the group's parent.
+// Questions themselves are never deleted.
+func (a *App) DeleteQuestionGroupWithMode(groupID, mode string) error {
+	scopes := []journalScope{
+		{table: "question_groups"},
+		{table: "question_group_relations"},
+	}
+	return a.journaled("Delete group", scopes, func() error {
+		if err := a.db.DeleteQuestionGroupTree(groupID, mode, a.now()); err != nil {
+			return fmt.Errorf("failed to delete question group: %v", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"testing"
+)
+
+// createTestGroup creates a group with the given parent for tree tests
+func createTestGroup(t *testing.T, app *App, name, parentID string) *QuestionGroup {
+	t.Helper()
+	group, err := app.CreateQuestionGroup(name, "", parentID, "#1890ff", "folder")
+	if err != nil {
+		t.Fatalf("Failed to create group %s: %v", name, err)
+	}
+	return group
+}
+
+// TestGroupTreeCounts tests tree assembly and recursive question counts
+func TestGroupTreeCounts(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	medicine := createTestGroup(t, app, "Medicine", "")
+	cardiology := createTestGroup(t, app, "Cardiology", medicine.ID)
+	createTestGroup(t, app, "Arrhythmia", cardiology.ID)
+	createTestGroup(t, app, "Statistics", "")
+
+	data := []map[string]interface{}{
+		{"question": "Q1", "options": []string{"a"}, "answer": []string{"a"}},
+		{"question": "Q2", "options": []string{"b"}, "answer": []string{"b"}},
+	}
+	app.ImportQuestions(data[:1], medicine.ID)
+	app.ImportQuestions(data[1:], cardiology.ID)
+
+	// The same question in two levels of the tree is counted once
+	questions, _ := app.GetQuestionsByGroup(cardiology.ID)
+	db.AddQuestionToGroup(medicine.ID, questions[0].ID)
+
+	tree, err := app.GetGroupTree("")
+	if err != nil {
+		t.Fatalf("Failed to get group tree: %v", err)
+	}
+	if len(tree) != 2 || tree[0].Name != "Medicine" || tree[1].Name != "Statistics" {
+		t.Fatalf("Unexpected roots: %+v", tree)
+	}
+	if tree[0].QuestionCount != 2 || tree[0].TotalQuestionCount != 2 {
+		t.Errorf("Unexpected Medicine counts: direct %d, total %d", tree[0].QuestionCount, tree[0].TotalQuestionCount)
+	}
+	if len(tree[0].Children) != 1 || tree[0].Children[0].Name != "Cardiology" {
+		t.Fatalf("Expected Cardiology under Medicine, got %+v", tree[0].Children)
+	}
+	if arrhythmia := tree[0].Children[0].Children; len(arrhythmia) != 1 || arrhythmia[0].Depth != 2 {
+		t.Errorf("Expected Arrhythmia at depth 2, got %+v", arrhythmia)
+	}
+
+	subtree, err := app.GetGroupTree(cardiology.ID)
+	if err != nil {
+		t.Fatalf("Failed to get subtree: %v", err)
+	}
+	if len(subtree) != 1 || subtree[0].ID != cardiology.ID || subtree[0].TotalQuestionCount != 1 {
+		t.Errorf("Unexpected subtree: %+v", subtree)
+	}
+}
+
+// TestMoveGroupCycleProtection tests moving groups and rejecting cycles
+func TestMoveGroupCycleProtection(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	a := createTestGroup(t, app, "A", "")
+	b := createTestGroup(t, app, "B", a.ID)
+	c := createTestGroup(t, app, "C", b.ID)
+	d := createTestGroup(t, app, "D", "")
+
+	if err := app.MoveGroup(a.ID, c.ID, 0); err == nil {
+		t.Error("Expected moving a group under its descendant to fail")
+	}
+	if err := app.MoveGroup(a.ID, a.ID, 0); err == nil {
+		t.Error("Expected moving a group under itself to fail")
+	}
+	if err := app.MoveGroup("missing", a.ID, 0); err == nil {
+		t.Error("Expected moving an unknown group to fail")
+	}
+
+	orphan := *d
+	missing := "missing"
+	orphan.ParentID = &missing
+	if err := app.UpdateQuestionGroup(orphan); err == nil {
+		t.Error("Expected UpdateQuestionGroup to reject an unknown parent")
+	}
+	if _, err := app.CreateQuestionGroup("Orphan", "", "missing", "", ""); err == nil {
+		t.Error("Expected creating a group under an unknown parent to fail")
+	}
+	if _, err := app.CreateSmartGroup("Orphan", "", "missing", "", "", SmartGroupQuery{Match: SmartMatchAll}); err == nil {
+		t.Error("Expected creating a smart group under an unknown parent to fail")
+	}
+
+	a.ParentID = &c.ID
+	if err := app.UpdateQuestionGroup(*a); err == nil {
+		t.Error("Expected UpdateQuestionGroup to reject a cycle")
+	}
+
+	// Move D to the front of A's children
+	if err := app.MoveGroup(d.ID, a.ID, 0); err != nil {
+		t.Fatalf("Failed to move group: %v", err)
+	}
+	tree, _ := app.GetGroupTree(a.ID)
+	children := tree[0].Children
+	if len(children) != 2 || children[0].ID != d.ID || children[1].ID != b.ID {
+		t.Errorf("Expected children [D, B], got %+v", children)
+	}
+
+	descendants, _ := app.GetGroupDescendants(a.ID)
+	if len(descendants) != 3 {
+		t.Errorf("Expected 3 descendants, got %v", descendants)
+	}
+}
+
+// TestDeleteGroupModes tests reparenting and recursive group deletion
+func TestDeleteGroupModes(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	root := createTestGroup(t, app, "Root", "")
+	middle := createTestGroup(t, app, "Middle", root.ID)
+	leaf := createTestGroup(t, app, "Leaf", middle.ID)
+
+	if err := app.DeleteQuestionGroup(middle.ID); err != nil {
+		t.Fatalf("Failed to delete group: %v", err)
+	}
+	tree, _ := app.GetGroupTree("")
+	if len(tree) != 1 || len(tree[0].Children) != 1 || tree[0].Children[0].ID != leaf.ID {
+		t.Fatalf("Expected Leaf to be reparented under Root, got %+v", tree)
+	}
+
+	if err := app.DeleteQuestionGroupWithMode(root.ID, GroupDeleteRecursive); err != nil {
+		t.Fatalf("Failed to delete group recursively: %v", err)
+	}
+	groups, _ := app.GetQuestionGroups()
+	if len(groups) != 0 {
+		t.Errorf("Expected all groups deleted, got %d", len(groups))
+	}
+
+	if err := app.DeleteQuestionGroupWithMode(root.ID, "bogus"); err == nil {
+		t.Error("Expected an error for a missing group")
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// minAttemptsForItemFlags is the number of attempts needed before an item is flagged
+const minAttemptsForItemFlags = 5
+
+// parseQuestionOptions decodes a question's options, accepting plain strings as well
+// as option objects. Plain strings get their position letter as ID.
+func parseQuestionOptions(optionsJSON json.RawMessage) []QuestionOption {
+	var options []QuestionOption
+	if err := json.Unmarshal(optionsJSON, &options); err == nil {
+		return options
+	}
+
+	var texts []string
+	if err := json.Unmarshal(optionsJSON, &texts); err != nil {
+		return nil
+	}
+	options = make([]QuestionOption, len(texts))
+	for i, text := range texts {
+		options[i] = QuestionOption{ID: string(rune('a' + i)), Text: text}
+	}
+	return options
+}
+
+// parseQuestionAnswer decodes a question's answer key
+func parseQuestionAnswer(answerJSON json.RawMessage) []string {
+	var answer []string
+	if err := json.Unmarshal(answerJSON, &answer); err != nil {
+		var single string
+		if json.Unmarshal(answerJSON, &single) == nil && single != "" {
+			return []string{single}
+		}
+		return nil
+	}
+	return answer
+}
+
+// sessionRestScores returns, for every attempt, the proportion of the other questions
+// in the same session that were answered correctly, or -1 if it was the only question
+func sessionRestScores(attempts []questionAttempt) []float64 {
+	type totals struct{ answered, correct int }
+	bySession := make(map[string]totals)
+	for _, attempt := range attempts {
+		t := bySession[attempt.SessionID]
+		t.answered++
+		if attempt.IsCorrect {
+			t.correct++
+		}
+		bySession[attempt.SessionID] = t
+	}
+
+	scores := make([]float64, len(attempts))
+	for i, attempt := range attempts {
+		t := bySession[attempt.SessionID]
+		others := t.answered - 1
+		if others == 0 {
+			scores[i] = -1
+			continue
+		}
+		correct := t.correct
+		if attempt.IsCorrect {
+			correct--
+		}
+		scores[i] = float64(correct) / float64(others)
+	}
+	return scores
+}
+
+// pointBiserial correlates item correctness with rest scores. Returns nil when either
+// variable has no variance.
+func pointBiserial(correct []bool, scores []float64) *float64 {
+	n := float64(len(scores))
+	if n < 2 {
+		return nil
+	}
+	var sum, sumCorrect float64
+	var nCorrect int
+	for i, score := range scores {
+		sum += score
+		if correct[i] {
+			sumCorrect += score
+			nCorrect++
+		}
+	}
+	if nCorrect == 0 || nCorrect == len(scores) {
+		return nil
+	}
+
+	mean := sum / n
+	var variance float64
+	for _, score := range scores {
+		variance += (score - mean) * (score - mean)
+	}
+	sd := math.Sqrt(variance / n)
+	if sd == 0 {
+		return nil
+	}
+
+	p := float64(nCorrect) / n
+	meanCorrect := sumCorrect / float64(nCorrect)
+	meanIncorrect := (sum - sumCorrect) / (n - float64(nCorrect))
+	r := (meanCorrect - meanIncorrect) / sd * math.Sqrt(p*(1-p))
+	return &r
+}
+
+// analyzeItem computes the statistics of one question from its attempts and their rest scores
+func analyzeItem(q Question, attempts []questionAttempt, restScores []float64) ItemAnalysis {
+	analysis := ItemAnalysis{
+		QuestionID: q.ID,
+		Question:   q.Question,
+		Attempts:   len(attempts),
+		Options:    []OptionStatistics{},
+		Flags:      []string{},
+	}
+
+	key := make(map[string]bool)
+	for _, id := range parseQuestionAnswer(q.Answer) {
+		key[id] = true
+	}
+	counts := make(map[string]int)
+	var correct []bool
+	var scores []float64
+	for i, attempt := range attempts {
+		if attempt.IsCorrect {
+			analysis.Correct++
+		}
+		if len(attempt.UserAnswer) == 0 {
+			analysis.Omitted++
+		}
+		for _, id := range attempt.UserAnswer {
+			counts[id]++
+		}
+		if restScores[i] >= 0 {
+			correct = append(correct, attempt.IsCorrect)
+			scores = append(scores, restScores[i])
+		}
+	}
+	if analysis.Attempts > 0 {
+		analysis.PValue = float64(analysis.Correct) / float64(analysis.Attempts)
+	}
+	analysis.PointBiserial = pointBiserial(correct, scores)
+
+	// The least chosen key option is the bar distractors are compared against
+	minKeyCount := -1
+	for _, option := range parseQuestionOptions(q.Options) {
+		if key[option.ID] && (minKeyCount < 0 || counts[option.ID] < minKeyCount) {
+			minKeyCount = counts[option.ID]
+		}
+	}
+
+	flagged := analysis.Attempts >= minAttemptsForItemFlags
+	itemFlags := make(map[string]bool)
+	for _, option := range parseQuestionOptions(q.Options) {
+		stats := OptionStatistics{
+			OptionID: option.ID,
+			Text:     option.Text,
+			IsKey:    key[option.ID],
+			Count:    counts[option.ID],
+			Flags:    []string{},
+		}
+		if analysis.Attempts > 0 {
+			stats.Frequency = float64(stats.Count) / float64(analysis.Attempts)
+		}
+		if flagged && !stats.IsKey {
+			if stats.Count == 0 {
+				stats.Flags = append(stats.Flags, ItemFlagDistractorNeverChosen)
+				itemFlags[ItemFlagDistractorNeverChosen] = true
+			} else if minKeyCount >= 0 && stats.Count > minKeyCount {
+				stats.Flags = append(stats.Flags, ItemFlagDistractorOverKey)
+				itemFlags[ItemFlagDistractorOverKey] = true
+			}
+		}
+		analysis.Options = append(analysis.Options, stats)
+	}
+	if flagged && analysis.PointBiserial != nil && *analysis.PointBiserial < 0 {
+		itemFlags[ItemFlagNegativeDiscrimination] = true
+	}
+
+	for _, flag := range []string{ItemFlagDistractorOverKey, ItemFlagNegativeDiscrimination, ItemFlagDistractorNeverChosen} {
+		if itemFlags[flag] {
+			analysis.Flags = append(analysis.Flags, flag)
+		}
+	}
+	return analysis
+}
+
+// Item analysis methods
+
+// AnalyzeItems computes classical item statistics for the questions of a group, or of
+// every question when groupID is empty. Flashcard self-grades are left out. Flagged
+// items are listed first.
+func (a *App) AnalyzeItems(groupID string) ([]ItemAnalysis, error) {
+	questions, err := a.scopeQuestions(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questions: %v", err)
+	}
+
+	// Rest scores use whole sessions, including questions outside the group
+	attempts, err := a.db.GetQuestionAttempts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attempts: %v", err)
+	}
+	attempts = scoredAttempts(attempts)
+	restScores := sessionRestScores(attempts)
+	attemptsByQuestion := make(map[string][]questionAttempt)
+	scoresByQuestion := make(map[string][]float64)
+	for i, attempt := range attempts {
+		attemptsByQuestion[attempt.QuestionID] = append(attemptsByQuestion[attempt.QuestionID], attempt)
+		scoresByQuestion[attempt.QuestionID] = append(scoresByQuestion[attempt.QuestionID], restScores[i])
+	}
+
+	results := make([]ItemAnalysis, 0, len(questions))
+	for _, q := range questions {
+		results = append(results, analyzeItem(q, attemptsByQuestion[q.ID], scoresByQuestion[q.ID]))
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return len(results[i].Flags) > 0 && len(results[j].Flags) == 0
+	})
+	return results, nil
+}
+
+// ExportItemAnalysisCSV exports the item analysis of a group in CSV format
+func (a *App) ExportItemAnalysisCSV(groupID string) (string, error) {
+	results, err := a.AnalyzeItems(groupID)
+	if err != nil {
+		return "", err
+	}
+
+	var csvBuilder strings.Builder
+	csvBuilder.WriteString("question_id,question,attempts,correct,omitted,p_value,point_biserial,options,flags\n")
+	for _, item := range results {
+		pointBiserial := ""
+		if item.PointBiserial != nil {
+			pointBiserial = fmt.Sprintf("%.3f", *item.PointBiserial)
+		}
+
+		// Options as "a*:12 (60%)" entries, the key marked with an asterisk
+		options := make([]string, 0, len(item.Options))
+		for _, option := range item.Options {
+			marker := ""
+			if option.IsKey {
+				marker = "*"
+			}
+			options = append(options, fmt.Sprintf("%s%s:%d (%.0f%%)", option.OptionID, marker, option.Count, option.Frequency*100))
+		}
+
+		csvBuilder.WriteString(fmt.Sprintf("%s,%s,%d,%d,%d,%.3f,%s,%s,%s\n",
+			escapeCsvField(item.QuestionID),
+			escapeCsvField(item.Question),
+			item.Attempts,
+			item.Correct,
+			item.Omitted,
+			item.PValue,
+			pointBiserial,
+			escapeCsvField(strings.Join(options, "; ")),
+			escapeCsvField(strings.Join(item.Flags, "; "))))
+	}
+	return csvBuilder.String(), nil
+}
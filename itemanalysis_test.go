@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestAnalyzeItems tests p-values, discrimination and distractor flags
+func TestAnalyzeItems(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	options := []QuestionOption{{ID: "a", Text: "A"}, {ID: "b", Text: "B"}, {ID: "c", Text: "C"}}
+	data := []map[string]interface{}{
+		{"question": "Miskeyed", "options": options, "answer": []string{"a"}},
+		{"question": "Anchor", "options": options, "answer": []string{"a"}},
+	}
+	app.ImportQuestions(data, "")
+	questions, _ := app.GetQuestions()
+	ids := make(map[string]string)
+	for _, q := range questions {
+		ids[q.Question] = q.ID
+	}
+
+	// Strong sessions (anchor correct) pick "b" on the miskeyed item, weak sessions pick the key
+	for s := 0; s < 6; s++ {
+		strong := s%2 == 0
+		miskeyed := []string{"a"}
+		anchor := []string{"c"}
+		if strong {
+			miskeyed = []string{"b"}
+			anchor = []string{"a"}
+		}
+		details, _ := json.Marshal([]map[string]interface{}{
+			{"questionId": ids["Miskeyed"], "userAnswer": miskeyed, "isCorrect": !strong},
+			{"questionId": ids["Anchor"], "userAnswer": anchor, "isCorrect": strong},
+		})
+		at := time.Now().AddDate(0, 0, -s).Format(time.RFC3339)
+		session := &PracticeSession{ID: fmt.Sprintf("session-%d", s), Mode: "practice", StartTime: at,
+			TotalQuestions: 2, Details: details, CreatedAt: at}
+		if err := db.CreatePracticeSession(session); err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+	}
+
+	results, err := app.AnalyzeItems("")
+	if err != nil {
+		t.Fatalf("Failed to analyze items: %v", err)
+	}
+	if len(results) != 2 || results[0].QuestionID != ids["Miskeyed"] {
+		t.Fatalf("Expected the miskeyed question first, got %+v", results)
+	}
+
+	item := results[0]
+	if item.Attempts != 6 || item.PValue != 0.5 {
+		t.Errorf("Expected 6 attempts with p-value 0.5, got %d and %.2f", item.Attempts, item.PValue)
+	}
+	if item.PointBiserial == nil || *item.PointBiserial >= 0 {
+		t.Errorf("Expected negative point-biserial, got %v", item.PointBiserial)
+	}
+	expected := []string{ItemFlagDistractorNeverChosen, ItemFlagNegativeDiscrimination}
+	for _, flag := range expected {
+		found := false
+		for _, f := range item.Flags {
+			found = found || f == flag
+		}
+		if !found {
+			t.Errorf("Expected flag %s, got %v", flag, item.Flags)
+		}
+	}
+	if item.Options[1].Count != 3 || item.Options[1].Frequency != 0.5 || len(item.Options[2].Flags) != 1 {
+		t.Errorf("Unexpected option statistics: %+v", item.Options)
+	}
+
+	csv, err := app.ExportItemAnalysisCSV("")
+	if err != nil {
+		t.Fatalf("Failed to export item analysis: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(csv), "\n"); len(lines) != 3 || !strings.Contains(lines[1], "a*:3 (50%)") {
+		t.Errorf("Unexpected CSV export:\n%s", csv)
+	}
+}
@@ -0,0 +1,550 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxJournalOperations bounds how many operations are kept for undo
+const maxJournalOperations = 100
+
+// maxJournalOperationBytes bounds the stored changes of a single operation and
+// maxJournalBytes those of the whole journal. Operations above the first limit are not
+// kept, and the journal is cleared since older operations can no longer be undone
+// safely past them.
+const (
+	maxJournalOperationBytes = 8 << 20
+	maxJournalBytes          = 32 << 20
+)
+
+// journalScope selects the rows an operation may touch. An empty where clause covers the whole table.
+type journalScope struct {
+	table string
+	where string
+	args  []interface{}
+}
+
+// journalRowChange holds the before and after image of a single row.
+// A nil Before means the row was inserted, a nil After means it was deleted.
+type journalRowChange struct {
+	Table  string                 `json:"table"`
+	Before map[string]interface{} `json:"before"`
+	After  map[string]interface{} `json:"after"`
+}
+
+// journaled runs fn as one undoable operation. Rows covered by scopes are captured before
+// and after fn runs and the differences are stored in the operation journal. Operations
+// run one at a time and must not nest: fn calls the unjournaled variants of other
+// operations instead.
+func (a *App) journaled(label string, scopes []journalScope, fn func() error) error {
+	a.journalMu.Lock()
+	defer a.journalMu.Unlock()
+
+	before, err := a.db.snapshotJournalScopes(scopes)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot data for undo: %v", err)
+	}
+
+	fnErr := fn()
+
+	after, err := a.db.snapshotJournalScopes(scopes)
+	if err != nil {
+		log.Printf("Warning: Failed to snapshot data for undo after %s: %v", label, err)
+		return fnErr
+	}
+
+	// Record even if fn failed part way, so partial bulk changes can still be undone
+	changes := diffJournalSnapshots(scopes, before, after)
+	if len(changes) > 0 {
+		if err := a.db.RecordJournalOperation(label, changes, a.now()); err != nil {
+			log.Printf("Warning: Failed to record operation %s: %v", label, err)
+		}
+	}
+
+	return fnErr
+}
+
+// withoutJournal runs a background write while no journaled operation is in progress,
+// so it is neither recorded in nor reverted by an operation that happens to overlap it
+func (a *App) withoutJournal(fn func() error) error {
+	a.journalMu.Lock()
+	defer a.journalMu.Unlock()
+	return fn()
+}
+
+// Undo reverts the most recent operation that has not been undone. The caches of the
+// answer history are rebuilt since the operation may have changed it.
+func (a *App) Undo() (*JournalOperation, error) {
+	a.journalMu.Lock()
+	defer a.journalMu.Unlock()
+
+	op, changes, err := a.db.GetLastJournalOperation(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operation to undo: %v", err)
+	}
+	if op == nil {
+		return nil, fmt.Errorf("nothing to undo")
+	}
+
+	// Apply before images in reverse order
+	reverted := make([]journalRowChange, len(changes))
+	for i, change := range changes {
+		reverted[len(changes)-1-i] = journalRowChange{Table: change.Table, Before: change.After, After: change.Before}
+	}
+	if err := a.db.ApplyJournalChanges(reverted); err != nil {
+		return nil, fmt.Errorf("failed to undo %s: %v", op.Label, err)
+	}
+	if err := a.db.SetJournalOperationUndone(op.ID, true); err != nil {
+		return nil, fmt.Errorf("failed to update operation journal: %v", err)
+	}
+	op.Undone = true
+
+	a.recordJournalRevisions(reverted, RevisionActorUndo, "Undo: "+op.Label)
+	a.rebuildAttemptCaches()
+	return op, nil
+}
+
+// Redo reapplies the most recently undone operation and rebuilds the caches of the
+// answer history
+func (a *App) Redo() (*JournalOperation, error) {
+	a.journalMu.Lock()
+	defer a.journalMu.Unlock()
+
+	op, changes, err := a.db.GetLastJournalOperation(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operation to redo: %v", err)
+	}
+	if op == nil {
+		return nil, fmt.Errorf("nothing to redo")
+	}
+
+	if err := a.db.ApplyJournalChanges(changes); err != nil {
+		return nil, fmt.Errorf("failed to redo %s: %v", op.Label, err)
+	}
+	if err := a.db.SetJournalOperationUndone(op.ID, false); err != nil {
+		return nil, fmt.Errorf("failed to update operation journal: %v", err)
+	}
+	op.Undone = false
+
+	a.recordJournalRevisions(changes, RevisionActorRedo, "Redo: "+op.Label)
+	a.rebuildAttemptCaches()
+	return op, nil
+}
+
+// ListRecentOperations returns the most recent journal operations, newest first
+func (a *App) ListRecentOperations(limit int) ([]JournalOperation, error) {
+	if limit <= 0 {
+		limit = maxJournalOperations
+	}
+	ops, err := a.db.GetJournalOperations(limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list operations: %v", err)
+	}
+	return ops, nil
+}
+
+// recordJournalRevisions keeps question revision history in step with undo and redo
+func (a *App) recordJournalRevisions(changes []journalRowChange, actor, reason string) {
+	seen := make(map[string]bool)
+	for _, change := range changes {
+		if change.Table != "questions" || change.After == nil {
+			continue
+		}
+		questionID, ok := journalValue(change.After["id"]).(string)
+		if !ok || seen[questionID] {
+			continue
+		}
+		seen[questionID] = true
+		if err := a.recordQuestionRevision(questionID, actor, reason); err != nil {
+			log.Printf("Warning: Failed to record revision for question %s: %v", questionID, err)
+		}
+	}
+}
+
+// diffJournalSnapshots compares row images per table and returns the changed rows
+func diffJournalSnapshots(scopes []journalScope, before, after map[string]map[string]map[string]interface{}) []journalRowChange {
+	var changes []journalRowChange
+	visited := make(map[string]bool)
+	for _, scope := range scopes {
+		if visited[scope.table] {
+			continue
+		}
+		visited[scope.table] = true
+
+		beforeRows := before[scope.table]
+		afterRows := after[scope.table]
+		for key, row := range beforeRows {
+			afterRow, exists := afterRows[key]
+			if !exists {
+				changes = append(changes, journalRowChange{Table: scope.table, Before: row})
+			} else if !journalRowsEqual(row, afterRow) {
+				changes = append(changes, journalRowChange{Table: scope.table, Before: row, After: afterRow})
+			}
+		}
+		for key, row := range afterRows {
+			if _, exists := beforeRows[key]; !exists {
+				changes = append(changes, journalRowChange{Table: scope.table, After: row})
+			}
+		}
+	}
+	return changes
+}
+
+// journalRowsEqual compares two row images
+func journalRowsEqual(a, b map[string]interface{}) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(aJSON, bJSON)
+}
+
+// Operation journal database methods
+
+// journalTableColumns returns the columns and primary key columns of a table
+func (d *Database) journalTableColumns(table string) ([]string, []string, error) {
+	rows, err := d.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	pkColumns := make(map[int]string)
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return nil, nil, err
+		}
+		columns = append(columns, name)
+		if pk > 0 {
+			pkColumns[pk] = name
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(columns) == 0 {
+		return nil, nil, fmt.Errorf("unknown table %s", table)
+	}
+
+	pk := make([]string, 0, len(pkColumns))
+	for i := 1; i <= len(pkColumns); i++ {
+		pk = append(pk, pkColumns[i])
+	}
+	if len(pk) == 0 {
+		pk = columns
+	}
+	return columns, pk, nil
+}
+
+// snapshotJournalScopes reads the rows covered by scopes, keyed by table and primary
+// key. All scopes are read in one transaction so they show a single consistent state.
+func (d *Database) snapshotJournalScopes(scopes []journalScope) (map[string]map[string]map[string]interface{}, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	snapshot := make(map[string]map[string]map[string]interface{})
+	for _, scope := range scopes {
+		columns, pk, err := d.journalTableColumns(scope.table)
+		if err != nil {
+			return nil, err
+		}
+
+		// The unary plus keeps raw stored values instead of driver-converted timestamps
+		selects := make([]string, len(columns))
+		for i, column := range columns {
+			selects[i] = fmt.Sprintf("+[%s]", column)
+		}
+		query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selects, ", "), scope.table)
+		if scope.where != "" {
+			query += " WHERE " + scope.where
+		}
+
+		rows, err := tx.Query(query, scope.args...)
+		if err != nil {
+			return nil, err
+		}
+
+		tableRows := snapshot[scope.table]
+		if tableRows == nil {
+			tableRows = make(map[string]map[string]interface{})
+			snapshot[scope.table] = tableRows
+		}
+		for rows.Next() {
+			values := make([]interface{}, len(columns))
+			pointers := make([]interface{}, len(columns))
+			for i := range values {
+				pointers[i] = &values[i]
+			}
+			if err := rows.Scan(pointers...); err != nil {
+				rows.Close()
+				return nil, err
+			}
+
+			row := make(map[string]interface{}, len(columns))
+			for i, column := range columns {
+				if b, ok := values[i].([]byte); ok {
+					// Tag blobs so they are written back as blobs rather than text
+					row[column] = map[string]interface{}{"blob": string(b)}
+				} else {
+					row[column] = values[i]
+				}
+			}
+			tableRows[journalRowKey(row, pk)] = row
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return snapshot, nil
+}
+
+// journalRowKey builds a lookup key from a row's primary key values
+func journalRowKey(row map[string]interface{}, pk []string) string {
+	values := make([]interface{}, len(pk))
+	for i, column := range pk {
+		values[i] = row[column]
+	}
+	key, _ := json.Marshal(values)
+	return string(key)
+}
+
+// ApplyJournalChanges writes the after image of each change in a single transaction
+func (d *Database) ApplyJournalChanges(changes []journalRowChange) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, change := range changes {
+		_, pk, err := d.journalTableColumns(change.Table)
+		if err != nil {
+			return err
+		}
+
+		// Remove the current version of the row, then write the target image if any
+		keyRow := change.Before
+		if keyRow == nil {
+			keyRow = change.After
+		}
+		conditions := make([]string, len(pk))
+		args := make([]interface{}, len(pk))
+		for i, column := range pk {
+			conditions[i] = fmt.Sprintf("[%s] = ?", column)
+			args[i] = journalValue(keyRow[column])
+		}
+		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s", change.Table, strings.Join(conditions, " AND ")), args...); err != nil {
+			return err
+		}
+
+		if change.After == nil {
+			continue
+		}
+		columns := make([]string, 0, len(change.After))
+		placeholders := make([]string, 0, len(change.After))
+		values := make([]interface{}, 0, len(change.After))
+		for column, value := range change.After {
+			columns = append(columns, fmt.Sprintf("[%s]", column))
+			placeholders = append(placeholders, "?")
+			values = append(values, journalValue(value))
+		}
+		query := fmt.Sprintf("INSERT OR REPLACE INTO %s (%s) VALUES (%s)", change.Table, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+		if _, err := tx.Exec(query, values...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// journalValue converts a decoded JSON value back into a database value
+func journalValue(value interface{}) interface{} {
+	if tagged, ok := value.(map[string]interface{}); ok {
+		if blob, ok := tagged["blob"].(string); ok {
+			return []byte(blob)
+		}
+	}
+	if number, ok := value.(json.Number); ok {
+		if i, err := strconv.ParseInt(string(number), 10, 64); err == nil {
+			return i
+		}
+		if f, err := number.Float64(); err == nil {
+			return f
+		}
+		return string(number)
+	}
+	return value
+}
+
+// RecordJournalOperation stores a new operation, discarding the redo history and
+// trimming the journal to its maximum size
+func (d *Database) RecordJournalOperation(label string, changes []journalRowChange, at time.Time) error {
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	if len(changesJSON) > maxJournalOperationBytes {
+		if _, err := d.db.Exec(`DELETE FROM operation_journal`); err != nil {
+			return err
+		}
+		return fmt.Errorf("%s changed %d bytes, more than the %d bytes kept for undo; the undo history was cleared",
+			label, len(changesJSON), maxJournalOperationBytes)
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM operation_journal WHERE undone = 1`); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO operation_journal (label, changes, undone, created_at) VALUES (?, ?, 0, ?)`,
+		label, string(changesJSON), at.Format(time.RFC3339)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM operation_journal WHERE id NOT IN (SELECT id FROM operation_journal ORDER BY id DESC LIMIT ?)`, maxJournalOperations); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM operation_journal WHERE id IN (
+			  SELECT id FROM (SELECT id, SUM(length(changes)) OVER (ORDER BY id DESC) AS total FROM operation_journal)
+			  WHERE total > ?)`, maxJournalBytes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetLastJournalOperation returns the newest operation that is not undone, or when
+// undone is true, the most recently undone operation. It returns nil if there is none.
+func (d *Database) GetLastJournalOperation(undone bool) (*JournalOperation, []journalRowChange, error) {
+	query := `SELECT id, label, changes, undone, created_at FROM operation_journal WHERE undone = 0 ORDER BY id DESC LIMIT 1`
+	if undone {
+		query = `SELECT id, label, changes, undone, created_at FROM operation_journal WHERE undone = 1 ORDER BY id ASC LIMIT 1`
+	}
+
+	var op JournalOperation
+	var changesJSON string
+	err := d.db.QueryRow(query).Scan(&op.ID, &op.Label, &changesJSON, &op.Undone, &op.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changes, err := decodeJournalChanges(changesJSON)
+	if err != nil {
+		return nil, nil, err
+	}
+	op.ChangeCount = len(changes)
+	return &op, changes, nil
+}
+
+// decodeJournalChanges parses stored changes, keeping numbers exact
+func decodeJournalChanges(changesJSON string) ([]journalRowChange, error) {
+	decoder := json.NewDecoder(strings.NewReader(changesJSON))
+	decoder.UseNumber()
+	var changes []journalRowChange
+	if err := decoder.Decode(&changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// SetJournalOperationUndone marks an operation as undone or redone
+func (d *Database) SetJournalOperationUndone(id int64, undone bool) error {
+	_, err := d.db.Exec(`UPDATE operation_journal SET undone = ? WHERE id = ?`, undone, id)
+	return err
+}
+
+// GetJournalOperations returns up to limit operations, newest first
+func (d *Database) GetJournalOperations(limit int) ([]JournalOperation, error) {
+	query := `SELECT id, label, json_array_length(changes), undone, created_at
+			  FROM operation_journal ORDER BY id DESC LIMIT ?`
+
+	rows, err := d.db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ops []JournalOperation
+	for rows.Next() {
+		var op JournalOperation
+		if err := rows.Scan(&op.ID, &op.Label, &op.ChangeCount, &op.Undone, &op.CreatedAt); err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+	return ops, rows.Err()
+}
+
+// questionBankJournalScopes covers every question with its revision history, group,
+// group membership and tag
+func questionBankJournalScopes() []journalScope {
+	return []journalScope{
+		{table: "questions"},
+		{table: "question_revisions"},
+		{table: "question_groups"},
+		{table: "question_group_relations"},
+		{table: "tags"},
+		{table: "question_tags"},
+	}
+}
+
+// resetJournalScopes covers every table ResetAllData deletes, so the reset can be undone
+func resetJournalScopes() []journalScope {
+	return append(questionBankJournalScopes(),
+		journalScope{table: "exam_blueprints"},
+		journalScope{table: "question_templates"},
+		journalScope{table: "template_instances"},
+		journalScope{table: "question_suspensions"},
+		journalScope{table: "study_plans"},
+		journalScope{table: "readiness_stats"},
+		journalScope{table: "question_exposures"},
+		journalScope{table: "study_goals"},
+		journalScope{table: "achievements"},
+		journalScope{table: "practice_sessions"},
+		journalScope{table: "active_sessions"},
+		journalScope{table: "session_events"},
+		journalScope{table: "timed_exams"},
+		journalScope{table: "adaptive_sessions"},
+		journalScope{table: "calibration_runs"},
+		journalScope{table: "item_calibrations"},
+		journalScope{table: "user_settings"},
+	)
+}
+
+// questionJournalScopes covers a single question and its tag links. Tags are covered
+// as a whole since saving a question may create new tags.
+func questionJournalScopes(questionID string) []journalScope {
+	return []journalScope{
+		{table: "questions", where: "id = ?", args: []interface{}{questionID}},
+		{table: "question_tags", where: "question_id = ?", args: []interface{}{questionID}},
+		{table: "tags"},
+	}
+}
+
+// wrongQuestionJournalScopes covers the wrong-question entry of a single question
+func wrongQuestionJournalScopes(questionID string) []journalScope {
+	return []journalScope{{table: "wrong_questions", where: "question_id = ?", args: []interface{}{questionID}}}
+}
+
+// suspensionJournalScopes covers the suspension of a single question
+func suspensionJournalScopes(questionID string) []journalScope {
+	return []journalScope{{table: "question_suspensions", where: "question_id = ?", args: []interface{}{questionID}}}
+}
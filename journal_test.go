@@ -0,0 +1,274 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestUndoRedoBulkImport tests that an import is undone and redone as one operation
+func TestUndoRedoBulkImport(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	group, err := app.CreateQuestionGroup("Journal Group", "", "", "#1890ff", "folder")
+	if err != nil {
+		t.Fatalf("Failed to create group: %v", err)
+	}
+
+	data := []map[string]interface{}{
+		{"question": "Q1", "options": []string{"a", "b"}, "answer": []string{"a"}},
+		{"question": "Q2", "options": []string{"a", "b"}, "answer": []string{"b"}},
+	}
+	if result := app.ImportQuestions(data, group.ID); result.Imported != 2 {
+		t.Fatalf("Expected 2 imported questions, got %d (%v)", result.Imported, result.Errors)
+	}
+
+	ops, err := app.ListRecentOperations(10)
+	if err != nil {
+		t.Fatalf("Failed to list operations: %v", err)
+	}
+	if len(ops) != 2 || ops[0].Label != "Import 2 questions" {
+		t.Fatalf("Expected import and group creation operations, got %+v", ops)
+	}
+	// Two questions, their group memberships and their first revisions
+	if ops[0].ChangeCount != 6 {
+		t.Errorf("Expected 6 row changes for import, got %d", ops[0].ChangeCount)
+	}
+
+	if _, err := app.Undo(); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	questions, _ := app.GetQuestionsByGroup(group.ID)
+	if len(questions) != 0 {
+		t.Fatalf("Expected no questions after undo, got %d", len(questions))
+	}
+
+	if _, err := app.Redo(); err != nil {
+		t.Fatalf("Failed to redo: %v", err)
+	}
+	questions, _ = app.GetQuestionsByGroup(group.ID)
+	if len(questions) != 2 {
+		t.Fatalf("Expected 2 questions after redo, got %d", len(questions))
+	}
+	if questions[0].CreatedAt == "" {
+		t.Error("Expected restored questions to keep their timestamps")
+	}
+
+	if _, err := app.Redo(); err == nil {
+		t.Error("Expected an error when there is nothing to redo")
+	}
+}
+
+// TestUndoResetAllData tests that a full reset can be undone
+func TestUndoResetAllData(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	if result := app.InitializeDemoData(); result.Imported == 0 {
+		t.Fatalf("Failed to initialize demo data: %v", result.Errors)
+	}
+	if err := app.SetUserSetting("studyGoal", 30); err != nil {
+		t.Fatalf("Failed to set setting: %v", err)
+	}
+	if _, err := app.CreateExamBlueprint(ExamBlueprint{Name: "Boards", Sections: []BlueprintSection{{Name: "All", Count: 1}}}); err != nil {
+		t.Fatalf("Failed to create blueprint: %v", err)
+	}
+	template, err := app.CreateQuestionTemplate(dosingTemplate())
+	if err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+	if _, err := app.StartSession("", PracticeModePractice, []string{template.ID}); err != nil {
+		t.Fatalf("Failed to start template session: %v", err)
+	}
+	groupsBefore, _ := app.GetQuestionGroups()
+	questionsBefore, _ := app.GetQuestions()
+	if err := app.SuspendQuestion(questionsBefore[0].ID); err != nil {
+		t.Fatalf("Failed to suspend question: %v", err)
+	}
+	if _, err := app.CreateStudyPlan(StudyPlanOptions{ExamDate: time.Now().AddDate(0, 0, 7).Format(calendarDateLayout)}); err != nil {
+		t.Fatalf("Failed to create study plan: %v", err)
+	}
+	if _, err := app.SetGoal(GoalPeriodWeekly, GoalMetricMinutes, 120); err != nil {
+		t.Fatalf("Failed to set goal: %v", err)
+	}
+	if err := app.db.UnlockAchievement(achievementDefinitions[0].id, time.Now()); err != nil {
+		t.Fatalf("Failed to unlock achievement: %v", err)
+	}
+
+	if err := app.ResetAllData(); err != nil {
+		t.Fatalf("Failed to reset data: %v", err)
+	}
+	if questions, _ := app.GetQuestions(); len(questions) != 0 {
+		t.Fatalf("Expected no questions after reset, got %d", len(questions))
+	}
+	if blueprints, _ := app.GetExamBlueprints(); len(blueprints) != 0 {
+		t.Errorf("Expected no blueprints after reset, got %d", len(blueprints))
+	}
+
+	op, err := app.Undo()
+	if err != nil {
+		t.Fatalf("Failed to undo reset: %v", err)
+	}
+	if op.Label != "Reset all data" {
+		t.Errorf("Expected to undo reset, undid %s", op.Label)
+	}
+
+	groupsAfter, _ := app.GetQuestionGroups()
+	questionsAfter, _ := app.GetQuestions()
+	if len(groupsAfter) != len(groupsBefore) || len(questionsAfter) != len(questionsBefore) {
+		t.Errorf("Expected %d groups and %d questions restored, got %d and %d",
+			len(groupsBefore), len(questionsBefore), len(groupsAfter), len(questionsAfter))
+	}
+	for i, g := range groupsAfter {
+		if len(g.QuestionIds) != len(groupsBefore[i].QuestionIds) {
+			t.Errorf("Group %s membership not restored", g.Name)
+		}
+	}
+	if goal, err := app.GetUserSetting("studyGoal"); err != nil || goal.(float64) != 30 {
+		t.Errorf("Expected studyGoal 30 restored, got %v (%v)", goal, err)
+	}
+	if blueprints, _ := app.GetExamBlueprints(); len(blueprints) != 1 {
+		t.Errorf("Expected the blueprint restored, got %d", len(blueprints))
+	}
+	var instances int
+	db.db.QueryRow(`SELECT COUNT(*) FROM template_instances`).Scan(&instances)
+	if templates, _ := app.GetQuestionTemplates(""); len(templates) != 1 || instances != 1 {
+		t.Errorf("Expected the template and its variant restored, got %d and %d", len(templates), instances)
+	}
+	if suspended, _ := app.GetSuspendedQuestions(); len(suspended) != 1 {
+		t.Errorf("Expected the suspension restored, got %d", len(suspended))
+	}
+	if plans, _ := app.GetStudyPlans(); len(plans) != 1 {
+		t.Errorf("Expected the study plan restored, got %d", len(plans))
+	}
+	goals, _ := app.GetGoals()
+	achievements, _ := app.GetAchievements()
+	if len(goals) != 2 || !achievements[0].Unlocked {
+		t.Errorf("Expected the goal and achievement restored, got %+v and %+v", goals, achievements[0])
+	}
+}
+
+// journalTestTableCounts returns the number of rows in every table but the journal itself
+func journalTestTableCounts(t *testing.T, db *Database) map[string]int {
+	rows, err := db.db.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name != 'operation_journal'`)
+	if err != nil {
+		t.Fatalf("Failed to list tables: %v", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		rows.Scan(&name)
+		tables = append(tables, name)
+	}
+	rows.Close()
+
+	counts := make(map[string]int, len(tables))
+	for _, table := range tables {
+		var count int
+		db.db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count)
+		counts[table] = count
+	}
+	return counts
+}
+
+// TestUndoResetRestoresEveryTable tests that undoing a reset restores every table it emptied
+func TestUndoResetRestoresEveryTable(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	app.InitializeDemoData()
+	session, err := app.StartSession("", PracticeModePractice, nil)
+	if err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	app.SubmitSessionAnswer(session.ID, session.QuestionIDs[0], []string{"a"}, 10, "")
+	app.RecordSessionEvent(session.ID, SessionEventPaused, "", nil)
+	app.FinishSession(session.ID)
+	app.waitForCalibration()
+
+	before := journalTestTableCounts(t, db)
+	if err := app.ResetAllData(); err != nil {
+		t.Fatalf("Failed to reset data: %v", err)
+	}
+	if _, err := app.Undo(); err != nil {
+		t.Fatalf("Failed to undo reset: %v", err)
+	}
+	after := journalTestTableCounts(t, db)
+	for table, count := range before {
+		if after[table] != count {
+			t.Errorf("Expected %d rows in %s after undo, got %d", count, table, after[table])
+		}
+	}
+}
+
+// TestJournalSizeLimit tests that an operation too large to keep clears the undo history
+func TestJournalSizeLimit(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	created, err := app.CreateQuestion(Question{
+		Question:    "Long question",
+		Options:     json.RawMessage(`[]`),
+		Answer:      json.RawMessage(`["a"]`),
+		Explanation: strings.Repeat("x", maxJournalOperationBytes),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create question: %v", err)
+	}
+	if err := app.DeleteQuestion(created.ID); err != nil {
+		t.Fatalf("Failed to delete question: %v", err)
+	}
+
+	var stored int
+	db.db.QueryRow(`SELECT COALESCE(SUM(length(changes)), 0) FROM operation_journal`).Scan(&stored)
+	if stored != 0 {
+		t.Errorf("Expected the oversized operations to be left out of the journal, got %d bytes", stored)
+	}
+	if _, err := app.Undo(); err == nil {
+		t.Error("Expected nothing to undo past an operation too large to keep")
+	}
+}
+
+// TestSessionWritesWaitForJournaledOperations tests that session writes do not slip
+// between the snapshots of a journaled operation
+func TestSessionWritesWaitForJournaledOperations(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	data := []map[string]interface{}{
+		{"question": "Q1", "options": []string{"A", "B"}, "answer": []string{"a"}},
+	}
+	app.ImportQuestions(data, "")
+	session, _ := app.StartSession("", PracticeModePractice, nil)
+
+	app.journalMu.Lock()
+	done := make(chan struct{})
+	go func() {
+		app.SubmitSessionAnswer(session.ID, session.QuestionIDs[0], []string{"a"}, 5, "")
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Error("Expected the answer to wait for the journaled operation")
+	case <-time.After(50 * time.Millisecond):
+	}
+	app.journalMu.Unlock()
+	<-done
+
+	stored, _ := app.GetSession(session.ID)
+	if len(stored.Records) != 1 {
+		t.Errorf("Expected the answer to be saved once the operation finished, got %+v", stored.Records)
+	}
+}
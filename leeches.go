@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// Leech detection defaults, used when the settings are missing or invalid
+const (
+	defaultLeechThreshold = 8
+	defaultLeechAction    = LeechActionFlag
+)
+
+// Question suspension database methods
+
+// SuspendQuestion keeps a question out of practice assembly. An existing suspension
+// keeps its original reason and time.
+func (d *Database) SuspendQuestion(questionID, reason string, at time.Time) error {
+	_, err := d.db.Exec(`INSERT OR IGNORE INTO question_suspensions (question_id, reason, suspended_at) VALUES (?, ?, ?)`,
+		questionID, reason, at.Format(time.RFC3339))
+	return err
+}
+
+// UnsuspendQuestion returns a question to practice assembly
+func (d *Database) UnsuspendQuestion(questionID string) error {
+	_, err := d.db.Exec(`DELETE FROM question_suspensions WHERE question_id = ?`, questionID)
+	return err
+}
+
+// GetQuestionSuspensions returns all suspensions, most recent first
+func (d *Database) GetQuestionSuspensions() ([]QuestionSuspension, error) {
+	rows, err := d.db.Query(`SELECT question_id, reason, suspended_at FROM question_suspensions ORDER BY suspended_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suspensions []QuestionSuspension
+	for rows.Next() {
+		var s QuestionSuspension
+		if err := rows.Scan(&s.QuestionID, &s.Reason, &s.SuspendedAt); err != nil {
+			return nil, err
+		}
+		suspensions = append(suspensions, s)
+	}
+	return suspensions, rows.Err()
+}
+
+// GetSuspendedQuestionIDs returns the IDs of all suspended questions
+func (d *Database) GetSuspendedQuestionIDs() (map[string]bool, error) {
+	suspensions, err := d.GetQuestionSuspensions()
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool, len(suspensions))
+	for _, s := range suspensions {
+		ids[s.QuestionID] = true
+	}
+	return ids, nil
+}
+
+// FlagLeeches flags the wrong questions with at least the given number of lapses and
+// returns the IDs of the questions that were newly flagged
+func (d *Database) FlagLeeches(threshold int) ([]string, error) {
+	rows, err := d.db.Query(`UPDATE wrong_questions SET leech = TRUE
+			  WHERE COALESCE(lapses, 0) >= ? AND NOT COALESCE(leech, FALSE)
+			  RETURNING question_id`, threshold)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ResetLeech clears the leech flag and lapse count of a wrong question and lifts a
+// suspension made by leech detection
+func (d *Database) ResetLeech(questionID string) error {
+	if _, err := d.db.Exec(`UPDATE wrong_questions SET leech = FALSE, lapses = 0 WHERE question_id = ?`, questionID); err != nil {
+		return err
+	}
+	_, err := d.db.Exec(`DELETE FROM question_suspensions WHERE question_id = ? AND reason = ?`, questionID, SuspensionLeech)
+	return err
+}
+
+// Leech methods
+
+// leechSettings returns the configured lapse threshold and leech action
+func (a *App) leechSettings() (int, string) {
+	threshold, action := defaultLeechThreshold, defaultLeechAction
+	if value, err := a.db.GetSetting("leechThreshold"); err == nil {
+		var configured float64
+		if json.Unmarshal(value, &configured) == nil && configured >= 1 {
+			threshold = int(configured)
+		}
+	}
+	if value, err := a.db.GetSetting("leechAction"); err == nil {
+		var configured string
+		if json.Unmarshal(value, &configured) == nil && (configured == LeechActionFlag || configured == LeechActionSuspend) {
+			action = configured
+		}
+	}
+	return threshold, action
+}
+
+// detectLeeches flags every wrong question that has reached the lapse threshold and
+// suspends the new leeches when the leech action is set to suspend
+func (a *App) detectLeeches() error {
+	threshold, action := a.leechSettings()
+	flagged, err := a.db.FlagLeeches(threshold)
+	if err != nil {
+		return fmt.Errorf("failed to flag leeches: %v", err)
+	}
+	if action != LeechActionSuspend {
+		return nil
+	}
+	for _, questionID := range flagged {
+		if err := a.db.SuspendQuestion(questionID, SuspensionLeech, a.now()); err != nil {
+			return fmt.Errorf("failed to suspend leech %s: %v", questionID, err)
+		}
+	}
+	return nil
+}
+
+// detectLeech runs leech detection after a review of a question. Failures are logged
+// rather than failing the review, which has already been recorded.
+func (a *App) detectLeech(questionID string) {
+	if err := a.detectLeeches(); err != nil {
+		log.Printf("Warning: Failed to detect leech %s: %v", questionID, err)
+	}
+}
+
+// practiceQuestions returns the questions of a scope available for practice, leaving
+// out suspended questions
+func (a *App) practiceQuestions(groupID string) ([]Question, error) {
+	questions, err := a.scopeQuestions(groupID)
+	if err != nil {
+		return nil, err
+	}
+	return a.withoutSuspended(questions)
+}
+
+// withoutSuspended filters suspended questions out of a question list
+func (a *App) withoutSuspended(questions []Question) ([]Question, error) {
+	suspended, err := a.db.GetSuspendedQuestionIDs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suspended questions: %v", err)
+	}
+	if len(suspended) == 0 {
+		return questions, nil
+	}
+	available := make([]Question, 0, len(questions))
+	for _, q := range questions {
+		if !suspended[q.ID] {
+			available = append(available, q)
+		}
+	}
+	return available, nil
+}
+
+// GetPracticeQuestions returns the questions of a group available for practice, or of
+// the whole bank when groupID is empty. Suspended questions are left out, and the least
+// answered come first when the preferUnderexposed setting is on.
+func (a *App) GetPracticeQuestions(groupID string) ([]Question, error) {
+	questions, err := a.practiceQuestions(groupID)
+	if err != nil {
+		return nil, err
+	}
+	a.preferUnderexposed(questions)
+	return questions, nil
+}
+
+// SuspendQuestion manually keeps a question out of practice until it is unsuspended
+func (a *App) SuspendQuestion(questionID string) error {
+	if _, err := a.db.GetQuestionByID(questionID); err != nil {
+		return fmt.Errorf("failed to get question: %v", err)
+	}
+	return a.journaled("Suspend question", suspensionJournalScopes(questionID), func() error {
+		return a.db.SuspendQuestion(questionID, SuspensionManual, a.now())
+	})
+}
+
+// UnsuspendQuestion returns a suspended question to practice. A leech stays flagged
+// and is not suspended again until its flag is reset.
+func (a *App) UnsuspendQuestion(questionID string) error {
+	return a.journaled("Unsuspend question", suspensionJournalScopes(questionID), func() error {
+		return a.db.UnsuspendQuestion(questionID)
+	})
+}
+
+// GetSuspendedQuestions returns the suspended questions with the reason for each
+func (a *App) GetSuspendedQuestions() ([]QuestionSuspension, error) {
+	suspensions, err := a.db.GetQuestionSuspensions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suspended questions: %v", err)
+	}
+	if suspensions == nil {
+		suspensions = []QuestionSuspension{}
+	}
+	return suspensions, nil
+}
+
+// ResetLeech clears a question's leech flag and lapses, e.g. after rewriting it, and
+// lifts a suspension made by leech detection
+func (a *App) ResetLeech(questionID string) error {
+	scopes := append(wrongQuestionJournalScopes(questionID), suspensionJournalScopes(questionID)...)
+	return a.journaled("Reset leech", scopes, func() error {
+		return a.db.ResetLeech(questionID)
+	})
+}
+
+// GetLeeches detects leeches with the current settings and returns them with their
+// failure history, most lapses first
+func (a *App) GetLeeches() ([]Leech, error) {
+	if err := a.detectLeeches(); err != nil {
+		return nil, err
+	}
+	wrongQuestions, err := a.db.GetWrongQuestionsWithDetails()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wrong questions: %v", err)
+	}
+	suspended, err := a.db.GetSuspendedQuestionIDs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suspended questions: %v", err)
+	}
+	attempts, err := a.db.GetQuestionAttempts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question attempts: %v", err)
+	}
+	byQuestion := groupAttemptsByQuestion(attempts)
+
+	leeches := []Leech{}
+	for _, item := range wrongQuestions {
+		wq, _ := item["wrongQuestion"].(WrongQuestion)
+		q, ok := item["question"].(Question)
+		if !ok || !wq.Leech {
+			continue
+		}
+		leech := Leech{Question: q, WrongQuestion: wq, Suspended: suspended[q.ID], Failures: []LeechFailure{}}
+		for _, attempt := range byQuestion[q.ID] {
+			leech.Attempts++
+			if attempt.IsCorrect {
+				leech.Correct++
+				continue
+			}
+			leech.Failures = append(leech.Failures, LeechFailure{
+				SessionID:  attempt.SessionID,
+				Mode:       attempt.Mode,
+				AnsweredAt: attempt.AnsweredAt.Format(time.RFC3339),
+				UserAnswer: attempt.UserAnswer,
+			})
+		}
+		sort.SliceStable(leech.Failures, func(i, j int) bool {
+			return leech.Failures[i].AnsweredAt > leech.Failures[j].AnsweredAt
+		})
+		leeches = append(leeches, leech)
+	}
+	sort.SliceStable(leeches, func(i, j int) bool {
+		return leeches[i].WrongQuestion.Lapses > leeches[j].WrongQuestion.Lapses
+	})
+	return leeches, nil
+}
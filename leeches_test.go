@@ -0,0 +1,191 @@
+package main
+
+import (
+	"testing"
+)
+
+// TestLeechDetection tests lapse counting, flagging, auto-suspension and failure history
+func TestLeechDetection(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	data := []map[string]interface{}{
+		{"question": "Capital of France", "options": []string{}, "answer": []string{"Paris"}},
+		{"question": "Capital of Italy", "options": []string{}, "answer": []string{"Rome"}},
+	}
+	app.ImportQuestions(data, "")
+	questions, _ := app.GetQuestions()
+	france, italy := questions[0].ID, questions[1].ID
+	if questions[0].Question != "Capital of France" {
+		france, italy = italy, france
+	}
+
+	session, _ := app.StartSession("", PracticeModePractice, []string{france})
+	app.SubmitSessionAnswer(session.ID, france, []string{"Lyon"}, 5, "")
+	app.FinishSession(session.ID)
+	app.waitForCalibration()
+
+	app.UpdateUserSettings(map[string]interface{}{"leechThreshold": 3, "leechAction": LeechActionSuspend})
+	for i := 0; i < 2; i++ {
+		app.UpdateWrongQuestionReview(france, false, "")
+	}
+	if leeches, _ := app.GetLeeches(); len(leeches) != 0 {
+		t.Fatalf("Expected no leeches below the threshold, got %+v", leeches)
+	}
+	app.UpdateWrongQuestionReview(france, true, "")
+	app.UpdateWrongQuestionReview(france, false, "")
+
+	leeches, err := app.GetLeeches()
+	if err != nil {
+		t.Fatalf("Failed to get leeches: %v", err)
+	}
+	if len(leeches) != 1 || leeches[0].Question.ID != france || leeches[0].WrongQuestion.Lapses != 3 || !leeches[0].Suspended {
+		t.Fatalf("Expected the suspended leech, got %+v", leeches)
+	}
+	if len(leeches[0].Failures) != 1 || leeches[0].Failures[0].UserAnswer[0] != "Lyon" || leeches[0].Attempts != 1 {
+		t.Errorf("Expected the failure history, got %+v", leeches[0])
+	}
+
+	available, _ := app.GetPracticeQuestions("")
+	if len(available) != 1 || available[0].ID != italy {
+		t.Errorf("Expected the leech to be left out of practice, got %+v", questionTexts(available))
+	}
+	if wrong, _ := app.GetWrongQuestionsForPractice(); len(wrong) != 0 {
+		t.Errorf("Expected no wrong questions to practice, got %+v", questionTexts(wrong))
+	}
+	session, _ = app.StartSession("", PracticeModePractice, nil)
+	if len(session.QuestionIDs) != 1 || session.QuestionIDs[0] != italy {
+		t.Errorf("Expected sessions to skip the leech, got %v", session.QuestionIDs)
+	}
+
+	if err := app.ResetLeech(france); err != nil {
+		t.Fatalf("Failed to reset leech: %v", err)
+	}
+	if leeches, _ := app.GetLeeches(); len(leeches) != 0 {
+		t.Errorf("Expected the reset leech to be cleared, got %+v", leeches)
+	}
+	if suspended, _ := app.GetSuspendedQuestions(); len(suspended) != 0 {
+		t.Errorf("Expected the leech suspension to be lifted, got %+v", suspended)
+	}
+}
+
+// TestSessionLapses tests that a wrong question answered wrong again in a session counts a lapse
+func TestSessionLapses(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	data := []map[string]interface{}{
+		{"question": "Capital of Spain", "options": []string{}, "answer": []string{"Madrid"}},
+	}
+	app.ImportQuestions(data, "")
+	questions, _ := app.GetQuestions()
+	spain := questions[0].ID
+	app.UpdateUserSettings(map[string]interface{}{"leechThreshold": 2, "leechAction": LeechActionFlag})
+
+	for i := 0; i < 3; i++ {
+		session, _ := app.StartSession("", PracticeModePractice, []string{spain})
+		app.SubmitSessionAnswer(session.ID, spain, []string{"Barcelona"}, 5, "")
+		app.FinishSession(session.ID)
+	}
+	app.waitForCalibration()
+
+	leeches, err := app.GetLeeches()
+	if err != nil {
+		t.Fatalf("Failed to get leeches: %v", err)
+	}
+	if len(leeches) != 1 || leeches[0].Question.ID != spain || leeches[0].WrongQuestion.Lapses != 2 {
+		t.Fatalf("Expected the repeated session misses to make a leech, got %+v", leeches)
+	}
+}
+
+// TestFlashcardLapses tests that a flashcard graded again counts one lapse and an
+// ungraded card none when the session finishes
+func TestFlashcardLapses(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	data := []map[string]interface{}{
+		{"question": "Capital of Spain", "options": []string{}, "answer": []string{"Madrid"}},
+		{"question": "Capital of Portugal", "options": []string{}, "answer": []string{"Lisbon"}},
+	}
+	app.ImportQuestions(data, "")
+	session, _ := app.StartSession("", PracticeModeFlashcard, nil)
+	ids := make(map[string]string)
+	for _, q := range session.Questions {
+		ids[q.Question] = q.ID
+	}
+	spain, portugal := ids["Capital of Spain"], ids["Capital of Portugal"]
+	app.AddWrongQuestion(spain, "")
+	app.AddWrongQuestion(portugal, "")
+
+	app.RevealFlashcard(session.ID, spain)
+	if err := app.GradeFlashcard(session.ID, spain, FlashcardGradeAgain, 5); err != nil {
+		t.Fatalf("Failed to grade card: %v", err)
+	}
+	if _, err := app.FinishSession(session.ID); err != nil {
+		t.Fatalf("Failed to finish session: %v", err)
+	}
+	app.waitForCalibration()
+
+	wrong, _ := app.GetWrongQuestions()
+	lapses := make(map[string]int)
+	for _, wq := range wrong {
+		lapses[wq.QuestionID] = wq.Lapses
+	}
+	if lapses[spain] != 1 || lapses[portugal] != 0 {
+		t.Errorf("Expected one lapse for the card graded again and none for the ungraded card, got %v", lapses)
+	}
+}
+
+// TestManualSuspension tests suspending and unsuspending questions by hand
+func TestManualSuspension(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	data := []map[string]interface{}{
+		{"question": "Q1", "options": []string{}, "answer": []string{"a"}},
+		{"question": "Q2", "options": []string{}, "answer": []string{"b"}},
+	}
+	app.ImportQuestions(data, "")
+	questions, _ := app.GetQuestions()
+
+	if err := app.SuspendQuestion("missing"); err == nil {
+		t.Error("Expected suspending an unknown question to fail")
+	}
+	if err := app.SuspendQuestion(questions[0].ID); err != nil {
+		t.Fatalf("Failed to suspend question: %v", err)
+	}
+	suspended, _ := app.GetSuspendedQuestions()
+	if len(suspended) != 1 || suspended[0].QuestionID != questions[0].ID || suspended[0].Reason != SuspensionManual {
+		t.Errorf("Unexpected suspensions: %+v", suspended)
+	}
+	if all, _ := app.GetQuestions(); len(all) != 2 {
+		t.Error("Expected suspended questions to stay in the bank")
+	}
+	if available, _ := app.GetPracticeQuestions(""); len(available) != 1 {
+		t.Errorf("Expected one question available for practice, got %d", len(available))
+	}
+
+	if _, err := app.Undo(); err != nil {
+		t.Fatalf("Failed to undo suspension: %v", err)
+	}
+	if available, _ := app.GetPracticeQuestions(""); len(available) != 2 {
+		t.Errorf("Expected undo to lift the suspension, got %d available", len(available))
+	}
+
+	app.SuspendQuestion(questions[1].ID)
+	if err := app.UnsuspendQuestion(questions[1].ID); err != nil {
+		t.Fatalf("Failed to unsuspend question: %v", err)
+	}
+	if suspended, _ := app.GetSuspendedQuestions(); len(suspended) != 0 {
+		t.Errorf("Expected no suspensions, got %+v", suspended)
+	}
+}
@@ -22,15 +22,18 @@ type Question struct {
 
 // QuestionGroup represents a group of questions
 type QuestionGroup struct {
-	ID          string          `json:"id" db:"id"`
-	Name        string          `json:"name" db:"name"`
-	Description string          `json:"description" db:"description"`
-	ParentID    *string         `json:"parentId" db:"parent_id"`
-	Color       string          `json:"color" db:"color"`
-	Icon        string          `json:"icon" db:"icon"`
-	QuestionIds []string        `json:"questionIds"`
-	CreatedAt   string          `json:"createdAt" db:"created_at"`
-	UpdatedAt   string          `json:"updatedAt" db:"updated_at"`
+	ID          string           `json:"id" db:"id"`
+	Name        string           `json:"name" db:"name"`
+	Description string           `json:"description" db:"description"`
+	ParentID    *string          `json:"parentId" db:"parent_id"`
+	Color       string           `json:"color" db:"color"`
+	Icon        string           `json:"icon" db:"icon"`
+	Position    int              `json:"position" db:"position"`      // Order among sibling groups
+	SmartQuery  *SmartGroupQuery `json:"smartQuery" db:"smart_query"` // Membership query for smart groups, nil for static groups
+	QuestionIds []string         `json:"questionIds"`
+	Coverage    *CoverageStats   `json:"coverage"` // Filled by GetQuestionGroups
+	CreatedAt   string           `json:"createdAt" db:"created_at"`
+	UpdatedAt   string           `json:"updatedAt" db:"updated_at"`
 }
 
 // QuestionGroupRelation represents the many-to-many relationship between questions and groups
@@ -50,6 +53,7 @@ type PracticeSession struct {
 	TotalQuestions int             `json:"totalQuestions" db:"total_questions"`
 	CorrectCount   int             `json:"correctCount" db:"correct_count"`
 	Details        json.RawMessage `json:"details" db:"details"`
+	Sections       []SectionTiming `json:"sections" db:"section_timing"` // Per-section timing of timed exams
 	CreatedAt      string          `json:"createdAt" db:"created_at"`
 }
 
@@ -68,6 +72,12 @@ type WrongQuestion struct {
 	TimesReviewed int    `json:"timesReviewed" db:"times_reviewed"`
 	LastResult bool      `json:"lastResult" db:"last_result"`
 	Notes      string    `json:"notes" db:"notes"`
+	Priority   int       `json:"priority" db:"priority"` // Higher priorities are reviewed first
+	Ease       float64   `json:"ease" db:"ease"`
+	IntervalDays int     `json:"intervalDays" db:"interval_days"`
+	DueAt      *string   `json:"dueAt" db:"due_at"` // Nil until the first review, meaning due now
+	Lapses     int       `json:"lapses" db:"lapses"` // Failed reviews
+	Leech      bool      `json:"leech" db:"leech"`
 }
 
 // ImportResult represents the result of importing questions
@@ -92,6 +102,8 @@ type QuestionRecord struct {
 	IsCorrect  bool     `json:"isCorrect"`
 	TimeSpent  int      `json:"timeSpent"`
 	Marked     bool     `json:"marked"`
+	Confidence string   `json:"confidence,omitempty"` // guess, unsure or sure; empty when not rated
+	Grade      string   `json:"grade,omitempty"`      // Self-grade of flashcard sessions
 }
 
 // DateRange represents a date range for filtering data
@@ -110,4 +122,885 @@ type ExportOptions struct {
 	GroupIDs              []string   `json:"groupIds"`        // Export questions from specific groups only
 	DateRange             *DateRange `json:"dateRange"`
 	Format                string     `json:"format"`          // JSON, CSV, etc.
-}
\ No newline at end of file
+}
+
+// Revision actors identify what caused a question to change
+const (
+	RevisionActorBaseline       = "baseline"
+	RevisionActorUser           = "user"
+	RevisionActorImport         = "import"
+	RevisionActorAutoDifficulty = "auto_difficulty"
+	RevisionActorRollback       = "rollback"
+	RevisionActorUndo           = "undo"
+	RevisionActorRedo           = "redo"
+)
+
+// QuestionRevision represents a recorded version of a question
+type QuestionRevision struct {
+	ID         string          `json:"id" db:"id"`
+	QuestionID string          `json:"questionId" db:"question_id"`
+	Revision   int             `json:"revision" db:"revision"`
+	Actor      string          `json:"actor" db:"actor"`
+	Reason     string          `json:"reason" db:"reason"`
+	Snapshot   json.RawMessage `json:"snapshot" db:"snapshot"`
+	Changes    json.RawMessage `json:"changes" db:"changes"`
+	CreatedAt  string          `json:"createdAt" db:"created_at"`
+}
+
+// QuestionFieldChange represents a single field difference between two question versions
+type QuestionFieldChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
+
+// JournalOperation represents a recorded, undoable operation
+type JournalOperation struct {
+	ID          int64  `json:"id" db:"id"`
+	Label       string `json:"label" db:"label"`
+	Undone      bool   `json:"undone" db:"undone"`
+	ChangeCount int    `json:"changeCount"`
+	CreatedAt   string `json:"createdAt" db:"created_at"`
+}
+
+// Tag represents a node in the tag hierarchy. Path is the slash-separated
+// name of the tag including its ancestors, e.g. "Medicine/Cardiology".
+type Tag struct {
+	ID        string  `json:"id" db:"id"`
+	Name      string  `json:"name" db:"name"`
+	ParentID  *string `json:"parentId" db:"parent_id"`
+	Path      string  `json:"path" db:"path"`
+	CreatedAt string  `json:"createdAt" db:"created_at"`
+	UpdatedAt string  `json:"updatedAt" db:"updated_at"`
+}
+
+// TagWithCount represents a tag with the number of questions using it
+type TagWithCount struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	ParentID      *string `json:"parentId"`
+	Path          string  `json:"path"`
+	QuestionCount int     `json:"questionCount"` // Questions tagged with this tag directly
+	TotalCount    int     `json:"totalCount"`    // Questions tagged with this tag or any descendant
+}
+
+// Group deletion modes for child groups
+const (
+	GroupDeleteReparent  = "reparent"  // Move child groups up to the deleted group's parent
+	GroupDeleteRecursive = "recursive" // Delete child groups along with the group
+)
+
+// GroupTreeNode represents a question group and its descendants
+type GroupTreeNode struct {
+	ID                 string          `json:"id"`
+	Name               string          `json:"name"`
+	Description        string          `json:"description"`
+	ParentID           *string         `json:"parentId"`
+	Color              string          `json:"color"`
+	Icon               string          `json:"icon"`
+	Position           int             `json:"position"`
+	Depth              int             `json:"depth"`
+	QuestionCount      int             `json:"questionCount"`      // Questions directly in this group
+	TotalQuestionCount int             `json:"totalQuestionCount"` // Distinct questions in this group and its descendants
+	Children           []GroupTreeNode `json:"children"`
+}
+
+// SmartGroupQuery defines the membership of a smart group as a set of conditions
+type SmartGroupQuery struct {
+	Match      string                `json:"match"` // "all" (default) or "any"
+	Conditions []SmartGroupCondition `json:"conditions"`
+}
+
+// SmartGroupCondition is a single filter in a smart group query.
+// Supported fields and operators:
+//
+//	tag             eq, neq (value: tag path, matches descendant tags too)
+//	difficulty      eq, neq, gt, gte, lt, lte (value: number)
+//	source          eq, neq, contains (value: string)
+//	text            contains (value: string)
+//	group           eq, neq (value: static group ID)
+//	attempts        eq, neq, gt, gte, lt, lte (value: number of recorded answers)
+//	wrongWithinDays within (value: days; answered incorrectly within that many days)
+//	markedWrong     eq (value: bool; on the wrong-question list)
+type SmartGroupCondition struct {
+	Field string      `json:"field"`
+	Op    string      `json:"op"`
+	Value interface{} `json:"value"`
+}
+
+// IRT calibration models
+const (
+	CalibrationModel1PL = "1pl"
+	CalibrationModel2PL = "2pl"
+)
+
+// Calibration run statuses
+const (
+	CalibrationStatusRunning   = "running"
+	CalibrationStatusCompleted = "completed"
+	CalibrationStatusFailed    = "failed"
+)
+
+// ItemCalibration holds the fitted IRT parameters of a question
+type ItemCalibration struct {
+	QuestionID     string  `json:"questionId" db:"question_id"`
+	Model          string  `json:"model" db:"model"`                   // Model actually fitted for this item
+	Difficulty     float64 `json:"difficulty" db:"difficulty"`         // b parameter on the ability scale
+	Discrimination float64 `json:"discrimination" db:"discrimination"` // a parameter, 1 for 1PL items
+	DifficultySE   float64 `json:"difficultySe" db:"difficulty_se"`
+	Attempts       int     `json:"attempts" db:"attempts"`
+	Correct        int     `json:"correct" db:"correct"`
+	CalibratedAt   string  `json:"calibratedAt" db:"calibrated_at"`
+}
+
+// CalibrationRun records one execution of the calibration job
+type CalibrationRun struct {
+	ID         int64    `json:"id" db:"id"`
+	Model      string   `json:"model" db:"model"`
+	Status     string   `json:"status" db:"status"`
+	Items      int      `json:"items" db:"items"`
+	Attempts   int      `json:"attempts" db:"attempts"`
+	Iterations int      `json:"iterations" db:"iterations"`
+	Ability    *float64 `json:"ability" db:"ability"`
+	AbilitySE  *float64 `json:"abilitySe" db:"ability_se"`
+	Error      string   `json:"error" db:"error"`
+	StartedAt  string   `json:"startedAt" db:"started_at"`
+	FinishedAt *string  `json:"finishedAt" db:"finished_at"`
+}
+
+// LearnerAbility is the learner's current ability estimate on the IRT scale
+type LearnerAbility struct {
+	Theta         float64 `json:"theta"`
+	StandardError float64 `json:"standardError"`
+	Attempts      int     `json:"attempts"`
+	EstimatedAt   string  `json:"estimatedAt"`
+}
+
+// Item analysis flags
+const (
+	ItemFlagDistractorNeverChosen  = "distractor_never_chosen"
+	ItemFlagDistractorOverKey      = "distractor_chosen_over_key"
+	ItemFlagNegativeDiscrimination = "negative_discrimination"
+)
+
+// OptionStatistics describes how often an option of a question was selected
+type OptionStatistics struct {
+	OptionID  string   `json:"optionId"`
+	Text      string   `json:"text"`
+	IsKey     bool     `json:"isKey"`
+	Count     int      `json:"count"`
+	Frequency float64  `json:"frequency"` // Share of attempts that selected this option
+	Flags     []string `json:"flags"`
+}
+
+// ItemAnalysis holds classical test statistics for a question
+type ItemAnalysis struct {
+	QuestionID    string             `json:"questionId"`
+	Question      string             `json:"question"`
+	Attempts      int                `json:"attempts"`
+	Correct       int                `json:"correct"`
+	Omitted       int                `json:"omitted"`
+	PValue        float64            `json:"pValue"`        // Proportion of attempts answered correctly
+	PointBiserial *float64           `json:"pointBiserial"` // Correlation with the rest of the session score, nil if undefined
+	Options       []OptionStatistics `json:"options"`
+	Flags         []string           `json:"flags"`
+}
+
+// PracticeModeAdaptive is the session mode of computerized adaptive tests
+const PracticeModeAdaptive = "adaptive"
+
+// Adaptive session statuses
+const (
+	AdaptiveStatusActive   = "active"
+	AdaptiveStatusFinished = "finished"
+)
+
+// Adaptive session stop reasons
+const (
+	AdaptiveStopPrecision     = "precision_reached"
+	AdaptiveStopMaxQuestions  = "max_questions"
+	AdaptiveStopBankExhausted = "bank_exhausted"
+)
+
+// AdaptiveSessionOptions configures when an adaptive session stops
+type AdaptiveSessionOptions struct {
+	MaxQuestions        int     `json:"maxQuestions"`        // Hard limit on questions asked
+	MinQuestions        int     `json:"minQuestions"`        // Questions asked before the precision rule applies
+	TargetStandardError float64 `json:"targetStandardError"` // Stop once the ability standard error is this small
+}
+
+// AdaptiveSessionState is the state of an adaptive session returned after every step
+type AdaptiveSessionState struct {
+	SessionID      string                 `json:"sessionId"`
+	GroupID        string                 `json:"groupId"`
+	Status         string                 `json:"status"`
+	StopReason     string                 `json:"stopReason"`
+	Options        AdaptiveSessionOptions `json:"options"`
+	Answered       int                    `json:"answered"`
+	CorrectCount   int                    `json:"correctCount"`
+	Ability        float64                `json:"ability"`
+	StandardError  float64                `json:"standardError"`
+	ConfidenceLow  float64                `json:"confidenceLow"`  // Lower bound of the 95% confidence interval
+	ConfidenceHigh float64                `json:"confidenceHigh"` // Upper bound of the 95% confidence interval
+	NextQuestionID string                 `json:"nextQuestionId"`
+	NextQuestion   *SessionQuestion       `json:"nextQuestion"` // Nil once the session has finished
+	Records        []QuestionRecord       `json:"records"`
+	StartedAt      string                 `json:"startedAt"`
+	FinishedAt     *string                `json:"finishedAt"`
+}
+
+// ExamBlueprint describes the composition of a mock exam
+type ExamBlueprint struct {
+	ID              string             `json:"id"`
+	Name            string             `json:"name"`
+	Description     string             `json:"description"`
+	TotalQuestions  int                `json:"totalQuestions"`  // 0 uses the sum of the section counts
+	TimeLimit       int                `json:"timeLimit"`       // Seconds, 0 uses the sum of the section limits
+	AvoidRecentDays int                `json:"avoidRecentDays"` // Skip questions answered this recently; 0 uses the default, negative disables
+	Difficulty      map[int]float64    `json:"difficulty"`      // Default difficulty weights for sections without their own
+	Sections        []BlueprintSection `json:"sections"`
+	Breaks          []ExamBreak        `json:"breaks"`       // Scheduled breaks for timed exams
+	PassingScore    float64            `json:"passingScore"` // Percentage needed to pass, 0 uses the default
+	CreatedAt       string             `json:"createdAt"`
+	UpdatedAt       string             `json:"updatedAt"`
+}
+
+// BlueprintSection is a content area of an exam blueprint. Questions are drawn from
+// the tag and/or group; with neither, from the whole bank.
+type BlueprintSection struct {
+	Name       string          `json:"name"`
+	Tag        string          `json:"tag"`
+	GroupID    string          `json:"groupId"`
+	Weight     float64         `json:"weight"`     // Percentage of the total, used when Count is 0
+	Count      int             `json:"count"`      // Exact number of questions
+	TimeLimit  int             `json:"timeLimit"`  // Seconds, 0 for no section limit
+	Difficulty map[int]float64 `json:"difficulty"` // Relative weights per 1-5 difficulty level
+}
+
+// BlueprintShortfall reports a quota the question bank could not fill
+type BlueprintShortfall struct {
+	Section     string `json:"section"`
+	Difficulty  *int   `json:"difficulty"` // Nil when the whole section is short
+	Requested   int    `json:"requested"`
+	Available   int    `json:"available"`
+	Substituted int    `json:"substituted"` // Questions of other difficulties used instead
+}
+
+// GeneratedExamSection holds the questions drawn for a blueprint section
+type GeneratedExamSection struct {
+	Name         string     `json:"name"`
+	TimeLimit    int        `json:"timeLimit"`
+	Requested    int        `json:"requested"`
+	RecentlySeen int        `json:"recentlySeen"` // Recently answered questions used because nothing else was left
+	Questions    []Question `json:"questions"`
+}
+
+// GeneratedExam is a mock exam sampled from a blueprint
+type GeneratedExam struct {
+	BlueprintID    string                 `json:"blueprintId"`
+	Name           string                 `json:"name"`
+	TotalQuestions int                    `json:"totalQuestions"`
+	TimeLimit      int                    `json:"timeLimit"`
+	Sections       []GeneratedExamSection `json:"sections"`
+	Shortfalls     []BlueprintShortfall   `json:"shortfalls"`
+	GeneratedAt    string                 `json:"generatedAt"`
+}
+
+// ExamBreak is a scheduled break after a section of a timed exam
+type ExamBreak struct {
+	AfterSection int `json:"afterSection"` // Zero-based index of the section the break follows
+	Duration     int `json:"duration"`     // Seconds
+}
+
+// PracticeModeTimedExam is the session mode of backend-timed exams
+const PracticeModeTimedExam = "timed_exam"
+
+// Timed exam statuses
+const (
+	TimedExamInProgress = "in_progress"
+	TimedExamOnBreak    = "on_break"
+	TimedExamCompleted  = "completed"
+)
+
+// SectionTiming records when a timed exam section ran and how it ended
+type SectionTiming struct {
+	Name        string `json:"name"`
+	TimeLimit   int    `json:"timeLimit"`
+	StartedAt   string `json:"startedAt"`
+	CompletedAt string `json:"completedAt"`
+	Duration    int    `json:"duration"` // Seconds spent in the section
+	Questions   int    `json:"questions"`
+	Answered    int    `json:"answered"`
+	Correct     int    `json:"correct"`
+	TimedOut    bool   `json:"timedOut"`
+}
+
+// TimedExamSection is a section of a timed exam in progress
+type TimedExamSection struct {
+	Name         string           `json:"name"`
+	TimeLimit    int              `json:"timeLimit"` // Seconds, 0 for no section limit
+	QuestionIDs  []string         `json:"questionIds"`
+	Answers      []QuestionRecord `json:"answers"`
+	StartedAt    *string          `json:"startedAt"`
+	Deadline     *string          `json:"deadline"` // Nil when neither the section nor the exam has a limit
+	CompletedAt  *string          `json:"completedAt"`
+	LastAnswerAt *string          `json:"lastAnswerAt"`
+	Locked       bool             `json:"locked"`
+	TimedOut     bool             `json:"timedOut"`
+}
+
+// TimedExam is the server-side state of a timed exam simulation
+type TimedExam struct {
+	ID               string               `json:"id"`
+	BlueprintID      string               `json:"blueprintId"`
+	Name             string               `json:"name"`
+	Status           string               `json:"status"`
+	TimeLimit        int                  `json:"timeLimit"` // Seconds of answering time, breaks excluded; 0 for no limit
+	CurrentSection   int                  `json:"currentSection"`
+	Sections         []TimedExamSection   `json:"sections"`
+	Breaks           []ExamBreak          `json:"breaks"`
+	BreakEndsAt      *string              `json:"breakEndsAt"`
+	StartedAt        string               `json:"startedAt"`
+	FinishedAt       *string              `json:"finishedAt"`
+	Shortfalls       []BlueprintShortfall `json:"shortfalls"`
+	CurrentQuestions []SessionQuestion    `json:"currentQuestions"` // Questions of the running section without answers, empty otherwise
+}
+
+// Practice session modes served by the session API
+const (
+	PracticeModePractice  = "practice"
+	PracticeModeTest      = "test"
+	PracticeModeFlashcard = "flashcard"
+)
+
+// Flashcard self-grades, from forgotten to effortless recall
+const (
+	FlashcardGradeAgain = "again"
+	FlashcardGradeHard  = "hard"
+	FlashcardGradeGood  = "good"
+	FlashcardGradeEasy  = "easy"
+)
+
+// Session statuses
+const (
+	SessionStatusActive   = "active"
+	SessionStatusFinished = "finished"
+)
+
+// SessionQuestion is a question as served during a session. Answer and Explanation are
+// left empty until they may be shown.
+type SessionQuestion struct {
+	ID          string          `json:"id"`
+	Question    string          `json:"question"`
+	Options     json.RawMessage `json:"options"`
+	Answer      json.RawMessage `json:"answer,omitempty"`
+	Explanation string          `json:"explanation,omitempty"`
+	Tags        json.RawMessage `json:"tags"`
+	ImageURL    string          `json:"imageUrl"`
+	Difficulty  *int            `json:"difficulty"`
+	MultiAnswer bool            `json:"multiAnswer"` // Whether more than one option is correct
+}
+
+// ActiveSession is the server-side state of a practice, test or flashcard session. In
+// test mode answers and explanations are withheld and answers are graded only when it
+// finishes; flashcards reveal them one card at a time on demand.
+type ActiveSession struct {
+	ID           string            `json:"id"`
+	GroupID      string            `json:"groupId"`
+	Mode         string            `json:"mode"`
+	Status       string            `json:"status"`
+	QuestionIDs  []string          `json:"questionIds"`
+	Records      []QuestionRecord  `json:"records"`
+	CorrectCount int               `json:"correctCount"` // 0 while a test session is active
+	Revealed     []string          `json:"revealed"`     // Flashcards whose answer has been revealed
+	StartedAt    string            `json:"startedAt"`
+	FinishedAt   *string           `json:"finishedAt"`
+	Questions    []SessionQuestion `json:"questions"`
+}
+
+// AnswerFeedback is the response to an answer submitted in a session
+type AnswerFeedback struct {
+	QuestionID  string          `json:"questionId"`
+	Revealed    bool            `json:"revealed"` // False in test mode, where the result is shown at the end
+	IsCorrect   bool            `json:"isCorrect"`
+	Answer      json.RawMessage `json:"answer,omitempty"`
+	Explanation string          `json:"explanation,omitempty"`
+}
+
+// Session event types
+const (
+	SessionEventViewed         = "viewed"
+	SessionEventAnswerSelected = "answer_selected"
+	SessionEventAnswerChanged  = "answer_changed"
+	SessionEventMarked         = "marked"
+	SessionEventUnmarked       = "unmarked"
+	SessionEventPaused         = "paused"
+	SessionEventResumed        = "resumed"
+	SessionEventFinished       = "finished"
+)
+
+// SessionEvent is a timestamped step of a session, recorded for replay and analytics
+type SessionEvent struct {
+	ID          int64    `json:"id"`
+	SessionID   string   `json:"sessionId"`
+	QuestionID  string   `json:"questionId"` // Empty for paused, resumed and finished
+	Type        string   `json:"type"`
+	FromAnswer  []string `json:"fromAnswer"`  // Previous answer of answer_changed events
+	ToAnswer    []string `json:"toAnswer"`    // Selected answer of answer events
+	FromCorrect *bool    `json:"fromCorrect"` // Whether the previous answer was correct
+	ToCorrect   *bool    `json:"toCorrect"`   // Whether the selected answer was correct
+	At          string   `json:"at"`
+}
+
+// QuestionTimeline shows how time was spent on a question within a session
+type QuestionTimeline struct {
+	QuestionID          string         `json:"questionId"`
+	Visits              int            `json:"visits"`
+	TimeViewed          float64        `json:"timeViewed"`          // Seconds on screen, pauses excluded
+	TimeToFirstAnswer   *float64       `json:"timeToFirstAnswer"`   // Seconds viewed before the first answer
+	TimeAfterLastAnswer *float64       `json:"timeAfterLastAnswer"` // Seconds viewed after the final answer
+	AnswerChanges       int            `json:"answerChanges"`
+	Events              []SessionEvent `json:"events"`
+}
+
+// AnswerChangeStats summarizes how changing answers worked out
+type AnswerChangeStats struct {
+	Changes      int `json:"changes"`
+	WrongToRight int `json:"wrongToRight"`
+	RightToWrong int `json:"rightToWrong"`
+	WrongToWrong int `json:"wrongToWrong"`
+	NetGain      int `json:"netGain"` // WrongToRight minus RightToWrong
+}
+
+// Confidence levels learners can attach to an answer
+const (
+	ConfidenceGuess  = "guess"
+	ConfidenceUnsure = "unsure"
+	ConfidenceSure   = "sure"
+)
+
+// Wrong question priorities
+const (
+	WrongPriorityNormal           = 0
+	WrongPriorityConfidentlyWrong = 1 // Answered wrong while sure, a likely misconception
+)
+
+// ConfidenceBucket is the accuracy of the answers given with one confidence level
+type ConfidenceBucket struct {
+	Confidence string  `json:"confidence"`
+	Attempts   int     `json:"attempts"`
+	Correct    int     `json:"correct"`
+	Accuracy   float64 `json:"accuracy"`
+}
+
+// ConfidentlyWrongItem is a question answered wrong while sure
+type ConfidentlyWrongItem struct {
+	QuestionID     string          `json:"questionId"`
+	Question       string          `json:"question"`
+	Tags           json.RawMessage `json:"tags"`
+	Count          int             `json:"count"` // Times answered wrong while sure
+	LastAnsweredAt string          `json:"lastAnsweredAt"`
+}
+
+// ConfidenceTrendPoint summarizes confidence calibration over one week
+type ConfidenceTrendPoint struct {
+	WeekStart      string  `json:"weekStart"` // Monday of the week, YYYY-MM-DD
+	RatedAttempts  int     `json:"ratedAttempts"`
+	SureAttempts   int     `json:"sureAttempts"`
+	SureAccuracy   float64 `json:"sureAccuracy"`
+	Overconfidence float64 `json:"overconfidence"` // Share of sure answers that were wrong
+}
+
+// ConfidenceReport relates the confidence learners stated to how they actually did
+type ConfidenceReport struct {
+	RatedAttempts    int                    `json:"ratedAttempts"`
+	Buckets          []ConfidenceBucket     `json:"buckets"`
+	ConfidentlyWrong []ConfidentlyWrongItem `json:"confidentlyWrong"`
+	Trend            []ConfidenceTrendPoint `json:"trend"`
+}
+
+// TemplateVariable is a variable of a question template, drawn either from a numeric
+// range or from a list of values
+type TemplateVariable struct {
+	Name   string   `json:"name"`
+	Min    float64  `json:"min"`
+	Max    float64  `json:"max"`
+	Step   float64  `json:"step"`   // Range step, 0 for 1
+	Values []string `json:"values"` // List of values; when set the range is ignored
+}
+
+// QuestionTemplate generates question variants. The stem and explanation refer to
+// variables and the answer as {{name}} and {{answer}}; the answer and distractors are
+// expressions over the numeric variables.
+type QuestionTemplate struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	GroupID     string             `json:"groupId"` // Group whose sessions include the template
+	Stem        string             `json:"stem"`
+	Variables   []TemplateVariable `json:"variables"`
+	Answer      string             `json:"answer"`      // Expression computing the correct answer
+	Distractors []string           `json:"distractors"` // Expressions computing wrong options; none for free response
+	Decimals    *int               `json:"decimals"`    // Decimals of rendered numbers, nil for as many as needed
+	Unit        string             `json:"unit"`        // Appended to rendered answers and options
+	Explanation string             `json:"explanation"`
+	Tags        json.RawMessage    `json:"tags"`
+	Difficulty  *int               `json:"difficulty"`
+	Version     int                `json:"version"` // Incremented on update so earlier variants keep their content
+	CreatedAt   string             `json:"createdAt"`
+	UpdatedAt   string             `json:"updatedAt"`
+}
+
+// TemplateAnalytics rolls up the attempts of all variants of a template
+type TemplateAnalytics struct {
+	TemplateID       string  `json:"templateId"`
+	Name             string  `json:"name"`
+	Attempts         int     `json:"attempts"`
+	Correct          int     `json:"correct"`
+	Accuracy         float64 `json:"accuracy"`
+	Variants         int     `json:"variants"` // Distinct variants answered
+	AverageTimeSpent float64 `json:"averageTimeSpent"`
+}
+
+// Actions taken when a question becomes a leech
+const (
+	LeechActionFlag    = "flag"
+	LeechActionSuspend = "suspend"
+)
+
+// Suspension reasons
+const (
+	SuspensionManual = "manual"
+	SuspensionLeech  = "leech"
+)
+
+// QuestionSuspension keeps a question out of practice assembly
+type QuestionSuspension struct {
+	QuestionID  string `json:"questionId"`
+	Reason      string `json:"reason"`
+	SuspendedAt string `json:"suspendedAt"`
+}
+
+// LeechFailure is a wrong answer to a leech in a session
+type LeechFailure struct {
+	SessionID  string   `json:"sessionId"`
+	Mode       string   `json:"mode"`
+	AnsweredAt string   `json:"answeredAt"`
+	UserAnswer []string `json:"userAnswer"`
+}
+
+// Leech is a question that keeps being failed despite repeated review
+type Leech struct {
+	Question      Question       `json:"question"`
+	WrongQuestion WrongQuestion  `json:"wrongQuestion"`
+	Suspended     bool           `json:"suspended"`
+	Attempts      int            `json:"attempts"`
+	Correct       int            `json:"correct"`
+	Failures      []LeechFailure `json:"failures"` // Most recent first
+}
+
+// Study recommendation kinds
+const (
+	RecommendationDueReviews  = "due_reviews"
+	RecommendationWeakTopic   = "weak_topic"
+	RecommendationStaleTopic  = "stale_topic"
+	RecommendationNewMaterial = "new_material"
+)
+
+// StudyRecommendation is a concrete study action that can be started as a session
+type StudyRecommendation struct {
+	ID               string   `json:"id"`
+	Kind             string   `json:"kind"`
+	Title            string   `json:"title"`
+	Reason           string   `json:"reason"`
+	Topic            string   `json:"topic,omitempty"`
+	Mode             string   `json:"mode"`
+	QuestionIDs      []string `json:"questionIds"`
+	EstimatedMinutes int      `json:"estimatedMinutes"`
+	Score            float64  `json:"score"` // Higher is more urgent
+}
+
+// Study plan statuses
+const (
+	StudyPlanActive    = "active"
+	StudyPlanCompleted = "completed" // The exam date has passed
+)
+
+// Study plan pace
+const (
+	StudyPaceAhead   = "ahead"
+	StudyPaceOnTrack = "on_track"
+	StudyPaceBehind  = "behind"
+)
+
+// StudyPlanOptions configures a new study plan
+type StudyPlanOptions struct {
+	Name          string   `json:"name"`
+	ExamDate      string   `json:"examDate"`      // YYYY-MM-DD
+	GroupIDs      []string `json:"groupIds"`      // Empty for the whole bank
+	MinutesPerDay int      `json:"minutesPerDay"` // Zero derives the time from the studyGoal setting
+}
+
+// StudyPlanDay is the planned and completed work of a single day
+type StudyPlanDay struct {
+	Date               string   `json:"date"` // YYYY-MM-DD
+	NewQuestionIDs     []string `json:"newQuestionIds"`
+	ReviewQuestions    int      `json:"reviewQuestions"`
+	PlannedQuestions   int      `json:"plannedQuestions"`
+	PlannedMinutes     int      `json:"plannedMinutes"`
+	CompletedQuestions int      `json:"completedQuestions"`
+	CompletedMinutes   int      `json:"completedMinutes"`
+}
+
+// StudyPlan is a day-by-day schedule leading up to an exam
+type StudyPlan struct {
+	ID                 string         `json:"id"`
+	Name               string         `json:"name"`
+	Status             string         `json:"status"`
+	ExamDate           string         `json:"examDate"`
+	GroupIDs           []string       `json:"groupIds"`
+	MinutesPerDay      int            `json:"minutesPerDay"`
+	SecondsPerQuestion float64        `json:"secondsPerQuestion"`
+	Days               []StudyPlanDay `json:"days"`
+	Unscheduled        int            `json:"unscheduled"`     // New questions that do not fit before the exam
+	PlannedToDate      int            `json:"plannedToDate"`   // Questions planned before today
+	CompletedToDate    int            `json:"completedToDate"` // Questions answered up to and including today
+	Pace               string         `json:"pace"`
+	CreatedAt          string         `json:"createdAt"`
+	UpdatedAt          string         `json:"updatedAt"`
+}
+
+// Time series granularities
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
+// TimeSeriesQuery selects and buckets answers for learning analytics
+type TimeSeriesQuery struct {
+	Granularity   string `json:"granularity"` // day, week or month; defaults to day
+	From          string `json:"from"`        // YYYY-MM-DD, inclusive; defaults to the first answer
+	To            string `json:"to"`          // YYYY-MM-DD, inclusive; defaults to today
+	GroupID       string `json:"groupId"`
+	Tag           string `json:"tag"` // Includes nested tags
+	Mode          string `json:"mode"`
+	MovingAverage int    `json:"movingAverage"` // Window in periods; defaults by granularity
+}
+
+// TimeSeriesPoint aggregates the answers of one period
+type TimeSeriesPoint struct {
+	Period              string  `json:"period"` // First day of the period, YYYY-MM-DD
+	Sessions            int     `json:"sessions"`
+	Questions           int     `json:"questions"`
+	Correct             int     `json:"correct"`
+	Accuracy            float64 `json:"accuracy"` // Percentage, 0 without answers
+	StudyMinutes        float64 `json:"studyMinutes"`
+	AccuracyAverage     float64 `json:"accuracyAverage"` // Moving averages over the trailing window
+	QuestionsAverage    float64 `json:"questionsAverage"`
+	StudyMinutesAverage float64 `json:"studyMinutesAverage"`
+}
+
+// TimeSeries is a gap-free series of periods
+type TimeSeries struct {
+	Granularity   string            `json:"granularity"`
+	MovingAverage int               `json:"movingAverage"`
+	Points        []TimeSeriesPoint `json:"points"`
+}
+
+// HeatmapDay is the activity of a single day
+type HeatmapDay struct {
+	Date         string  `json:"date"`
+	Questions    int     `json:"questions"`
+	StudyMinutes float64 `json:"studyMinutes"`
+	Level        int     `json:"level"` // 0 for no activity up to 4 for the busiest days
+}
+
+// Goal periods
+const (
+	GoalPeriodDaily  = "daily"
+	GoalPeriodWeekly = "weekly"
+)
+
+// Goal metrics
+const (
+	GoalMetricQuestions = "questions"
+	GoalMetricMinutes   = "minutes"
+	GoalMetricAccuracy  = "accuracy" // Percentage of correct answers
+)
+
+// Goal is a daily or weekly study target
+type Goal struct {
+	ID     string  `json:"id"`
+	Period string  `json:"period"`
+	Metric string  `json:"metric"`
+	Target float64 `json:"target"`
+}
+
+// GoalStatus is the progress towards a goal in the current day or week
+type GoalStatus struct {
+	Goal     Goal    `json:"goal"`
+	Current  float64 `json:"current"`
+	Progress float64 `json:"progress"` // Percentage of the target, capped at 100
+	Met      bool    `json:"met"`
+}
+
+// GoalProgress summarizes goals and streaks
+type GoalProgress struct {
+	Goals         []GoalStatus `json:"goals"`
+	TodayComplete bool         `json:"todayComplete"` // Every daily goal is met
+	WeekComplete  bool         `json:"weekComplete"`  // Every weekly goal is met
+	CurrentStreak int          `json:"currentStreak"` // Days in a row meeting the daily goals
+	LongestStreak int          `json:"longestStreak"`
+	GraceDays     int          `json:"graceDays"`     // Missed days forgiven per week
+	GraceDaysLeft int          `json:"graceDaysLeft"` // Forgivable days left this week
+}
+
+// Achievement is a milestone unlocked by studying
+type Achievement struct {
+	ID          string  `json:"id"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Unlocked    bool    `json:"unlocked"`
+	UnlockedAt  *string `json:"unlockedAt"`
+}
+
+// ReadinessArea is the predicted performance in one blueprint section
+type ReadinessArea struct {
+	Name          string  `json:"name"`
+	Weight        float64 `json:"weight"` // Share of the exam, 0-1
+	Questions     int     `json:"questions"`
+	Attempted     int     `json:"attempted"`
+	Coverage      float64 `json:"coverage"` // Percentage of the section's questions ever answered
+	Accuracy      float64 `json:"accuracy"` // Recency-weighted percentage
+	Predicted     float64 `json:"predicted"`
+	Gap           float64 `json:"gap"` // Exam percentage points lost against the passing score
+	LastPracticed *string `json:"lastPracticed"`
+}
+
+// ReadinessReport predicts the score on a blueprint's exam
+type ReadinessReport struct {
+	BlueprintID     string          `json:"blueprintId"`
+	PassingScore    float64         `json:"passingScore"`
+	PredictedScore  float64         `json:"predictedScore"`
+	Lower           float64         `json:"lower"` // 95% band
+	Upper           float64         `json:"upper"`
+	PassProbability float64         `json:"passProbability"` // 0-1
+	PracticeScore   float64         `json:"practiceScore"`   // Prediction from practice alone
+	MockScore       *float64        `json:"mockScore"`       // Recency-weighted mock exam score, nil without mock exams
+	MockExams       int             `json:"mockExams"`
+	Coverage        float64         `json:"coverage"`
+	Areas           []ReadinessArea `json:"areas"` // Biggest gap first
+	ComputedAt      string          `json:"computedAt"`
+}
+
+// Pacing statuses
+const (
+	PaceAhead  = "ahead"
+	PaceOnPace = "on_pace"
+	PaceBehind = "behind"
+)
+
+// TimingGroup summarizes answer times for one tag or difficulty level
+type TimingGroup struct {
+	Key            string  `json:"key"`
+	Answers        int     `json:"answers"`
+	AverageSeconds float64 `json:"averageSeconds"`
+	MedianSeconds  float64 `json:"medianSeconds"`
+	Accuracy       float64 `json:"accuracy"`
+}
+
+// TimingBucket is the accuracy of answers given within a time range
+type TimingBucket struct {
+	MinSeconds int     `json:"minSeconds"`
+	MaxSeconds int     `json:"maxSeconds"` // 0 for the open-ended last bucket
+	Answers    int     `json:"answers"`
+	Accuracy   float64 `json:"accuracy"`
+}
+
+// TimingItem is a question with answers flagged as rushed or slow
+type TimingItem struct {
+	QuestionID     string  `json:"questionId"`
+	Question       string  `json:"question"`
+	Count          int     `json:"count"` // Flagged answers
+	Answers        int     `json:"answers"`
+	AverageSeconds float64 `json:"averageSeconds"` // Of the flagged answers
+}
+
+// TimingReport analyzes the time spent per question
+type TimingReport struct {
+	Answers         int            `json:"answers"`
+	AverageSeconds  float64        `json:"averageSeconds"`
+	MedianSeconds   float64        `json:"medianSeconds"`
+	BudgetSeconds   float64        `json:"budgetSeconds"` // Exam-day time per question
+	PaceRatio       float64        `json:"paceRatio"`     // Average time over the budget
+	OverBudget      int            `json:"overBudget"`
+	OverBudgetShare float64        `json:"overBudgetShare"` // Percentage of answers over the budget
+	ByTag           []TimingGroup  `json:"byTag"`
+	ByDifficulty    []TimingGroup  `json:"byDifficulty"`
+	AccuracyByTime  []TimingBucket `json:"accuracyByTime"`
+	RushedSeconds   float64        `json:"rushedSeconds"` // Wrong answers faster than this are rushed
+	SlowSeconds     float64        `json:"slowSeconds"`   // Correct answers slower than this are slow
+	RushedWrong     []TimingItem   `json:"rushedWrong"`
+	SlowCorrect     []TimingItem   `json:"slowCorrect"`
+}
+
+// SessionPacing is the live pacing signal of a running session
+type SessionPacing struct {
+	SessionID                   string  `json:"sessionId"`
+	Answered                    int     `json:"answered"`
+	Remaining                   int     `json:"remaining"`
+	ElapsedSeconds              float64 `json:"elapsedSeconds"` // Pauses excluded
+	BudgetSeconds               float64 `json:"budgetSeconds"`  // Per question
+	SecondsAhead                float64 `json:"secondsAhead"`   // Negative when behind
+	ProjectedSeconds            float64 `json:"projectedSeconds"`
+	TotalBudgetSeconds          float64 `json:"totalBudgetSeconds"`
+	SuggestedSecondsPerQuestion float64 `json:"suggestedSecondsPerQuestion"` // To finish within the budget
+	Status                      string  `json:"status"`
+}
+
+// QuestionExposure tracks how often a question has been answered and how recently
+type QuestionExposure struct {
+	QuestionID    string `json:"questionId"`
+	Exposures     int    `json:"exposures"`
+	CorrectStreak int    `json:"correctStreak"` // Correct answers in a row up to the last one
+	Mastered      bool   `json:"mastered"`
+	FirstSeenAt   string `json:"firstSeenAt"`
+	LastSeenAt    string `json:"lastSeenAt"`
+}
+
+// CoverageStats is how much of a set of questions has been seen and mastered
+type CoverageStats struct {
+	Key              string  `json:"key"` // Group ID or tag
+	Name             string  `json:"name"`
+	Total            int     `json:"total"`
+	Seen             int     `json:"seen"`
+	Mastered         int     `json:"mastered"`
+	NeverSeen        int     `json:"neverSeen"`
+	SeenPercent      float64 `json:"seenPercent"`
+	MasteredPercent  float64 `json:"masteredPercent"`
+	NeverSeenPercent float64 `json:"neverSeenPercent"`
+	Exposures        int     `json:"exposures"` // Total answers to the questions
+}
+
+// CoverageReport is the coverage of the whole bank, its groups and its tags
+type CoverageReport struct {
+	Overall CoverageStats   `json:"overall"`
+	Groups  []CoverageStats `json:"groups"`
+	Tags    []CoverageStats `json:"tags"`
+}
+
+// TopicRetention is the estimated forgetting curve of a topic: recall starts at
+// InitialRecall right after practice and halves every HalfLifeDays
+type TopicRetention struct {
+	Topic             string  `json:"topic"`
+	Questions         int     `json:"questions"` // Questions answered at least once
+	Attempts          int     `json:"attempts"`
+	Accuracy          float64 `json:"accuracy"`
+	Reviews           int     `json:"reviews"` // Answers to questions answered before, which the curve is fitted to
+	Fitted            bool    `json:"fitted"`  // False when there were too few reviews and defaults are used
+	InitialRecall     float64 `json:"initialRecall"`
+	HalfLifeDays      float64 `json:"halfLifeDays"`
+	PredictedRecall   float64 `json:"predictedRecall"` // Percentage expected to be answered correctly now
+	DaysSincePractice float64 `json:"daysSincePractice"`
+	Stale             bool    `json:"stale"` // Predicted recall has fallen well below the accuracy
+}
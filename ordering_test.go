@@ -0,0 +1,110 @@
+package main
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+// questionTexts returns the question text of each question in order
+func questionTexts(questions []Question) []string {
+	texts := make([]string, len(questions))
+	for i, q := range questions {
+		texts[i] = q.Question
+	}
+	return texts
+}
+
+// TestPerGroupQuestionOrdering tests that groups sharing a question keep independent orders
+func TestPerGroupQuestionOrdering(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	first := createTestGroup(t, app, "First", "")
+	second := createTestGroup(t, app, "Second", "")
+
+	data := []map[string]interface{}{
+		{"question": "Q1", "options": []string{"a"}, "answer": []string{"a"}, "index": float64(1)},
+		{"question": "Q2", "options": []string{"b"}, "answer": []string{"a"}, "index": float64(2)},
+		{"question": "Q3", "options": []string{"c"}, "answer": []string{"a"}, "index": float64(3)},
+	}
+	app.ImportQuestions(data, first.ID)
+	questions, _ := app.GetQuestionsByGroup(first.ID)
+	for _, q := range questions {
+		db.AddQuestionToGroup(second.ID, q.ID)
+	}
+
+	if err := app.ReorderGroupQuestions(first.ID, []string{questions[2].ID, questions[0].ID}); err != nil {
+		t.Fatalf("Failed to reorder group: %v", err)
+	}
+	reordered, _ := app.GetQuestionsByGroup(first.ID)
+	if got := questionTexts(reordered); got[0] != "Q3" || got[1] != "Q1" || got[2] != "Q2" {
+		t.Errorf("Expected [Q3 Q1 Q2], got %v", got)
+	}
+
+	// The other group keeps its own order
+	untouched, _ := app.GetQuestionsByGroup(second.ID)
+	if got := questionTexts(untouched); got[0] != "Q1" || got[1] != "Q2" || got[2] != "Q3" {
+		t.Errorf("Expected second group unchanged [Q1 Q2 Q3], got %v", got)
+	}
+
+	if err := app.MoveQuestionInGroup(second.ID, questions[0].ID, -1); err != nil {
+		t.Fatalf("Failed to move question: %v", err)
+	}
+	moved, _ := app.GetQuestionsByGroup(second.ID)
+	if got := questionTexts(moved); got[0] != "Q2" || got[1] != "Q3" || got[2] != "Q1" {
+		t.Errorf("Expected [Q2 Q3 Q1], got %v", got)
+	}
+
+	groups, _ := app.GetQuestionGroups()
+	for _, g := range groups {
+		if g.ID == first.ID && g.QuestionIds[0] != questions[2].ID {
+			t.Errorf("Expected QuestionIds to follow the group order, got %v", g.QuestionIds)
+		}
+	}
+
+	if err := app.ReorderGroupQuestions(first.ID, []string{"missing"}); err == nil {
+		t.Error("Expected an error for a question outside the group")
+	}
+}
+
+// TestRelationPositionMigration tests that positions are seeded from the global index
+func TestRelationPositionMigration(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "position_migration.db")
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	oldSchema := []string{
+		`CREATE TABLE questions (
+			id TEXT PRIMARY KEY, question TEXT NOT NULL, options JSON NOT NULL, answer JSON NOT NULL,
+			explanation TEXT, tags JSON, image_url TEXT, difficulty INTEGER, source TEXT, [index] INTEGER,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP, updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE question_group_relations (group_id TEXT, question_id TEXT, PRIMARY KEY (group_id, question_id))`,
+		`INSERT INTO questions (id, question, options, answer, [index]) VALUES ('q-a', 'A', '[]', '[]', 2), ('q-b', 'B', '[]', '[]', 1)`,
+		`INSERT INTO question_group_relations (group_id, question_id) VALUES ('g1', 'q-a'), ('g1', 'q-b')`,
+	}
+	for _, query := range oldSchema {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatalf("Failed to create old schema: %v", err)
+		}
+	}
+
+	database := &Database{db: db}
+	if err := database.migrate(); err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+
+	var position int
+	if err := db.QueryRow(`SELECT position FROM question_group_relations WHERE question_id = 'q-b'`).Scan(&position); err != nil {
+		t.Fatalf("Failed to read position: %v", err)
+	}
+	if position != 0 {
+		t.Errorf("Expected q-b (index 1) at position 0, got %d", position)
+	}
+}
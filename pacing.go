@@ -0,0 +1,318 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Pacing parameters
+const (
+	defaultTimePerQuestion = 60 // Seconds, when the timePerQuestion setting is missing
+	minimumRushedSeconds   = 5
+	timingItemLimit        = 20
+)
+
+// timingBucketBounds are the upper bounds in seconds of the accuracy-by-time buckets
+var timingBucketBounds = []int{15, 30, 60, 90, 120, 180}
+
+// medianSeconds returns the median of a list of times
+func medianSeconds(values []int) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return float64(sorted[middle-1]+sorted[middle]) / 2
+	}
+	return float64(sorted[middle])
+}
+
+// timingGroup summarizes a set of timed answers
+func timingGroup(key string, attempts []questionAttempt) TimingGroup {
+	group := TimingGroup{Key: key, Answers: len(attempts)}
+	times := make([]int, 0, len(attempts))
+	total, correct := 0, 0
+	for _, attempt := range attempts {
+		times = append(times, attempt.TimeSpent)
+		total += attempt.TimeSpent
+		if attempt.IsCorrect {
+			correct++
+		}
+	}
+	if len(attempts) > 0 {
+		group.AverageSeconds = float64(total) / float64(len(attempts))
+		group.Accuracy = float64(correct) / float64(len(attempts)) * 100
+	}
+	group.MedianSeconds = medianSeconds(times)
+	return group
+}
+
+// timingItems groups flagged answers by question, most flagged first
+func timingItems(flagged []questionAttempt, answers map[string]int, questions map[string]Question) []TimingItem {
+	byQuestion := make(map[string]*TimingItem)
+	var order []string
+	for _, attempt := range flagged {
+		item := byQuestion[attempt.QuestionID]
+		if item == nil {
+			item = &TimingItem{QuestionID: attempt.QuestionID, Question: questions[attempt.QuestionID].Question, Answers: answers[attempt.QuestionID]}
+			byQuestion[attempt.QuestionID] = item
+			order = append(order, attempt.QuestionID)
+		}
+		item.AverageSeconds = (item.AverageSeconds*float64(item.Count) + float64(attempt.TimeSpent)) / float64(item.Count+1)
+		item.Count++
+	}
+	items := make([]TimingItem, 0, len(order))
+	for _, id := range order {
+		items = append(items, *byQuestion[id])
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Count > items[j].Count
+	})
+	if len(items) > timingItemLimit {
+		items = items[:timingItemLimit]
+	}
+	return items
+}
+
+// pausedSeconds returns the time a session spent paused up to now
+func pausedSeconds(events []SessionEvent, now time.Time) float64 {
+	paused := 0.0
+	var since time.Time
+	for _, event := range events {
+		at, err := time.Parse(time.RFC3339Nano, event.At)
+		if err != nil {
+			continue
+		}
+		switch event.Type {
+		case SessionEventPaused:
+			if since.IsZero() {
+				since = at
+			}
+		case SessionEventResumed:
+			if !since.IsZero() {
+				paused += at.Sub(since).Seconds()
+				since = time.Time{}
+			}
+		}
+	}
+	if !since.IsZero() {
+		paused += now.Sub(since).Seconds()
+	}
+	return paused
+}
+
+// Pacing methods
+
+// timeBudget returns the exam-day seconds per question: the blueprint's time limit
+// spread over its questions, or the timePerQuestion setting
+func (a *App) timeBudget(blueprintID string) (float64, error) {
+	if blueprintID != "" {
+		blueprint, err := a.db.GetExamBlueprint(blueprintID)
+		if err != nil {
+			return 0, err
+		}
+		limit := blueprint.TimeLimit
+		if limit == 0 {
+			for _, section := range blueprint.Sections {
+				limit += section.TimeLimit
+			}
+		}
+		total := 0
+		for _, count := range sectionCounts(*blueprint) {
+			total += count
+		}
+		if limit > 0 && total > 0 {
+			return float64(limit) / float64(total), nil
+		}
+	}
+
+	budget := float64(defaultTimePerQuestion)
+	if value, err := a.db.GetSetting("timePerQuestion"); err == nil {
+		var configured float64
+		if json.Unmarshal(value, &configured) == nil && configured > 0 {
+			budget = configured
+		}
+	}
+	return budget, nil
+}
+
+// GetTimingReport analyzes the time spent on answers: averages by tag and difficulty,
+// accuracy against time, rushed wrong answers and slow correct ones, and pacing against
+// the exam-day budget of a blueprint, or the timePerQuestion setting without one.
+// Answers are rushed when faster than a third of the median time, and slow when
+// slower than both the budget and twice the median.
+func (a *App) GetTimingReport(blueprintID string) (*TimingReport, error) {
+	budget, err := a.timeBudget(blueprintID)
+	if err != nil {
+		return nil, err
+	}
+	allAttempts, err := a.db.GetQuestionAttempts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question attempts: %v", err)
+	}
+	questionList, err := a.db.GetQuestions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questions: %v", err)
+	}
+	questions := make(map[string]Question, len(questionList))
+	for _, q := range questionList {
+		questions[q.ID] = q
+	}
+
+	var attempts []questionAttempt
+	answers := make(map[string]int)
+	for _, attempt := range allAttempts {
+		if attempt.TimeSpent <= 0 {
+			continue
+		}
+		attempts = append(attempts, attempt)
+		answers[attempt.QuestionID]++
+	}
+
+	overall := timingGroup("", attempts)
+	report := &TimingReport{
+		Answers:        overall.Answers,
+		AverageSeconds: overall.AverageSeconds,
+		MedianSeconds:  overall.MedianSeconds,
+		BudgetSeconds:  budget,
+		PaceRatio:      overall.AverageSeconds / budget,
+		ByTag:          []TimingGroup{},
+		ByDifficulty:   []TimingGroup{},
+		AccuracyByTime: []TimingBucket{},
+		RushedSeconds:  math.Max(minimumRushedSeconds, overall.MedianSeconds/3),
+		SlowSeconds:    math.Max(budget, overall.MedianSeconds*2),
+	}
+
+	byTag := make(map[string][]questionAttempt)
+	byDifficulty := make(map[int][]questionAttempt)
+	buckets := make([]struct{ answers, correct int }, len(timingBucketBounds)+1)
+	var rushed, slow []questionAttempt
+	for _, attempt := range attempts {
+		if float64(attempt.TimeSpent) > budget {
+			report.OverBudget++
+		}
+		if q, ok := questions[attempt.QuestionID]; ok {
+			for _, topic := range questionTopics(q) {
+				byTag[topic] = append(byTag[topic], attempt)
+			}
+			if q.Difficulty != nil {
+				byDifficulty[*q.Difficulty] = append(byDifficulty[*q.Difficulty], attempt)
+			}
+		}
+
+		bucket := sort.SearchInts(timingBucketBounds, attempt.TimeSpent)
+		if bucket < len(timingBucketBounds) && timingBucketBounds[bucket] == attempt.TimeSpent {
+			bucket++
+		}
+		buckets[bucket].answers++
+		if attempt.IsCorrect {
+			buckets[bucket].correct++
+		}
+
+		seconds := float64(attempt.TimeSpent)
+		if !attempt.IsCorrect && seconds < report.RushedSeconds {
+			rushed = append(rushed, attempt)
+		}
+		if attempt.IsCorrect && seconds > report.SlowSeconds {
+			slow = append(slow, attempt)
+		}
+	}
+	if report.Answers > 0 {
+		report.OverBudgetShare = float64(report.OverBudget) / float64(report.Answers) * 100
+	}
+
+	for tag, tagAttempts := range byTag {
+		report.ByTag = append(report.ByTag, timingGroup(tag, tagAttempts))
+	}
+	sort.Slice(report.ByTag, func(i, j int) bool {
+		if report.ByTag[i].AverageSeconds != report.ByTag[j].AverageSeconds {
+			return report.ByTag[i].AverageSeconds > report.ByTag[j].AverageSeconds
+		}
+		return report.ByTag[i].Key < report.ByTag[j].Key
+	})
+	for level := 1; level <= 5; level++ {
+		if levelAttempts, ok := byDifficulty[level]; ok {
+			report.ByDifficulty = append(report.ByDifficulty, timingGroup(strconv.Itoa(level), levelAttempts))
+		}
+	}
+
+	lower := 0
+	for i, bucket := range buckets {
+		entry := TimingBucket{MinSeconds: lower, Answers: bucket.answers}
+		if i < len(timingBucketBounds) {
+			entry.MaxSeconds = timingBucketBounds[i]
+			lower = timingBucketBounds[i]
+		}
+		if bucket.answers > 0 {
+			entry.Accuracy = float64(bucket.correct) / float64(bucket.answers) * 100
+		}
+		report.AccuracyByTime = append(report.AccuracyByTime, entry)
+	}
+
+	report.RushedWrong = timingItems(rushed, answers, questions)
+	report.SlowCorrect = timingItems(slow, answers, questions)
+	return report, nil
+}
+
+// GetSessionPacing returns the pacing of a running session against the timePerQuestion
+// budget: the time spent so far with pauses excluded, how far ahead or behind that is,
+// the projected total and the time per question left to finish within the budget.
+// Being within one question's budget of the expected time counts as on pace.
+func (a *App) GetSessionPacing(sessionID string) (*SessionPacing, error) {
+	session, err := a.db.GetActiveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := a.db.GetSessionEvents(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session events: %v", err)
+	}
+	budget, err := a.timeBudget("")
+	if err != nil {
+		return nil, err
+	}
+
+	now := a.now()
+	if session.FinishedAt != nil {
+		if finished, err := time.Parse(time.RFC3339, *session.FinishedAt); err == nil {
+			now = finished
+		}
+	}
+	elapsed := 0.0
+	if started, err := time.Parse(time.RFC3339, session.StartedAt); err == nil {
+		elapsed = math.Max(0, now.Sub(started).Seconds()-pausedSeconds(events, now))
+	}
+
+	pacing := &SessionPacing{
+		SessionID:          sessionID,
+		Answered:           len(session.Records),
+		Remaining:          len(session.QuestionIDs) - len(session.Records),
+		ElapsedSeconds:     elapsed,
+		BudgetSeconds:      budget,
+		TotalBudgetSeconds: budget * float64(len(session.QuestionIDs)),
+	}
+	pacing.SecondsAhead = float64(pacing.Answered)*budget - elapsed
+	perQuestion := budget
+	if pacing.Answered > 0 {
+		perQuestion = elapsed / float64(pacing.Answered)
+	}
+	pacing.ProjectedSeconds = elapsed + perQuestion*float64(pacing.Remaining)
+	if pacing.Remaining > 0 {
+		pacing.SuggestedSecondsPerQuestion = math.Max(0, pacing.TotalBudgetSeconds-elapsed) / float64(pacing.Remaining)
+	}
+	switch {
+	case pacing.SecondsAhead < -budget:
+		pacing.Status = PaceBehind
+	case pacing.SecondsAhead > budget:
+		pacing.Status = PaceAhead
+	default:
+		pacing.Status = PaceOnPace
+	}
+	return pacing, nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestTimingReport tests time statistics, accuracy buckets, rushed and slow answers
+// and the blueprint time budget
+func TestTimingReport(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	data := []map[string]interface{}{
+		{"question": "Q1", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Cardiology"}, "difficulty": float64(2)},
+		{"question": "Q2", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Renal"}, "difficulty": float64(4)},
+	}
+	app.ImportQuestions(data, "")
+	questions, _ := app.GetQuestions()
+	ids := make(map[string]string)
+	for _, q := range questions {
+		ids[q.Question] = q.ID
+	}
+
+	records := []QuestionRecord{
+		{QuestionID: ids["Q1"], IsCorrect: false, TimeSpent: 2},
+		{QuestionID: ids["Q1"], IsCorrect: true, TimeSpent: 20},
+		{QuestionID: ids["Q1"], IsCorrect: true, TimeSpent: 30},
+		{QuestionID: ids["Q2"], IsCorrect: true, TimeSpent: 40},
+		{QuestionID: ids["Q2"], IsCorrect: true, TimeSpent: 200},
+		{QuestionID: ids["Q2"], IsCorrect: false, TimeSpent: 0},
+	}
+	details, _ := json.Marshal(records)
+	stamp := time.Now().Format(time.RFC3339)
+	db.CreatePracticeSession(&PracticeSession{ID: "s1", Mode: "practice", StartTime: stamp, TotalQuestions: len(records), Details: details, CreatedAt: stamp})
+
+	report, err := app.GetTimingReport("")
+	if err != nil {
+		t.Fatalf("Failed to get timing report: %v", err)
+	}
+	// Untimed answers are left out
+	if report.Answers != 5 || report.MedianSeconds != 30 || report.AverageSeconds != 58.4 || report.BudgetSeconds != 60 {
+		t.Errorf("Unexpected overall statistics: %+v", report)
+	}
+	if report.OverBudget != 1 || report.OverBudgetShare != 20 {
+		t.Errorf("Expected one answer over budget, got %+v", report)
+	}
+	if len(report.ByTag) != 2 || report.ByTag[0].Key != "Renal" || report.ByTag[0].AverageSeconds != 120 {
+		t.Errorf("Expected Renal to be the slowest tag, got %+v", report.ByTag)
+	}
+	if len(report.ByDifficulty) != 2 || report.ByDifficulty[0].Key != "2" || report.ByDifficulty[0].Answers != 3 {
+		t.Errorf("Unexpected difficulty groups: %+v", report.ByDifficulty)
+	}
+	if len(report.AccuracyByTime) != 7 || report.AccuracyByTime[0].Accuracy != 0 || report.AccuracyByTime[1].Accuracy != 100 ||
+		report.AccuracyByTime[2].Answers != 2 || report.AccuracyByTime[6].Answers != 1 {
+		t.Errorf("Unexpected accuracy buckets: %+v", report.AccuracyByTime)
+	}
+	if len(report.RushedWrong) != 1 || report.RushedWrong[0].QuestionID != ids["Q1"] || report.RushedWrong[0].Answers != 3 {
+		t.Errorf("Expected the 2s wrong answer to be rushed, got %+v", report.RushedWrong)
+	}
+	if len(report.SlowCorrect) != 1 || report.SlowCorrect[0].QuestionID != ids["Q2"] || report.SlowCorrect[0].AverageSeconds != 200 {
+		t.Errorf("Expected the 200s correct answer to be slow, got %+v", report.SlowCorrect)
+	}
+
+	blueprint, err := app.CreateExamBlueprint(ExamBlueprint{
+		Name:      "Boards",
+		TimeLimit: 200,
+		Sections:  []BlueprintSection{{Name: "All", Count: 2}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create blueprint: %v", err)
+	}
+	if report, _ = app.GetTimingReport(blueprint.ID); report.BudgetSeconds != 100 || report.OverBudget != 1 {
+		t.Errorf("Expected a 100s budget from the blueprint, got %+v", report)
+	}
+}
+
+// TestSessionPacing tests elapsed time without pauses and the pacing status
+func TestSessionPacing(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+	app.SetUserSetting("timePerQuestion", 30)
+
+	data := []map[string]interface{}{
+		{"question": "Q1", "options": []string{"A", "B"}, "answer": []string{"a"}},
+		{"question": "Q2", "options": []string{"A", "B"}, "answer": []string{"a"}},
+		{"question": "Q3", "options": []string{"A", "B"}, "answer": []string{"a"}},
+		{"question": "Q4", "options": []string{"A", "B"}, "answer": []string{"a"}},
+	}
+	app.ImportQuestions(data, "")
+
+	session, err := app.StartSession("", PracticeModeTest, nil)
+	if err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	ids := make(map[string]string)
+	for _, q := range session.Questions {
+		ids[q.Question] = q.ID
+	}
+	start, _ := time.Parse(time.RFC3339, session.StartedAt)
+	now := start
+	app.clock = func() time.Time { return now }
+	at := func(seconds int) { now = start.Add(time.Duration(seconds) * time.Second) }
+
+	at(20)
+	app.SubmitSessionAnswer(session.ID, ids["Q1"], []string{"a"}, 20, "")
+	pacing, err := app.GetSessionPacing(session.ID)
+	if err != nil {
+		t.Fatalf("Failed to get session pacing: %v", err)
+	}
+	if pacing.Answered != 1 || pacing.Remaining != 3 || pacing.ElapsedSeconds != 20 || pacing.SecondsAhead != 10 || pacing.Status != PaceOnPace {
+		t.Errorf("Expected to be slightly ahead, got %+v", pacing)
+	}
+	if pacing.TotalBudgetSeconds != 120 || pacing.ProjectedSeconds != 80 || pacing.SuggestedSecondsPerQuestion != 100.0/3 {
+		t.Errorf("Unexpected projection: %+v", pacing)
+	}
+
+	// A pause does not count against the pace
+	app.RecordSessionEvent(session.ID, SessionEventPaused, "", nil)
+	at(500)
+	if pacing, _ = app.GetSessionPacing(session.ID); pacing.ElapsedSeconds != 20 {
+		t.Errorf("Expected the running pause to be excluded, got %+v", pacing)
+	}
+	app.RecordSessionEvent(session.ID, SessionEventResumed, "", nil)
+	at(580)
+	app.SubmitSessionAnswer(session.ID, ids["Q2"], []string{"a"}, 80, "")
+	if pacing, _ = app.GetSessionPacing(session.ID); pacing.ElapsedSeconds != 100 || pacing.SecondsAhead != -40 || pacing.Status != PaceBehind {
+		t.Errorf("Expected to be behind, got %+v", pacing)
+	}
+	if pacing.SuggestedSecondsPerQuestion != 10 {
+		t.Errorf("Expected 10s per remaining question, got %+v", pacing)
+	}
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Readiness model parameters
+const (
+	readinessHalfLifeDays = 30.0 // Days after which an answer counts half
+	defaultPassingScore   = 70.0 // Percentage, for blueprints without a passing score
+	maxMockExamShare      = 0.6  // Largest share of the prediction mock exams can take
+)
+
+// readinessStat holds the recency-weighted answers to one question as of lastAt
+type readinessStat struct {
+	weight   float64
+	correct  float64
+	attempts int
+	lastAt   time.Time
+}
+
+// readinessDecay returns how much an answer given at from still counts at to
+func readinessDecay(from, to time.Time) float64 {
+	return math.Pow(0.5, to.Sub(from).Hours()/24/readinessHalfLifeDays)
+}
+
+// add folds an answer into the statistic. Newer answers decay the existing weight up
+// to their time; older ones are decayed to the time of the latest answer.
+func (s *readinessStat) add(correct bool, at time.Time) {
+	value := boolToFloat(correct)
+	if s.attempts == 0 || at.After(s.lastAt) {
+		decay := 1.0
+		if s.attempts > 0 {
+			decay = readinessDecay(s.lastAt, at)
+		}
+		s.weight = s.weight*decay + 1
+		s.correct = s.correct*decay + value
+		s.lastAt = at
+	} else {
+		decay := readinessDecay(at, s.lastAt)
+		s.weight += decay
+		s.correct += decay * value
+	}
+	s.attempts++
+}
+
+// normalCDF returns the standard normal cumulative probability
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// Readiness database methods
+
+// GetReadinessStats returns the recency-weighted answer statistics by question
+func (d *Database) GetReadinessStats() (map[string]*readinessStat, error) {
+	rows, err := d.db.Query(`SELECT question_id, weight, correct, attempts, last_at FROM readiness_stats`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make(map[string]*readinessStat)
+	for rows.Next() {
+		var id, lastAt string
+		stat := &readinessStat{}
+		if err := rows.Scan(&id, &stat.weight, &stat.correct, &stat.attempts, &lastAt); err != nil {
+			return nil, err
+		}
+		stat.lastAt, _ = time.Parse(time.RFC3339Nano, lastAt)
+		stats[id] = stat
+	}
+	return stats, rows.Err()
+}
+
+// SaveReadinessStats upserts the statistics of the given questions
+func (d *Database) SaveReadinessStats(stats map[string]*readinessStat) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for id, stat := range stats {
+		_, err := tx.Exec(`INSERT INTO readiness_stats (question_id, weight, correct, attempts, last_at) VALUES (?, ?, ?, ?, ?)
+				  ON CONFLICT(question_id) DO UPDATE SET weight = excluded.weight, correct = excluded.correct,
+				  attempts = excluded.attempts, last_at = excluded.last_at`,
+			id, stat.weight, stat.correct, stat.attempts, stat.lastAt.Format(time.RFC3339Nano))
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetCompletedTimedExams returns the completed timed exams taken from a blueprint
+func (d *Database) GetCompletedTimedExams(blueprintID string) ([]TimedExam, error) {
+	rows, err := d.db.Query(`SELECT state FROM timed_exams WHERE status = ? AND json_extract(state, '$.blueprintId') = ?`,
+		TimedExamCompleted, blueprintID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exams []TimedExam
+	for rows.Next() {
+		var stateJSON string
+		if err := rows.Scan(&stateJSON); err != nil {
+			return nil, err
+		}
+		var exam TimedExam
+		if err := json.Unmarshal([]byte(stateJSON), &exam); err != nil {
+			return nil, fmt.Errorf("failed to parse timed exam: %v", err)
+		}
+		exams = append(exams, exam)
+	}
+	return exams, rows.Err()
+}
+
+// Readiness methods
+
+// readinessCache returns the readiness statistics as a cache kept up to date as
+// sessions are saved
+func (a *App) readinessCache() attemptCache[*readinessStat] {
+	return attemptCache[*readinessStat]{
+		name:  "readiness statistics",
+		table: "readiness_stats",
+		load:  a.db.GetReadinessStats,
+		save:  a.db.SaveReadinessStats,
+		fresh: func() *readinessStat { return &readinessStat{} },
+	}
+}
+
+// recordReadiness folds the answers of a saved session into the readiness statistics
+func (a *App) recordReadiness(session *PracticeSession) {
+	a.readinessCache().record(a, session)
+}
+
+// GetReadiness predicts the score on an exam built from a blueprint. Each section's
+// accuracy is weighted towards recent answers and shrunk towards 50% while there are
+// few of them; questions never answered are expected halfway between that accuracy
+// and 50%. Completed mock exams from the blueprint are blended in, taking a larger
+// share the more recent ones there are. The band and pass probability account for
+// the uncertainty of each estimate and of sampling the exam's questions.
+func (a *App) GetReadiness(blueprintID string) (*ReadinessReport, error) {
+	blueprint, err := a.db.GetExamBlueprint(blueprintID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := a.db.GetReadinessStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load readiness statistics: %v", err)
+	}
+	exams, err := a.db.GetCompletedTimedExams(blueprintID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mock exams: %v", err)
+	}
+
+	now := a.now()
+	passing := blueprint.PassingScore
+	if passing == 0 {
+		passing = defaultPassingScore
+	}
+	counts := sectionCounts(*blueprint)
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	report := &ReadinessReport{
+		BlueprintID:  blueprintID,
+		PassingScore: passing,
+		Areas:        []ReadinessArea{},
+		ComputedAt:   now.Format(time.RFC3339),
+	}
+	practice, practiceVariance := 0.0, 0.0
+	for i, section := range blueprint.Sections {
+		pool, err := a.blueprintSectionPool(section)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get questions for section %s: %v", section.Name, err)
+		}
+		area := ReadinessArea{Name: section.Name, Questions: len(pool)}
+		if total > 0 {
+			area.Weight = float64(counts[i]) / float64(total)
+		} else {
+			area.Weight = 1 / float64(len(blueprint.Sections))
+		}
+
+		weight, correct := 0.0, 0.0
+		var last time.Time
+		for _, q := range pool {
+			stat := stats[q.ID]
+			if stat == nil || stat.attempts == 0 {
+				continue
+			}
+			decay := readinessDecay(stat.lastAt, now)
+			weight += stat.weight * decay
+			correct += stat.correct * decay
+			area.Attempted++
+			if stat.lastAt.After(last) {
+				last = stat.lastAt
+			}
+		}
+		accuracy := (correct + 1) / (weight + 2)
+		coverage := 0.0
+		if len(pool) > 0 {
+			coverage = float64(area.Attempted) / float64(len(pool))
+		}
+		predicted := coverage*accuracy + (1-coverage)*(accuracy+0.5)/2
+		variance := accuracy * (1 - accuracy) / (weight + 3)
+
+		area.Accuracy = accuracy * 100
+		area.Coverage = coverage * 100
+		area.Predicted = predicted * 100
+		area.Gap = area.Weight * math.Max(0, passing-area.Predicted)
+		if !last.IsZero() {
+			lastPracticed := last.Format(time.RFC3339)
+			area.LastPracticed = &lastPracticed
+		}
+		report.Areas = append(report.Areas, area)
+		report.Coverage += area.Weight * area.Coverage
+		practice += area.Weight * predicted
+		practiceVariance += area.Weight * area.Weight * variance
+	}
+	sort.SliceStable(report.Areas, func(i, j int) bool {
+		return report.Areas[i].Gap > report.Areas[j].Gap
+	})
+
+	predicted, variance := practice, practiceVariance
+	mockWeight, mockScore := 0.0, 0.0
+	for _, exam := range exams {
+		questions, correct := 0, 0
+		for _, section := range exam.Sections {
+			questions += len(section.QuestionIDs)
+			for _, answer := range section.Answers {
+				if answer.IsCorrect {
+					correct++
+				}
+			}
+		}
+		if questions == 0 {
+			continue
+		}
+		decay := readinessDecay(parseExamTime(exam.FinishedAt), now)
+		mockWeight += decay
+		mockScore += decay * float64(correct) / float64(questions)
+		report.MockExams++
+	}
+	if mockWeight > 0 {
+		mockScore /= mockWeight
+		score := mockScore * 100
+		report.MockScore = &score
+		share := math.Min(maxMockExamShare, mockWeight/(mockWeight+2))
+		mockVariance := mockScore * (1 - mockScore) / (math.Max(1, float64(total)) * mockWeight)
+		predicted = (1-share)*practice + share*mockScore
+		variance = (1-share)*(1-share)*practiceVariance + share*share*mockVariance
+	}
+	if total > 0 {
+		variance += predicted * (1 - predicted) / float64(total)
+	}
+
+	se := math.Sqrt(variance) * 100
+	report.PracticeScore = practice * 100
+	report.PredictedScore = predicted * 100
+	report.Lower = math.Max(0, report.PredictedScore-1.96*se)
+	report.Upper = math.Min(100, report.PredictedScore+1.96*se)
+	if se > 0 {
+		report.PassProbability = normalCDF((report.PredictedScore - passing) / se)
+	} else if report.PredictedScore >= passing {
+		report.PassProbability = 1
+	}
+	return report, nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+// TestReadinessStatIncremental tests that folding in answers one by one matches the
+// decayed totals regardless of order
+func TestReadinessStatIncremental(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	var forward, shuffled readinessStat
+	forward.add(true, start)
+	forward.add(false, start.AddDate(0, 0, 30))
+	forward.add(true, start.AddDate(0, 0, 60))
+
+	shuffled.add(true, start.AddDate(0, 0, 60))
+	shuffled.add(true, start)
+	shuffled.add(false, start.AddDate(0, 0, 30))
+
+	// At day 60 the answers count 0.25, 0.5 and 1
+	for _, stat := range []readinessStat{forward, shuffled} {
+		if math.Abs(stat.weight-1.75) > 1e-9 || math.Abs(stat.correct-1.25) > 1e-9 || stat.attempts != 3 {
+			t.Errorf("Unexpected statistic: %+v", stat)
+		}
+	}
+}
+
+// TestReadinessReport tests per-area predictions, gaps, incremental updates and mock exams
+func TestReadinessReport(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	now := time.Date(2025, 6, 20, 12, 0, 0, 0, time.UTC)
+	app.clock = func() time.Time { return now }
+
+	data := []map[string]interface{}{
+		{"question": "A1", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Anatomy"}},
+		{"question": "A2", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Anatomy"}},
+		{"question": "B1", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Biochemistry"}},
+		{"question": "B2", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Biochemistry"}},
+	}
+	app.ImportQuestions(data, "")
+	questions, _ := app.GetQuestions()
+	ids := make(map[string]string)
+	for _, q := range questions {
+		ids[q.Question] = q.ID
+	}
+
+	if _, err := app.CreateExamBlueprint(ExamBlueprint{Name: "Bad", PassingScore: 120, Sections: []BlueprintSection{{Name: "All", Count: 1}}}); err == nil {
+		t.Error("Expected a passing score above 100 to be rejected")
+	}
+	blueprint, err := app.CreateExamBlueprint(ExamBlueprint{
+		Name:         "Boards",
+		PassingScore: 60,
+		Sections: []BlueprintSection{
+			{Name: "Anatomy", Tag: "Anatomy", Count: 2},
+			{Name: "Biochemistry", Tag: "Biochemistry", Count: 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create blueprint: %v", err)
+	}
+
+	addSession := func(id string, at time.Time, records []QuestionRecord) {
+		details, _ := json.Marshal(records)
+		stamp := at.Format(time.RFC3339)
+		session := &PracticeSession{ID: id, Mode: "practice", StartTime: stamp, TotalQuestions: len(records), Details: details, CreatedAt: stamp}
+		db.CreatePracticeSession(session)
+		app.recordReadiness(session)
+	}
+	addSession("s1", now.AddDate(0, 0, -10), []QuestionRecord{
+		{QuestionID: ids["A1"], IsCorrect: true},
+		{QuestionID: ids["A2"], IsCorrect: true},
+		{QuestionID: ids["B1"], IsCorrect: false},
+	})
+	addSession("s2", now.AddDate(0, 0, -1), []QuestionRecord{
+		{QuestionID: ids["A1"], IsCorrect: true},
+		{QuestionID: ids["B1"], IsCorrect: false},
+	})
+
+	// The incremental statistics match a rebuild from the whole history
+	incremental, _ := db.GetReadinessStats()
+	db.db.Exec("DELETE FROM readiness_stats")
+	app.recordReadiness(&PracticeSession{})
+	rebuilt, _ := db.GetReadinessStats()
+	for id, stat := range rebuilt {
+		if other := incremental[id]; other == nil || math.Abs(other.weight-stat.weight) > 1e-9 || other.attempts != stat.attempts {
+			t.Errorf("Incremental statistic for %s differs from the rebuild: %+v vs %+v", id, other, stat)
+		}
+	}
+
+	// Flashcard self-grades leave the statistics alone, whether folded in or rebuilt
+	details, _ := json.Marshal([]QuestionRecord{{QuestionID: ids["B1"], IsCorrect: true, Grade: FlashcardGradeGood}})
+	stamp := now.Format(time.RFC3339)
+	flashcards := &PracticeSession{ID: "s3", Mode: PracticeModeFlashcard, StartTime: stamp, TotalQuestions: 1, Details: details, CreatedAt: stamp}
+	db.CreatePracticeSession(flashcards)
+	app.recordReadiness(flashcards)
+	if stats, _ := db.GetReadinessStats(); stats[ids["B1"]].attempts != rebuilt[ids["B1"]].attempts {
+		t.Errorf("Expected flashcard grades to stay out of readiness, got %+v", stats[ids["B1"]])
+	}
+	app.rebuildAttemptCaches()
+	if stats, _ := db.GetReadinessStats(); stats[ids["B1"]].attempts != rebuilt[ids["B1"]].attempts {
+		t.Errorf("Expected the rebuild to leave flashcard grades out, got %+v", stats[ids["B1"]])
+	}
+
+	report, err := app.GetReadiness(blueprint.ID)
+	if err != nil {
+		t.Fatalf("Failed to get readiness: %v", err)
+	}
+	if len(report.Areas) != 2 || report.Areas[0].Name != "Biochemistry" || report.Areas[0].Gap <= 0 || report.Areas[1].Gap != 0 {
+		t.Fatalf("Expected Biochemistry to be the biggest gap, got %+v", report.Areas)
+	}
+	if report.Areas[0].Coverage != 50 || report.Areas[1].Coverage != 100 || report.Coverage != 75 {
+		t.Errorf("Unexpected coverage: %+v", report.Areas)
+	}
+	if report.Lower >= report.PredictedScore || report.Upper <= report.PredictedScore || report.PassProbability <= 0 || report.PassProbability >= 1 {
+		t.Errorf("Expected a band around the prediction, got %+v", report)
+	}
+	if report.MockScore != nil || report.PredictedScore != report.PracticeScore {
+		t.Errorf("Expected no mock exam contribution, got %+v", report)
+	}
+
+	finished := now.AddDate(0, 0, -2).Format(time.RFC3339)
+	db.SaveTimedExam(&TimedExam{
+		ID:          "exam_1",
+		BlueprintID: blueprint.ID,
+		Status:      TimedExamCompleted,
+		FinishedAt:  &finished,
+		Sections: []TimedExamSection{{
+			Name:        "All",
+			QuestionIDs: []string{ids["A1"], ids["A2"], ids["B1"], ids["B2"]},
+			Answers: []QuestionRecord{
+				{QuestionID: ids["A1"], IsCorrect: true}, {QuestionID: ids["A2"], IsCorrect: true},
+				{QuestionID: ids["B1"], IsCorrect: true}, {QuestionID: ids["B2"], IsCorrect: true},
+			},
+		}},
+	}, now)
+	withMock, _ := app.GetReadiness(blueprint.ID)
+	if withMock.MockExams != 1 || withMock.MockScore == nil || *withMock.MockScore != 100 || withMock.PredictedScore <= report.PredictedScore {
+		t.Errorf("Expected the perfect mock exam to raise the prediction, got %+v", withMock)
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Recommendation tuning
+const (
+	recommendationQuestionLimit = 20 // Questions per recommended session
+	recommendationLimit         = 10 // Recommendations returned
+	defaultSecondsPerQuestion   = 60 // Used before any timed answers exist
+	weakTopicAccuracy           = 70 // Topics below this accuracy count as weak
+	staleTopicDays              = 7  // Topics not practiced for this long count as stale
+)
+
+// questionPracticeStats summarizes the past answers to a single question
+type questionPracticeStats struct {
+	attempts int
+	correct  int
+	last     time.Time
+}
+
+// accuracy returns the share of correct answers, treating unanswered questions as unknown
+func (s questionPracticeStats) accuracy() float64 {
+	if s.attempts == 0 {
+		return 0.5
+	}
+	return float64(s.correct) / float64(s.attempts)
+}
+
+// averageSecondsPerQuestion returns the mean time spent on timed answers
+func averageSecondsPerQuestion(attempts []questionAttempt) float64 {
+	timed, total := 0, 0
+	for _, attempt := range attempts {
+		if attempt.TimeSpent > 0 {
+			timed++
+			total += attempt.TimeSpent
+		}
+	}
+	if timed == 0 {
+		return defaultSecondsPerQuestion
+	}
+	return float64(total) / float64(timed)
+}
+
+// estimateMinutes estimates how long answering a number of questions takes
+func estimateMinutes(questionCount int, secondsPerQuestion float64) int {
+	return int(math.Max(1, math.Ceil(float64(questionCount)*secondsPerQuestion/60)))
+}
+
+// limitQuestionIDs returns the IDs of at most recommendationQuestionLimit questions
+func limitQuestionIDs(questions []Question) []string {
+	ids := make([]string, 0, len(questions))
+	for _, q := range questions {
+		if len(ids) == recommendationQuestionLimit {
+			break
+		}
+		ids = append(ids, q.ID)
+	}
+	return ids
+}
+
+// Recommendation methods
+
+// GetStudyRecommendations ranks what to study next: due reviews, weak topics, topics
+// not practiced for a while and parts of the bank never attempted. Each recommendation
+// lists the questions to practice and can be started with StartRecommendation.
+func (a *App) GetStudyRecommendations() ([]StudyRecommendation, error) {
+	questions, err := a.practiceQuestions("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questions: %v", err)
+	}
+	attempts, err := a.db.GetQuestionAttempts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question attempts: %v", err)
+	}
+	weakest, err := a.GetWeakestTopics()
+	if err != nil {
+		return nil, err
+	}
+	due, err := a.GetDueWrongQuestions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due reviews: %v", err)
+	}
+
+	now := a.now()
+	stats := make(map[string]questionPracticeStats)
+	for _, attempt := range attempts {
+		s := stats[attempt.QuestionID]
+		s.attempts++
+		if attempt.IsCorrect {
+			s.correct++
+		}
+		if attempt.AnsweredAt.After(s.last) {
+			s.last = attempt.AnsweredAt
+		}
+		stats[attempt.QuestionID] = s
+	}
+	secondsPerQuestion := averageSecondsPerQuestion(attempts)
+
+	// Group the available questions by topic and note when each topic was last practiced
+	topicQuestions := make(map[string][]Question)
+	topicLast := make(map[string]time.Time)
+	var topics []string
+	for _, q := range questions {
+		for _, topic := range questionTopics(q) {
+			if _, ok := topicQuestions[topic]; !ok {
+				topics = append(topics, topic)
+			}
+			topicQuestions[topic] = append(topicQuestions[topic], q)
+			if last := stats[q.ID].last; last.After(topicLast[topic]) {
+				topicLast[topic] = last
+			}
+		}
+	}
+
+	var recommendations []StudyRecommendation
+
+	if len(due) > 0 {
+		ids := limitQuestionIDs(due)
+		recommendations = append(recommendations, StudyRecommendation{
+			ID:               RecommendationDueReviews,
+			Kind:             RecommendationDueReviews,
+			Title:            "Review due questions",
+			Reason:           fmt.Sprintf("%d wrong questions are due for review", len(due)),
+			Mode:             PracticeModeFlashcard,
+			QuestionIDs:      ids,
+			EstimatedMinutes: estimateMinutes(len(ids), secondsPerQuestion),
+			Score:            80 + math.Min(20, float64(len(due))),
+		})
+	}
+
+	// Weak topics come first, lowest accuracy first, with the least mastered questions
+	weakTopics := make(map[string]bool)
+	for _, item := range weakest {
+		topic, _ := item["topic"].(string)
+		accuracy, _ := item["accuracy"].(float64)
+		if accuracy >= weakTopicAccuracy || len(topicQuestions[topic]) == 0 {
+			continue
+		}
+		weakTopics[topic] = true
+		candidates := append([]Question(nil), topicQuestions[topic]...)
+		sort.SliceStable(candidates, func(i, j int) bool {
+			si, sj := stats[candidates[i].ID], stats[candidates[j].ID]
+			if si.accuracy() != sj.accuracy() {
+				return si.accuracy() < sj.accuracy()
+			}
+			return si.last.Before(sj.last)
+		})
+		ids := limitQuestionIDs(candidates)
+		reason := fmt.Sprintf("%.0f%% accuracy over %v attempts", accuracy, item["totalAttempts"])
+		days := now.Sub(topicLast[topic]).Hours() / 24
+		if days >= staleTopicDays {
+			reason += fmt.Sprintf(", last practiced %.0f days ago", days)
+		}
+		recommendations = append(recommendations, StudyRecommendation{
+			ID:               RecommendationWeakTopic + ":" + topic,
+			Kind:             RecommendationWeakTopic,
+			Title:            "Strengthen " + topic,
+			Reason:           reason,
+			Topic:            topic,
+			Mode:             PracticeModePractice,
+			QuestionIDs:      ids,
+			EstimatedMinutes: estimateMinutes(len(ids), secondsPerQuestion),
+			Score:            (100-accuracy)*0.7 + math.Min(20, days),
+		})
+	}
+
+	for _, topic := range topics {
+		candidates := append([]Question(nil), topicQuestions[topic]...)
+		last := topicLast[topic]
+
+		// Topics practiced before but not recently, least recently answered questions first
+		if days := now.Sub(last).Hours() / 24; !last.IsZero() && days >= staleTopicDays && !weakTopics[topic] {
+			sort.SliceStable(candidates, func(i, j int) bool {
+				return stats[candidates[i].ID].last.Before(stats[candidates[j].ID].last)
+			})
+			ids := limitQuestionIDs(candidates)
+			recommendations = append(recommendations, StudyRecommendation{
+				ID:               RecommendationStaleTopic + ":" + topic,
+				Kind:             RecommendationStaleTopic,
+				Title:            "Refresh " + topic,
+				Reason:           fmt.Sprintf("Last practiced %.0f days ago", days),
+				Topic:            topic,
+				Mode:             PracticeModePractice,
+				QuestionIDs:      ids,
+				EstimatedMinutes: estimateMinutes(len(ids), secondsPerQuestion),
+				Score:            math.Min(60, 20+days),
+			})
+		}
+
+		// Questions never attempted, for top-level topics only so nested tags are not repeated
+		if len(tagPathAncestors(topic)) > 1 {
+			continue
+		}
+		var untouched []Question
+		for _, q := range candidates {
+			if stats[q.ID].attempts == 0 {
+				untouched = append(untouched, q)
+			}
+		}
+		if len(untouched) == 0 {
+			continue
+		}
+		ids := limitQuestionIDs(untouched)
+		share := float64(len(untouched)) / float64(len(candidates))
+		recommendations = append(recommendations, StudyRecommendation{
+			ID:               RecommendationNewMaterial + ":" + topic,
+			Kind:             RecommendationNewMaterial,
+			Title:            "Start new questions in " + topic,
+			Reason:           fmt.Sprintf("%d of %d questions have never been attempted", len(untouched), len(candidates)),
+			Topic:            topic,
+			Mode:             PracticeModePractice,
+			QuestionIDs:      ids,
+			EstimatedMinutes: estimateMinutes(len(ids), secondsPerQuestion),
+			Score:            30 + 20*share,
+		})
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Score > recommendations[j].Score
+	})
+	if len(recommendations) > recommendationLimit {
+		recommendations = recommendations[:recommendationLimit]
+	}
+	if recommendations == nil {
+		recommendations = []StudyRecommendation{}
+	}
+	return recommendations, nil
+}
+
+// StartRecommendation starts a session for a recommendation returned by
+// GetStudyRecommendations, recomputing it so the session reflects current progress
+func (a *App) StartRecommendation(recommendationID string) (*ActiveSession, error) {
+	recommendations, err := a.GetStudyRecommendations()
+	if err != nil {
+		return nil, err
+	}
+	for _, recommendation := range recommendations {
+		if recommendation.ID == recommendationID {
+			return a.StartSession("", recommendation.Mode, recommendation.QuestionIDs)
+		}
+	}
+	return nil, fmt.Errorf("recommendation %s is no longer available", recommendationID)
+}
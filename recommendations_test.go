@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestStudyRecommendations tests ranking of due reviews, weak, stale and new topics
+func TestStudyRecommendations(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	app.clock = func() time.Time { return time.Date(2025, 6, 20, 9, 0, 0, 0, time.UTC) }
+
+	if recommendations, err := app.GetStudyRecommendations(); err != nil || len(recommendations) != 0 {
+		t.Fatalf("Expected no recommendations for an empty bank, got %+v (%v)", recommendations, err)
+	}
+
+	data := []map[string]interface{}{
+		{"question": "C1", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Cardio"}},
+		{"question": "C2", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Cardio"}},
+		{"question": "C3", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Cardio"}},
+		{"question": "R1", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Renal"}},
+		{"question": "R2", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Renal"}},
+		{"question": "N1", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Neuro"}},
+	}
+	app.ImportQuestions(data, "")
+	questions, _ := app.GetQuestions()
+	ids := make(map[string]string)
+	for _, q := range questions {
+		ids[q.Question] = q.ID
+	}
+
+	details, _ := json.Marshal([]QuestionRecord{
+		{QuestionID: ids["C3"], IsCorrect: true, TimeSpent: 30},
+		{QuestionID: ids["C1"], IsCorrect: false, TimeSpent: 30},
+		{QuestionID: ids["C2"], IsCorrect: false, TimeSpent: 30},
+		{QuestionID: ids["R1"], IsCorrect: true, TimeSpent: 30},
+		{QuestionID: ids["R2"], IsCorrect: true, TimeSpent: 30},
+	})
+	at := "2025-06-01T10:00:00Z"
+	db.CreatePracticeSession(&PracticeSession{ID: "s1", Mode: "practice", StartTime: at, TotalQuestions: 5, Details: details, CreatedAt: at})
+	app.AddWrongQuestion(ids["C1"], "")
+
+	recommendations, err := app.GetStudyRecommendations()
+	if err != nil {
+		t.Fatalf("Failed to get recommendations: %v", err)
+	}
+	var order []string
+	for _, r := range recommendations {
+		order = append(order, r.ID)
+	}
+	expected := []string{"due_reviews", "weak_topic:Cardio", "new_material:Neuro", "stale_topic:Renal"}
+	if len(order) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, order)
+		}
+	}
+
+	weak := recommendations[1]
+	if len(weak.QuestionIDs) != 3 || weak.QuestionIDs[2] != ids["C3"] || weak.EstimatedMinutes != 2 || weak.Mode != PracticeModePractice {
+		t.Errorf("Expected the missed questions first and a 2 minute estimate, got %+v", weak)
+	}
+	if recommendations[0].Mode != PracticeModeFlashcard || recommendations[0].QuestionIDs[0] != ids["C1"] {
+		t.Errorf("Expected due reviews as flashcards, got %+v", recommendations[0])
+	}
+
+	session, err := app.StartRecommendation("new_material:Neuro")
+	if err != nil {
+		t.Fatalf("Failed to start recommendation: %v", err)
+	}
+	if len(session.QuestionIDs) != 1 || session.QuestionIDs[0] != ids["N1"] {
+		t.Errorf("Expected a session with the untouched question, got %v", session.QuestionIDs)
+	}
+	if _, err := app.StartRecommendation("weak_topic:Renal"); err == nil {
+		t.Error("Expected an unknown recommendation to be rejected")
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Forgetting curve parameters
+const (
+	minRetentionReviews       = 5    // Reviews a topic needs before its own curve is fitted
+	defaultHalfLifeDays       = 30.0 // Half-life of topics with too few reviews
+	retentionStaleDrop        = 20.0 // Points below the accuracy at which a topic counts as stale
+	retentionHalfLifeSpread   = 1.0  // Spread of the log-normal prior around the default half-life
+	minRetentionHalfLife      = 1.0
+	maxRetentionHalfLife      = 730.0
+	retentionHalfLifeSteps    = 40
+	retentionRecallStep       = 0.02
+	minRetentionInitialRecall = 0.3
+)
+
+// retentionReview is an answer to a question given some days after its previous answer
+type retentionReview struct {
+	days    float64
+	correct bool
+}
+
+// retentionRecall returns the probability of recalling an answer days after practice
+func retentionRecall(initial, halfLife, days float64) float64 {
+	return initial * math.Pow(0.5, days/halfLife)
+}
+
+// fitForgettingCurve finds the initial recall and half-life that best explain the
+// reviews, searching a grid of half-lives spaced evenly on a log scale. A prior pulls
+// the half-life towards the default when the reviews say little about it, such as
+// when they all came shortly after the previous answer.
+func fitForgettingCurve(reviews []retentionReview) (float64, float64) {
+	bestInitial, bestHalfLife, best := 0.0, 0.0, math.Inf(-1)
+	ratio := math.Pow(maxRetentionHalfLife/minRetentionHalfLife, 1/float64(retentionHalfLifeSteps-1))
+	halfLife := minRetentionHalfLife
+	for step := 0; step < retentionHalfLifeSteps; step++ {
+		spread := math.Log(halfLife/defaultHalfLifeDays) / retentionHalfLifeSpread
+		for initial := minRetentionInitialRecall; initial <= 1+1e-9; initial += retentionRecallStep {
+			likelihood := -spread * spread / 2
+			for _, review := range reviews {
+				p := math.Min(0.99, math.Max(0.01, retentionRecall(initial, halfLife, review.days)))
+				if review.correct {
+					likelihood += math.Log(p)
+				} else {
+					likelihood += math.Log(1 - p)
+				}
+			}
+			if likelihood > best {
+				bestInitial, bestHalfLife, best = math.Min(1, initial), halfLife, likelihood
+			}
+		}
+		halfLife *= ratio
+	}
+	return bestInitial, bestHalfLife
+}
+
+// Retention methods
+
+// GetTopicRetention estimates a forgetting curve per topic from the answer history.
+// Each answer to a question answered before is a review of how well it was recalled
+// after the time since that previous answer; topics with enough reviews get their own
+// initial recall and half-life, the rest start at their accuracy and use a 30 day
+// half-life. The predicted recall averages the curve over the topic's questions at the
+// time since each was last answered. Topics are returned lowest predicted recall first.
+func (a *App) GetTopicRetention() ([]TopicRetention, error) {
+	attempts, err := a.db.GetQuestionAttempts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question attempts: %v", err)
+	}
+	questionList, err := a.db.GetQuestions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questions: %v", err)
+	}
+	questions := make(map[string]Question, len(questionList))
+	for _, q := range questionList {
+		questions[q.ID] = q
+	}
+
+	type topicHistory struct {
+		retention TopicRetention
+		correct   int
+		reviews   []retentionReview
+		last      []time.Time // Last answer to each question
+	}
+	now := a.now()
+	histories := make(map[string]*topicHistory)
+	for questionID, questionAttempts := range groupAttemptsByQuestion(attempts) {
+		q, ok := questions[questionID]
+		if !ok {
+			continue
+		}
+		var reviews []retentionReview
+		correct := 0
+		for i, attempt := range questionAttempts {
+			if attempt.IsCorrect {
+				correct++
+			}
+			if i > 0 {
+				days := attempt.AnsweredAt.Sub(questionAttempts[i-1].AnsweredAt).Hours() / 24
+				reviews = append(reviews, retentionReview{days: days, correct: attempt.IsCorrect})
+			}
+		}
+		last := questionAttempts[len(questionAttempts)-1].AnsweredAt
+
+		for _, topic := range questionTopics(q) {
+			history := histories[topic]
+			if history == nil {
+				history = &topicHistory{retention: TopicRetention{Topic: topic}}
+				histories[topic] = history
+			}
+			history.retention.Questions++
+			history.retention.Attempts += len(questionAttempts)
+			history.correct += correct
+			history.reviews = append(history.reviews, reviews...)
+			history.last = append(history.last, last)
+		}
+	}
+
+	retention := make([]TopicRetention, 0, len(histories))
+	for _, history := range histories {
+		topic := history.retention
+		topic.Accuracy = float64(history.correct) / float64(topic.Attempts) * 100
+		topic.Reviews = len(history.reviews)
+		if topic.Reviews >= minRetentionReviews {
+			topic.InitialRecall, topic.HalfLifeDays = fitForgettingCurve(history.reviews)
+			topic.Fitted = true
+		} else {
+			topic.InitialRecall = float64(history.correct+1) / float64(topic.Attempts+2)
+			topic.HalfLifeDays = defaultHalfLifeDays
+		}
+
+		recall := 0.0
+		var latest time.Time
+		for _, last := range history.last {
+			recall += retentionRecall(topic.InitialRecall, topic.HalfLifeDays, math.Max(0, now.Sub(last).Hours()/24))
+			if last.After(latest) {
+				latest = last
+			}
+		}
+		topic.PredictedRecall = recall / float64(len(history.last)) * 100
+		topic.DaysSincePractice = math.Max(0, now.Sub(latest).Hours()/24)
+		topic.Stale = topic.PredictedRecall <= topic.Accuracy-retentionStaleDrop
+		retention = append(retention, topic)
+	}
+	sort.Slice(retention, func(i, j int) bool {
+		if retention[i].PredictedRecall != retention[j].PredictedRecall {
+			return retention[i].PredictedRecall < retention[j].PredictedRecall
+		}
+		return retention[i].Topic < retention[j].Topic
+	})
+	return retention, nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestFitForgettingCurve tests that the fit recovers the curve reviews were drawn from
+func TestFitForgettingCurve(t *testing.T) {
+	var reviews []retentionReview
+	for _, days := range []float64{1, 5, 10, 20, 40} {
+		correct := int(retentionRecall(0.9, 10, days)*100 + 0.5)
+		for i := 0; i < 100; i++ {
+			reviews = append(reviews, retentionReview{days: days, correct: i < correct})
+		}
+	}
+	initial, halfLife := fitForgettingCurve(reviews)
+	if initial < 0.8 || initial > 1 || halfLife < 7 || halfLife > 14 {
+		t.Errorf("Expected a curve near 90%% with a 10 day half-life, got %.2f and %.1f days", initial, halfLife)
+	}
+
+	// Reviews right after practice say nothing about forgetting
+	initial, halfLife = fitForgettingCurve([]retentionReview{{0, true}, {0, true}, {0.01, true}, {0, true}, {0, true}})
+	if initial != 1 || halfLife < 20 || halfLife > 45 {
+		t.Errorf("Expected the default half-life to hold, got %.2f and %.1f days", initial, halfLife)
+	}
+}
+
+// TestTopicRetention tests that topics mastered long ago rank as weaker than their
+// accuracy suggests
+func TestTopicRetention(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	now := time.Now()
+	app.clock = func() time.Time { return now }
+
+	app.ImportQuestions([]map[string]interface{}{
+		{"question": "O1", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Old"}},
+		{"question": "O2", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Old"}},
+		{"question": "R1", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Recent"}},
+		{"question": "R2", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Recent"}},
+	}, "")
+	questions, _ := app.GetQuestions()
+	ids := make(map[string]string)
+	for _, q := range questions {
+		ids[q.Question] = q.ID
+	}
+
+	addSession := func(id string, daysAgo int, records []QuestionRecord) {
+		details, _ := json.Marshal(records)
+		stamp := now.AddDate(0, 0, -daysAgo).Format(time.RFC3339)
+		db.CreatePracticeSession(&PracticeSession{ID: id, Mode: "practice", StartTime: stamp, TotalQuestions: len(records), Details: details, CreatedAt: stamp})
+	}
+	for i, daysAgo := range []int{100, 90} {
+		addSession(fmt.Sprintf("old%d", i), daysAgo, []QuestionRecord{
+			{QuestionID: ids["O1"], IsCorrect: true}, {QuestionID: ids["O2"], IsCorrect: true},
+		})
+	}
+	for i, daysAgo := range []int{2, 1} {
+		addSession(fmt.Sprintf("recent%d", i), daysAgo, []QuestionRecord{
+			{QuestionID: ids["R1"], IsCorrect: true}, {QuestionID: ids["R2"], IsCorrect: false},
+		})
+	}
+
+	retention, err := app.GetTopicRetention()
+	if err != nil {
+		t.Fatalf("Failed to get topic retention: %v", err)
+	}
+	if len(retention) != 2 || retention[0].Topic != "Old" || retention[0].Accuracy != 100 || !retention[0].Stale || retention[0].Fitted {
+		t.Fatalf("Expected Old to be stale despite its accuracy, got %+v", retention)
+	}
+	if retention[0].PredictedRecall > 20 || retention[0].DaysSincePractice < 89 || retention[0].Reviews != 2 {
+		t.Errorf("Expected little recall after 90 days, got %+v", retention[0])
+	}
+	if retention[1].Stale || retention[1].PredictedRecall < 45 || retention[1].PredictedRecall > 50 {
+		t.Errorf("Expected Recent to be recalled at about its accuracy, got %+v", retention[1])
+	}
+
+	weakest, err := app.GetWeakestTopics()
+	if err != nil {
+		t.Fatalf("Failed to get weakest topics: %v", err)
+	}
+	if len(weakest) != 2 || weakest[0]["topic"] != "Old" || weakest[0]["stale"] != true || weakest[1]["accuracy"] != 50.0 {
+		t.Errorf("Expected the stale topic to rank first, got %v", weakest)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestQuestionRevisionHistory tests that edits are recorded and can be rolled back
+func TestQuestionRevisionHistory(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	options, _ := json.Marshal([]QuestionOption{{ID: "a", Text: "A"}, {ID: "b", Text: "B"}})
+	created, err := app.CreateQuestion(Question{
+		Question: "Which option is correct?",
+		Options:  options,
+		Answer:   json.RawMessage(`["a"]`),
+		Tags:     json.RawMessage(`["test"]`),
+		Source:   "Unit Test",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create question: %v", err)
+	}
+
+	edited := *created
+	edited.Answer = json.RawMessage(`["b"]`)
+	if err := app.UpdateQuestionWithReason(edited, "Fix answer key"); err != nil {
+		t.Fatalf("Failed to update question: %v", err)
+	}
+
+	// Saving identical content should not add a revision
+	if err := app.UpdateQuestion(edited); err != nil {
+		t.Fatalf("Failed to update question: %v", err)
+	}
+
+	revisions, err := app.GetQuestionRevisions(created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get revisions: %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("Expected 2 revisions, got %d", len(revisions))
+	}
+	if revisions[1].Actor != RevisionActorUser || revisions[1].Reason != "Fix answer key" {
+		t.Errorf("Unexpected revision metadata: actor=%s reason=%s", revisions[1].Actor, revisions[1].Reason)
+	}
+
+	diff, err := app.DiffQuestionRevisions(created.ID, 1, 2)
+	if err != nil {
+		t.Fatalf("Failed to diff revisions: %v", err)
+	}
+	if len(diff) != 1 || diff[0].Field != "answer" {
+		t.Fatalf("Expected only answer to change, got %+v", diff)
+	}
+	if string(diff[0].From) != `["a"]` || string(diff[0].To) != `["b"]` {
+		t.Errorf("Unexpected answer diff: %s -> %s", diff[0].From, diff[0].To)
+	}
+
+	restored, err := app.RollbackQuestion(created.ID, 1)
+	if err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+	if string(restored.Answer) != `["a"]` {
+		t.Errorf("Expected restored answer [\"a\"], got %s", restored.Answer)
+	}
+
+	revisions, _ = app.GetQuestionRevisions(created.ID)
+	if len(revisions) != 3 || revisions[2].Actor != RevisionActorRollback || revisions[2].Revision != 3 {
+		t.Errorf("Expected rollback to be recorded as revision 3, got %+v", revisions)
+	}
+
+	// Revisions are deleted with their question and restored by undo
+	if err := app.DeleteQuestion(created.ID); err != nil {
+		t.Fatalf("Failed to delete question: %v", err)
+	}
+	var orphans int
+	db.db.QueryRow(`SELECT COUNT(*) FROM question_revisions WHERE question_id = ?`, created.ID).Scan(&orphans)
+	if orphans != 0 {
+		t.Errorf("Expected the revisions to be deleted with the question, found %d", orphans)
+	}
+	if _, err := app.Undo(); err != nil {
+		t.Fatalf("Failed to undo delete: %v", err)
+	}
+	if revisions, _ = app.GetQuestionRevisions(created.ID); len(revisions) != 3 {
+		t.Errorf("Expected undo to restore 3 revisions, got %d", len(revisions))
+	}
+}
+
+// TestQuestionRevisionBaseline tests that questions without history get a baseline before changes
+func TestQuestionRevisionBaseline(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	difficulty := 3
+	question := &Question{
+		ID:         "legacy-1",
+		Question:   "Legacy question",
+		Options:    json.RawMessage(`[]`),
+		Answer:     json.RawMessage(`["a"]`),
+		Tags:       json.RawMessage(`[]`),
+		Difficulty: &difficulty,
+		CreatedAt:  "2025-07-24T00:00:00Z",
+		UpdatedAt:  "2025-07-24T00:00:00Z",
+	}
+	if err := db.CreateQuestion(question); err != nil {
+		t.Fatalf("Failed to create question: %v", err)
+	}
+
+	for _, id := range []string{"session-1", "session-2", "session-3"} {
+		session := &PracticeSession{
+			ID:             id,
+			Mode:           "practice",
+			StartTime:      "2025-07-25T00:00:00Z",
+			TotalQuestions: 1,
+			CorrectCount:   1,
+			Details:        json.RawMessage(`[{"questionId":"legacy-1","isCorrect":true}]`),
+			CreatedAt:      "2025-07-25T00:00:00Z",
+		}
+		if err := db.CreatePracticeSession(session); err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+	}
+	if _, err := app.CalibrateDifficulties(CalibrationModel1PL); err != nil {
+		t.Fatalf("Failed to calibrate difficulties: %v", err)
+	}
+
+	revisions, err := app.GetQuestionRevisions("legacy-1")
+	if err != nil {
+		t.Fatalf("Failed to get revisions: %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("Expected baseline and auto-difficulty revisions, got %d", len(revisions))
+	}
+	if revisions[0].Actor != RevisionActorBaseline || revisions[1].Actor != RevisionActorAutoDifficulty {
+		t.Errorf("Unexpected actors: %s, %s", revisions[0].Actor, revisions[1].Actor)
+	}
+}
+
+// TestUndoQuestionRevisions tests that undoing an edit or a creation removes its revision
+func TestUndoQuestionRevisions(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	created, err := app.CreateQuestion(Question{
+		Question: "Which option is correct?",
+		Options:  json.RawMessage(`[]`),
+		Answer:   json.RawMessage(`["a"]`),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create question: %v", err)
+	}
+	edited := *created
+	edited.Answer = json.RawMessage(`["b"]`)
+	if err := app.UpdateQuestionWithReason(edited, "Fix answer key"); err != nil {
+		t.Fatalf("Failed to update question: %v", err)
+	}
+
+	if _, err := app.Undo(); err != nil {
+		t.Fatalf("Failed to undo update: %v", err)
+	}
+	revisions, _ := app.GetQuestionRevisions(created.ID)
+	if len(revisions) != 1 || revisions[0].Reason != "Created" {
+		t.Errorf("Expected only the creation revision after undoing the edit, got %+v", revisions)
+	}
+
+	if _, err := app.Undo(); err != nil {
+		t.Fatalf("Failed to undo create: %v", err)
+	}
+	if revisions, _ := app.GetQuestionRevisions(created.ID); len(revisions) != 0 {
+		t.Errorf("Expected no revisions after undoing the creation, got %+v", revisions)
+	}
+}
+
+// TestRevisionsUseAppClock tests that questions, their revisions and the undo journal
+// are stamped with the app clock
+func TestRevisionsUseAppClock(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	app.clock = func() time.Time { return now }
+	stamp := now.Format(time.RFC3339)
+
+	created, err := app.CreateQuestion(Question{
+		Question: "Which option is correct?",
+		Options:  json.RawMessage(`[]`),
+		Answer:   json.RawMessage(`["a"]`),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create question: %v", err)
+	}
+
+	stored, err := db.GetQuestionByID(created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get question: %v", err)
+	}
+	if stored.CreatedAt != stamp || stored.UpdatedAt != stamp {
+		t.Errorf("Expected the question to be stamped %s, got %s / %s", stamp, stored.CreatedAt, stored.UpdatedAt)
+	}
+	revisions, _ := app.GetQuestionRevisions(created.ID)
+	if len(revisions) != 1 || revisions[0].CreatedAt != stamp {
+		t.Errorf("Expected one revision stamped %s, got %+v", stamp, revisions)
+	}
+	operations, _ := app.ListRecentOperations(10)
+	if len(operations) != 1 || operations[0].CreatedAt != stamp {
+		t.Errorf("Expected one journal operation stamped %s, got %+v", stamp, operations)
+	}
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+)
+
+// sanitizeQuestion returns a question without its answer and explanation
+func sanitizeQuestion(q Question) SessionQuestion {
+	return SessionQuestion{
+		ID:          q.ID,
+		Question:    q.Question,
+		Options:     q.Options,
+		Tags:        q.Tags,
+		ImageURL:    q.ImageURL,
+		Difficulty:  q.Difficulty,
+		MultiAnswer: len(parseQuestionAnswer(q.Answer)) > 1,
+	}
+}
+
+// revealQuestion returns a question with its answer and explanation
+func revealQuestion(q Question) SessionQuestion {
+	revealed := sanitizeQuestion(q)
+	revealed.Answer = q.Answer
+	revealed.Explanation = q.Explanation
+	return revealed
+}
+
+// containsString reports whether a list contains a value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// concealsAnswers reports whether answers are withheld in a session
+func (s *ActiveSession) concealsAnswers() bool {
+	return s.Mode == PracticeModeTest && s.Status == SessionStatusActive
+}
+
+// revealsQuestion reports whether the answer of a session question may be shown
+func (s *ActiveSession) revealsQuestion(questionID string) bool {
+	if s.Mode == PracticeModeFlashcard && s.Status == SessionStatusActive {
+		return containsString(s.Revealed, questionID)
+	}
+	return !s.concealsAnswers()
+}
+
+// Active session database methods
+
+// SaveActiveSession stores the state of a session
+func (d *Database) SaveActiveSession(session *ActiveSession, at time.Time) error {
+	stored := *session
+	stored.Questions = nil
+	stateJSON, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	now := at.Format(time.RFC3339)
+	_, err = d.db.Exec(`INSERT INTO active_sessions (id, status, state, created_at, updated_at) VALUES (?, ?, ?, ?, ?)
+			  ON CONFLICT(id) DO UPDATE SET status = excluded.status, state = excluded.state, updated_at = excluded.updated_at`,
+		session.ID, session.Status, string(stateJSON), now, now)
+	return err
+}
+
+// GetActiveSession loads the state of a session
+func (d *Database) GetActiveSession(sessionID string) (*ActiveSession, error) {
+	var stateJSON string
+	err := d.db.QueryRow(`SELECT state FROM active_sessions WHERE id = ?`, sessionID).Scan(&stateJSON)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var session ActiveSession
+	if err := json.Unmarshal([]byte(stateJSON), &session); err != nil {
+		return nil, fmt.Errorf("failed to parse session: %v", err)
+	}
+	return &session, nil
+}
+
+// isValidSessionMode reports whether a mode is served by the session API
+func isValidSessionMode(mode string) bool {
+	switch mode {
+	case PracticeModePractice, PracticeModeTest, PracticeModeFlashcard:
+		return true
+	}
+	return false
+}
+
+// Active session methods
+
+// saveActiveSession stores the state of a session outside any journaled operation, so
+// undoing an operation that overlaps it does not revert the session
+func (a *App) saveActiveSession(session *ActiveSession) error {
+	return a.withoutJournal(func() error { return a.db.SaveActiveSession(session, a.now()) })
+}
+
+// StartSession starts a session over the given questions, or over every question and
+// template of the group (or the whole bank when groupID is empty) when questionIDs is
+// empty, least answered first when the preferUnderexposed setting is on. Templates are
+// replaced by a variant seeded from the session. Test sessions serve questions without
+// answers and explanations until they finish.
+func (a *App) StartSession(groupID, mode string, questionIDs []string) (*ActiveSession, error) {
+	if mode == "" {
+		mode = PracticeModePractice
+	}
+	if !isValidSessionMode(mode) {
+		return nil, fmt.Errorf("unknown session mode %q", mode)
+	}
+	if len(questionIDs) == 0 {
+		questions, err := a.practiceQuestions(groupID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get questions: %v", err)
+		}
+		a.preferUnderexposed(questions)
+		for _, q := range questions {
+			questionIDs = append(questionIDs, q.ID)
+		}
+		if groupID != "" {
+			templates, err := a.db.GetQuestionTemplates(groupID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get question templates: %v", err)
+			}
+			for _, template := range templates {
+				questionIDs = append(questionIDs, template.ID)
+			}
+		}
+	}
+	if len(questionIDs) == 0 {
+		return nil, fmt.Errorf("no questions available for the session")
+	}
+
+	session := &ActiveSession{
+		ID:          fmt.Sprintf("session_%d_%d", time.Now().UnixNano(), rand.Int63()),
+		GroupID:     groupID,
+		Mode:        mode,
+		Status:      SessionStatusActive,
+		QuestionIDs: make([]string, 0, len(questionIDs)),
+		Records:     []QuestionRecord{},
+		StartedAt:   a.now().Format(time.RFC3339),
+	}
+	for _, id := range questionIDs {
+		if isTemplateID(id) {
+			template, err := a.db.GetQuestionTemplate(id)
+			if err != nil {
+				return nil, err
+			}
+			variant, err := a.instantiateTemplate(template, sessionTemplateSeed(session.ID, id))
+			if err != nil {
+				return nil, err
+			}
+			id = variant.ID
+		}
+		session.QuestionIDs = append(session.QuestionIDs, id)
+	}
+	if err := a.saveActiveSession(session); err != nil {
+		return nil, fmt.Errorf("failed to save session: %v", err)
+	}
+	return a.presentSession(session)
+}
+
+// GetSession returns the state of a session as the frontend may see it
+func (a *App) GetSession(sessionID string) (*ActiveSession, error) {
+	session, err := a.db.GetActiveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return a.presentSession(session)
+}
+
+// SubmitSessionAnswer grades an answer on the server. Practice sessions reveal the
+// result, answer and explanation right away and accept one answer per question; test
+// sessions reveal nothing and accept changed answers until they finish. Confidence is
+// guess, unsure, sure or empty when the learner did not rate the answer.
+func (a *App) SubmitSessionAnswer(sessionID, questionID string, userAnswer []string, timeSpent int, confidence string) (*AnswerFeedback, error) {
+	if !isValidConfidence(confidence) {
+		return nil, fmt.Errorf("unknown confidence level %q", confidence)
+	}
+	session, err := a.db.GetActiveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Mode == PracticeModeFlashcard {
+		return nil, fmt.Errorf("flashcards are graded with GradeFlashcard")
+	}
+	if session.Status != SessionStatusActive {
+		return nil, fmt.Errorf("session %s has already finished", sessionID)
+	}
+	inSession := false
+	for _, id := range session.QuestionIDs {
+		inSession = inSession || id == questionID
+	}
+	if !inSession {
+		return nil, fmt.Errorf("question %s is not part of the session", questionID)
+	}
+
+	question, err := a.db.GetQuestionByID(questionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question: %v", err)
+	}
+	record := QuestionRecord{
+		QuestionID: questionID,
+		UserAnswer: userAnswer,
+		IsCorrect:  a.gradeAnswer(*question, userAnswer),
+		TimeSpent:  timeSpent,
+		Confidence: confidence,
+	}
+
+	answered := false
+	for i := range session.Records {
+		if session.Records[i].QuestionID != questionID {
+			continue
+		}
+		if !session.concealsAnswers() {
+			return nil, fmt.Errorf("question %s has already been answered", questionID)
+		}
+		record.TimeSpent += session.Records[i].TimeSpent
+		session.Records[i] = record
+		answered = true
+	}
+	if !answered {
+		session.Records = append(session.Records, record)
+	}
+	if err := a.saveActiveSession(session); err != nil {
+		return nil, fmt.Errorf("failed to save session: %v", err)
+	}
+	if _, err := a.recordAnswerEvent(session, questionID, userAnswer, record.IsCorrect); err != nil {
+		log.Printf("Warning: Failed to record answer event: %v", err)
+	}
+
+	feedback := &AnswerFeedback{QuestionID: questionID}
+	if !session.concealsAnswers() {
+		feedback.Revealed = true
+		feedback.IsCorrect = record.IsCorrect
+		feedback.Answer = question.Answer
+		feedback.Explanation = question.Explanation
+	}
+	return feedback, nil
+}
+
+// FinishSession ends a session, records it as a practice session and returns it with
+// answers, explanations and results. Unanswered questions are recorded as incorrect.
+func (a *App) FinishSession(sessionID string) (*ActiveSession, error) {
+	session, err := a.db.GetActiveSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != SessionStatusActive {
+		return nil, fmt.Errorf("session %s has already finished", sessionID)
+	}
+
+	now := a.now()
+	finishedAt := now.Format(time.RFC3339)
+	session.Status = SessionStatusFinished
+	session.FinishedAt = &finishedAt
+	session.CorrectCount = 0
+	answers := make(map[string]QuestionRecord, len(session.Records))
+	for _, record := range session.Records {
+		answers[record.QuestionID] = record
+		if record.IsCorrect {
+			session.CorrectCount++
+		}
+	}
+	records := make([]QuestionRecord, 0, len(session.QuestionIDs))
+	for _, id := range session.QuestionIDs {
+		record, ok := answers[id]
+		if !ok {
+			record = QuestionRecord{QuestionID: id, UserAnswer: []string{}}
+		}
+		records = append(records, record)
+	}
+	duration := 0
+	if started, err := time.Parse(time.RFC3339, session.StartedAt); err == nil {
+		duration = int(now.Sub(started).Seconds())
+	}
+
+	practiceSession := &PracticeSession{
+		ID:             session.ID,
+		GroupID:        session.GroupID,
+		Mode:           session.Mode,
+		StartTime:      session.StartedAt,
+		EndTime:        &finishedAt,
+		Duration:       duration,
+		TotalQuestions: len(session.QuestionIDs),
+		CorrectCount:   session.CorrectCount,
+		CreatedAt:      finishedAt,
+	}
+	if err := a.saveCompletedSession(practiceSession, records); err != nil {
+		return nil, err
+	}
+	if err := a.saveActiveSession(session); err != nil {
+		return nil, fmt.Errorf("failed to save session: %v", err)
+	}
+	if _, err := a.recordSessionEvent(session.ID, SessionEventFinished, ""); err != nil {
+		log.Printf("Warning: Failed to record finish event: %v", err)
+	}
+	return a.presentSession(session)
+}
+
+// presentSession fills in the session questions, withholding answers, explanations and
+// results while a test session is active and unrevealed flashcard answers
+func (a *App) presentSession(session *ActiveSession) (*ActiveSession, error) {
+	session.Questions = make([]SessionQuestion, 0, len(session.QuestionIDs))
+	for _, id := range session.QuestionIDs {
+		q, err := a.db.GetQuestionByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get question %s: %v", id, err)
+		}
+		if session.revealsQuestion(id) {
+			session.Questions = append(session.Questions, revealQuestion(*q))
+		} else {
+			session.Questions = append(session.Questions, sanitizeQuestion(*q))
+		}
+	}
+	if session.concealsAnswers() {
+		for i := range session.Records {
+			session.Records[i].IsCorrect = false
+		}
+	}
+	return session, nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestTestSessionConcealsAnswers tests that test sessions withhold answers until they finish
+func TestTestSessionConcealsAnswers(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	data := []map[string]interface{}{
+		{"question": "Q1", "options": []string{"A", "B"}, "answer": []string{"a"}, "explanation": "Because A"},
+		{"question": "Q2", "options": []string{"A", "B", "C"}, "answer": []string{"a", "c"}, "explanation": "A and C"},
+		{"question": "Q3", "options": []string{"A", "B"}, "answer": []string{"b"}},
+	}
+	app.ImportQuestions(data, "")
+
+	if _, err := app.StartSession("", "exam", nil); err == nil {
+		t.Error("Expected an unknown session mode to be rejected")
+	}
+	session, err := app.StartSession("", PracticeModeTest, nil)
+	if err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	if len(session.Questions) != 3 {
+		t.Fatalf("Expected 3 questions, got %d", len(session.Questions))
+	}
+	byText := func(session *ActiveSession) map[string]SessionQuestion {
+		questions := make(map[string]SessionQuestion)
+		for _, q := range session.Questions {
+			questions[q.Question] = q
+		}
+		return questions
+	}
+	questions := byText(session)
+	payload, _ := json.Marshal(session)
+	var served struct {
+		Questions []map[string]interface{} `json:"questions"`
+	}
+	json.Unmarshal(payload, &served)
+	for _, q := range served.Questions {
+		if _, ok := q["answer"]; ok {
+			t.Errorf("Expected no answer in the payload, got %v", q)
+		}
+		if _, ok := q["explanation"]; ok {
+			t.Errorf("Expected no explanation in the payload, got %v", q)
+		}
+	}
+	if questions["Q1"].MultiAnswer || !questions["Q2"].MultiAnswer {
+		t.Errorf("Expected only the second question to be multiple answer, got %+v", session.Questions)
+	}
+
+	q1, q2 := questions["Q1"].ID, questions["Q2"].ID
+	feedback, err := app.SubmitSessionAnswer(session.ID, q1, []string{"b"}, 5, "")
+	if err != nil {
+		t.Fatalf("Failed to submit answer: %v", err)
+	}
+	if feedback.Revealed || feedback.Answer != nil || feedback.Explanation != "" {
+		t.Errorf("Expected no feedback in test mode, got %+v", feedback)
+	}
+
+	// Answers may be changed until the session finishes
+	if _, err := app.SubmitSessionAnswer(session.ID, q1, []string{"a"}, 3, ""); err != nil {
+		t.Fatalf("Failed to change answer: %v", err)
+	}
+	if _, err := app.SubmitSessionAnswer(session.ID, q2, []string{"c", "a"}, 4, ""); err != nil {
+		t.Fatalf("Failed to submit answer: %v", err)
+	}
+	if _, err := app.SubmitSessionAnswer(session.ID, "unknown", []string{"a"}, 1, ""); err == nil {
+		t.Error("Expected an answer to a question outside the session to be rejected")
+	}
+
+	session, _ = app.GetSession(session.ID)
+	for _, record := range session.Records {
+		if record.IsCorrect {
+			t.Errorf("Expected results to be hidden while the session is active, got %+v", record)
+		}
+	}
+
+	session, err = app.FinishSession(session.ID)
+	if err != nil {
+		t.Fatalf("Failed to finish session: %v", err)
+	}
+	app.waitForCalibration()
+	if session.CorrectCount != 2 || session.Records[0].TimeSpent != 8 {
+		t.Errorf("Unexpected results: %+v", session)
+	}
+	if finished := byText(session)["Q1"]; finished.Explanation != "Because A" || finished.Answer == nil {
+		t.Errorf("Expected answers and explanations after the session, got %+v", finished)
+	}
+	if _, err := app.SubmitSessionAnswer(session.ID, q1, []string{"b"}, 1, ""); err == nil {
+		t.Error("Expected answers after the session to be rejected")
+	}
+
+	sessions, _ := db.GetPracticeSessions()
+	if len(sessions) != 1 || sessions[0].Mode != PracticeModeTest || sessions[0].CorrectCount != 2 {
+		t.Fatalf("Expected the finished session to be saved, got %+v", sessions)
+	}
+	// The unanswered question counts as incorrect
+	var records []QuestionRecord
+	json.Unmarshal(sessions[0].Details, &records)
+	if sessions[0].TotalQuestions != 3 || len(records) != 3 {
+		t.Errorf("Expected all 3 questions in the record, got %d and %+v", sessions[0].TotalQuestions, records)
+	}
+	if wrong, _ := app.IsQuestionMarkedWrong(questions["Q3"].ID); !wrong {
+		t.Error("Expected the unanswered question to be marked wrong")
+	}
+}
+
+// TestPracticeSessionFeedback tests immediate feedback in practice mode
+func TestPracticeSessionFeedback(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	data := []map[string]interface{}{
+		{"question": "Q1", "options": []string{"A", "B"}, "answer": []string{"a"}, "explanation": "Because A"},
+	}
+	app.ImportQuestions(data, "")
+
+	session, err := app.StartSession("", PracticeModePractice, nil)
+	if err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	q1 := session.Questions[0].ID
+
+	feedback, err := app.SubmitSessionAnswer(session.ID, q1, []string{"b"}, 5, "")
+	if err != nil {
+		t.Fatalf("Failed to submit answer: %v", err)
+	}
+	if !feedback.Revealed || feedback.IsCorrect || feedback.Explanation != "Because A" {
+		t.Errorf("Expected immediate feedback, got %+v", feedback)
+	}
+	if _, err := app.SubmitSessionAnswer(session.ID, q1, []string{"a"}, 5, ""); err == nil {
+		t.Error("Expected a second answer after feedback to be rejected")
+	}
+}
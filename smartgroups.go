@@ -0,0 +1,488 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Smart group query matching modes
+const (
+	SmartMatchAll = "all"
+	SmartMatchAny = "any"
+)
+
+// smartFieldOps lists the operators accepted for each smart group field
+var smartFieldOps = map[string][]string{
+	"tag":             {"eq", "neq"},
+	"difficulty":      {"eq", "neq", "gt", "gte", "lt", "lte"},
+	"source":          {"eq", "neq", "contains"},
+	"text":            {"contains"},
+	"group":           {"eq", "neq"},
+	"attempts":        {"eq", "neq", "gt", "gte", "lt", "lte"},
+	"wrongWithinDays": {"within"},
+	"markedWrong":     {"eq"},
+}
+
+// marshalSmartQuery encodes a smart query for storage, returning nil for static groups
+func marshalSmartQuery(query *SmartGroupQuery) (interface{}, error) {
+	if query == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal smart query: %v", err)
+	}
+	return string(data), nil
+}
+
+// unmarshalSmartQuery decodes a stored smart query
+func unmarshalSmartQuery(value sql.NullString) (*SmartGroupQuery, error) {
+	if !value.Valid || value.String == "" {
+		return nil, nil
+	}
+	var query SmartGroupQuery
+	if err := json.Unmarshal([]byte(value.String), &query); err != nil {
+		return nil, fmt.Errorf("failed to parse smart query: %v", err)
+	}
+	return &query, nil
+}
+
+// validateSmartQuery checks that every condition uses a known field, operator and value type
+func validateSmartQuery(query SmartGroupQuery) error {
+	if query.Match != "" && query.Match != SmartMatchAll && query.Match != SmartMatchAny {
+		return fmt.Errorf("unknown match mode %q", query.Match)
+	}
+	for i, cond := range query.Conditions {
+		ops, ok := smartFieldOps[cond.Field]
+		if !ok {
+			return fmt.Errorf("condition %d: unknown field %q", i+1, cond.Field)
+		}
+		known := false
+		for _, op := range ops {
+			if op == cond.Op {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("condition %d: operator %q is not supported for %s", i+1, cond.Op, cond.Field)
+		}
+		switch cond.Field {
+		case "difficulty", "attempts", "wrongWithinDays":
+			if _, ok := smartNumber(cond.Value); !ok {
+				return fmt.Errorf("condition %d: %s needs a numeric value", i+1, cond.Field)
+			}
+		case "markedWrong":
+			if _, ok := cond.Value.(bool); !ok {
+				return fmt.Errorf("condition %d: markedWrong needs a true or false value", i+1)
+			}
+		default:
+			if smartString(cond.Value) == "" {
+				return fmt.Errorf("condition %d: %s needs a value", i+1, cond.Field)
+			}
+		}
+	}
+	return nil
+}
+
+// smartNumber converts a JSON-decoded condition value to a number
+func smartNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// smartString converts a condition value to a string
+func smartString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// compareSmartNumber applies a numeric comparison operator
+func compareSmartNumber(actual float64, op string, expected float64) bool {
+	switch op {
+	case "eq":
+		return actual == expected
+	case "neq":
+		return actual != expected
+	case "gt":
+		return actual > expected
+	case "gte":
+		return actual >= expected
+	case "lt":
+		return actual < expected
+	case "lte":
+		return actual <= expected
+	}
+	return false
+}
+
+// smartQueryContext holds the data smart queries are evaluated against. The answer
+// history is loaded once, on the first evaluation, and group memberships, smart or
+// static, are resolved once each.
+type smartQueryContext struct {
+	app       *App
+	now       time.Time
+	loaded    bool
+	questions []Question
+	attempts  map[string][]questionAttempt
+	wrong     map[string]WrongQuestion
+	queries   map[string]*SmartGroupQuery // Smart queries by group ID
+	static    map[string][]string         // Stored members of static groups
+	members   map[string][]string         // Resolved members by group ID
+	memberSet map[string]map[string]bool
+	resolving map[string]bool // Smart groups being resolved, to detect cycles
+}
+
+// loadSmartQueryContext gathers the groups smart queries may refer to
+func (a *App) loadSmartQueryContext() (*smartQueryContext, error) {
+	groups, err := a.db.GetQuestionGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load groups: %v", err)
+	}
+	return a.newSmartQueryContext(groups), nil
+}
+
+// newSmartQueryContext creates a context over groups already loaded
+func (a *App) newSmartQueryContext(groups []QuestionGroup) *smartQueryContext {
+	ctx := &smartQueryContext{
+		app:       a,
+		now:       a.now(),
+		queries:   make(map[string]*SmartGroupQuery),
+		static:    make(map[string][]string),
+		members:   make(map[string][]string),
+		memberSet: make(map[string]map[string]bool),
+		resolving: make(map[string]bool),
+	}
+	for _, group := range groups {
+		if group.SmartQuery != nil {
+			ctx.queries[group.ID] = group.SmartQuery
+		} else {
+			ctx.static[group.ID] = group.QuestionIds
+		}
+	}
+	return ctx
+}
+
+// loadHistory loads the questions, attempts and wrong questions queries match against
+func (ctx *smartQueryContext) loadHistory() error {
+	if ctx.loaded {
+		return nil
+	}
+	questions, err := ctx.app.db.GetQuestions()
+	if err != nil {
+		return fmt.Errorf("failed to get questions: %v", err)
+	}
+	attempts, err := ctx.app.db.GetQuestionAttempts()
+	if err != nil {
+		return fmt.Errorf("failed to load attempts: %v", err)
+	}
+	wrongQuestions, err := ctx.app.db.GetWrongQuestions()
+	if err != nil {
+		return fmt.Errorf("failed to load wrong questions: %v", err)
+	}
+	ctx.questions = questions
+	ctx.attempts = groupAttemptsByQuestion(attempts)
+	ctx.wrong = make(map[string]WrongQuestion, len(wrongQuestions))
+	for _, wq := range wrongQuestions {
+		ctx.wrong[wq.QuestionID] = wq
+	}
+	ctx.loaded = true
+	return nil
+}
+
+// resolveGroup returns the question IDs of a group: the stored members of a static
+// group in the group's order, or the matches of a smart group's query in index order
+func (ctx *smartQueryContext) resolveGroup(groupID string) ([]string, error) {
+	if ids, ok := ctx.members[groupID]; ok {
+		return ids, nil
+	}
+	ids := ctx.static[groupID]
+	if query, ok := ctx.queries[groupID]; ok {
+		if ctx.resolving[groupID] {
+			return nil, fmt.Errorf("smart group %s refers to itself through its group conditions", groupID)
+		}
+		ctx.resolving[groupID] = true
+		questions, err := ctx.evaluate(*query)
+		delete(ctx.resolving, groupID)
+		if err != nil {
+			return nil, err
+		}
+		ids = make([]string, 0, len(questions))
+		for _, q := range questions {
+			ids = append(ids, q.ID)
+		}
+	}
+	if ids == nil {
+		ids = []string{}
+	}
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	ctx.members[groupID] = ids
+	ctx.memberSet[groupID] = set
+	return ids, nil
+}
+
+// evaluate returns the questions matching a query in index order, resolving the groups
+// its conditions refer to first
+func (ctx *smartQueryContext) evaluate(query SmartGroupQuery) ([]Question, error) {
+	if err := ctx.loadHistory(); err != nil {
+		return nil, err
+	}
+	for _, cond := range query.Conditions {
+		if cond.Field == "group" {
+			if _, err := ctx.resolveGroup(smartString(cond.Value)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	matched := make([]Question, 0)
+	for _, q := range ctx.questions {
+		if ctx.matchesSmartQuery(q, query) {
+			matched = append(matched, q)
+		}
+	}
+	return matched, nil
+}
+
+// countGroupTree fills in the question counts of a subtree from the resolved members of
+// its groups and returns the distinct questions of the subtree
+func (ctx *smartQueryContext) countGroupTree(node *GroupTreeNode) (map[string]bool, error) {
+	ids, err := ctx.resolveGroup(node.ID)
+	if err != nil {
+		return nil, err
+	}
+	total := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		total[id] = true
+	}
+	for i := range node.Children {
+		childTotal, err := ctx.countGroupTree(&node.Children[i])
+		if err != nil {
+			return nil, err
+		}
+		for id := range childTotal {
+			total[id] = true
+		}
+	}
+	node.QuestionCount = len(ids)
+	node.TotalQuestionCount = len(total)
+	return total, nil
+}
+
+// matchesSmartCondition reports whether a question satisfies a single condition
+func (ctx *smartQueryContext) matchesSmartCondition(q Question, cond SmartGroupCondition) bool {
+	switch cond.Field {
+	case "tag":
+		path := normalizeTagPath(smartString(cond.Value))
+		has := false
+		for _, tag := range parseQuestionTags(q.Tags) {
+			if isTagPathWithin(tag, path) {
+				has = true
+				break
+			}
+		}
+		if cond.Op == "neq" {
+			return !has
+		}
+		return has
+	case "difficulty":
+		// Questions without a difficulty never match difficulty conditions
+		if q.Difficulty == nil {
+			return false
+		}
+		expected, _ := smartNumber(cond.Value)
+		return compareSmartNumber(float64(*q.Difficulty), cond.Op, expected)
+	case "source":
+		value := strings.ToLower(smartString(cond.Value))
+		source := strings.ToLower(q.Source)
+		switch cond.Op {
+		case "eq":
+			return source == value
+		case "neq":
+			return source != value
+		default:
+			return strings.Contains(source, value)
+		}
+	case "text":
+		return strings.Contains(strings.ToLower(q.Question), strings.ToLower(smartString(cond.Value)))
+	case "group":
+		member := ctx.memberSet[smartString(cond.Value)][q.ID]
+		if cond.Op == "neq" {
+			return !member
+		}
+		return member
+	case "attempts":
+		expected, _ := smartNumber(cond.Value)
+		return compareSmartNumber(float64(len(ctx.attempts[q.ID])), cond.Op, expected)
+	case "wrongWithinDays":
+		days, _ := smartNumber(cond.Value)
+		since := ctx.now.Add(-time.Duration(days * float64(24*time.Hour)))
+		for _, attempt := range ctx.attempts[q.ID] {
+			if !attempt.IsCorrect && !attempt.AnsweredAt.Before(since) {
+				return true
+			}
+		}
+		if wq, ok := ctx.wrong[q.ID]; ok {
+			if added, err := time.Parse(time.RFC3339, wq.AddedAt); err == nil && !added.Before(since) {
+				return true
+			}
+		}
+		return false
+	case "markedWrong":
+		expected, _ := cond.Value.(bool)
+		_, marked := ctx.wrong[q.ID]
+		return marked == expected
+	}
+	return false
+}
+
+// matchesSmartQuery reports whether a question satisfies a query. A query without
+// conditions matches every question.
+func (ctx *smartQueryContext) matchesSmartQuery(q Question, query SmartGroupQuery) bool {
+	if len(query.Conditions) == 0 {
+		return true
+	}
+	anyMatch := query.Match == SmartMatchAny
+	for _, cond := range query.Conditions {
+		matched := ctx.matchesSmartCondition(q, cond)
+		if anyMatch && matched {
+			return true
+		}
+		if !anyMatch && !matched {
+			return false
+		}
+	}
+	return !anyMatch
+}
+
+// evaluateSmartQuery returns the questions matching a smart query in index order
+func (a *App) evaluateSmartQuery(query SmartGroupQuery) ([]Question, error) {
+	if err := validateSmartQuery(query); err != nil {
+		return nil, err
+	}
+	ctx, err := a.loadSmartQueryContext()
+	if err != nil {
+		return nil, err
+	}
+	return ctx.evaluate(query)
+}
+
+// GetGroupSmartQuery returns a group's smart query, or nil for static groups
+func (d *Database) GetGroupSmartQuery(groupID string) (*SmartGroupQuery, error) {
+	var value sql.NullString
+	err := d.db.QueryRow(`SELECT smart_query FROM question_groups WHERE id = ?`, groupID).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalSmartQuery(value)
+}
+
+// SetGroupSmartQuery stores a group's smart query
+func (d *Database) SetGroupSmartQuery(groupID string, query *SmartGroupQuery, at time.Time) error {
+	value, err := marshalSmartQuery(query)
+	if err != nil {
+		return err
+	}
+	_, err = d.db.Exec(`UPDATE question_groups SET smart_query = ?, updated_at = ? WHERE id = ?`,
+		value, at.Format(time.RFC3339), groupID)
+	return err
+}
+
+// groupQuestions returns the questions of a group, evaluating smart groups on demand
+func (a *App) groupQuestions(groupID string) ([]Question, error) {
+	query, err := a.db.GetGroupSmartQuery(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load group query: %v", err)
+	}
+	if query != nil {
+		return a.evaluateSmartQuery(*query)
+	}
+	return a.db.GetQuestionsByGroup(groupID)
+}
+
+// scopeQuestions returns the questions of a group, or the whole bank when groupID is empty
+func (a *App) scopeQuestions(groupID string) ([]Question, error) {
+	if groupID == "" {
+		return a.db.GetQuestions()
+	}
+	return a.groupQuestions(groupID)
+}
+
+// Smart group methods
+
+// CreateSmartGroup creates a group whose questions are chosen by a saved query
+func (a *App) CreateSmartGroup(name, description, parentID, color, icon string, query SmartGroupQuery) (*QuestionGroup, error) {
+	if err := validateSmartQuery(query); err != nil {
+		return nil, fmt.Errorf("invalid smart query: %v", err)
+	}
+
+	group, err := a.newQuestionGroup(name, description, parentID, color, icon)
+	if err != nil {
+		return nil, err
+	}
+	group.SmartQuery = &query
+
+	scopes := []journalScope{{table: "question_groups", where: "id = ?", args: []interface{}{group.ID}}}
+	err = a.journaled("Create smart group "+name, scopes, func() error {
+		return a.db.CreateQuestionGroup(group)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// UpdateSmartGroupQuery replaces the query of a smart group
+func (a *App) UpdateSmartGroupQuery(groupID string, query SmartGroupQuery) error {
+	if err := validateSmartQuery(query); err != nil {
+		return fmt.Errorf("invalid smart query: %v", err)
+	}
+	existing, err := a.db.GetGroupSmartQuery(groupID)
+	if err != nil {
+		return fmt.Errorf("failed to load group query: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("group %s is not a smart group", groupID)
+	}
+
+	scopes := []journalScope{{table: "question_groups", where: "id = ?", args: []interface{}{groupID}}}
+	return a.journaled("Update smart group query", scopes, func() error {
+		if err := a.db.SetGroupSmartQuery(groupID, &query, a.now()); err != nil {
+			return fmt.Errorf("failed to update smart group query: %v", err)
+		}
+		return nil
+	})
+}
+
+// PreviewSmartGroup returns the questions a smart query currently matches without saving it
+func (a *App) PreviewSmartGroup(query SmartGroupQuery) ([]Question, error) {
+	return a.evaluateSmartQuery(query)
+}
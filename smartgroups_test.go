@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestSmartGroupEvaluation tests smart group queries against tags, difficulty and recent mistakes
+func TestSmartGroupEvaluation(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	data := []map[string]interface{}{
+		{"question": "Q1", "options": []string{"a"}, "answer": []string{"a"}, "tags": []string{"Cardiology/ECG"}, "difficulty": float64(4)},
+		{"question": "Q2", "options": []string{"a"}, "answer": []string{"a"}, "tags": []string{"Cardiology"}, "difficulty": float64(5)},
+		{"question": "Q3", "options": []string{"a"}, "answer": []string{"a"}, "tags": []string{"Cardiology"}, "difficulty": float64(2)},
+		{"question": "Q4", "options": []string{"a"}, "answer": []string{"a"}, "tags": []string{"Neurology"}, "difficulty": float64(5)},
+	}
+	if result := app.ImportQuestions(data, ""); !result.Success {
+		t.Fatalf("Import failed: %v", result)
+	}
+	questions, _ := app.GetQuestions()
+	ids := make(map[string]string)
+	for _, q := range questions {
+		ids[q.Question] = q.ID
+	}
+
+	// Q1 was answered wrong recently, Q2 only long ago
+	recordSession := func(id string, at time.Time, questionID string) {
+		details, _ := json.Marshal([]map[string]interface{}{
+			{"questionId": questionID, "userAnswer": []string{"b"}, "isCorrect": false, "timeSpent": 10},
+		})
+		end := at.Format(time.RFC3339)
+		session := &PracticeSession{ID: id, Mode: "practice", StartTime: end, EndTime: &end,
+			TotalQuestions: 1, Details: details, CreatedAt: end}
+		if err := db.CreatePracticeSession(session); err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+	}
+	recordSession("s1", time.Now().AddDate(0, 0, -3), ids["Q1"])
+	recordSession("s2", time.Now().AddDate(0, 0, -90), ids["Q2"])
+
+	query := SmartGroupQuery{
+		Match: SmartMatchAll,
+		Conditions: []SmartGroupCondition{
+			{Field: "tag", Op: "eq", Value: "Cardiology"},
+			{Field: "difficulty", Op: "gte", Value: 4},
+		},
+	}
+	group, err := app.CreateSmartGroup("Hard cardiology", "", "", "#1890ff", "filter", query)
+	if err != nil {
+		t.Fatalf("Failed to create smart group: %v", err)
+	}
+
+	got, err := app.GetQuestionsByGroup(group.ID)
+	if err != nil {
+		t.Fatalf("Failed to evaluate smart group: %v", err)
+	}
+	if texts := questionTexts(got); len(texts) != 2 || texts[0] != "Q1" || texts[1] != "Q2" {
+		t.Errorf("Expected [Q1 Q2], got %v", texts)
+	}
+
+	query.Conditions = append(query.Conditions, SmartGroupCondition{Field: "wrongWithinDays", Op: "within", Value: 30})
+	if err := app.UpdateSmartGroupQuery(group.ID, query); err != nil {
+		t.Fatalf("Failed to update smart group: %v", err)
+	}
+	got, _ = app.GetQuestionsByGroup(group.ID)
+	if texts := questionTexts(got); len(texts) != 1 || texts[0] != "Q1" {
+		t.Errorf("Expected [Q1], got %v", texts)
+	}
+
+	// Counts are visible through GetQuestionGroups and usable for sessions and exports
+	groups, _ := app.GetQuestionGroups()
+	if len(groups) != 1 || groups[0].SmartQuery == nil || len(groups[0].QuestionIds) != 1 {
+		t.Fatalf("Expected smart group with one question, got %+v", groups)
+	}
+	session, err := app.CreatePracticeSession(group.ID, "practice", 0)
+	if err != nil || session.TotalQuestions != 1 {
+		t.Errorf("Expected session over 1 question, got %+v (%v)", session, err)
+	}
+	if _, err := app.ExportGroupAsCSV(group.ID); err != nil {
+		t.Errorf("Failed to export smart group: %v", err)
+	}
+
+	// "any" matching and invalid queries
+	anyQuery := SmartGroupQuery{Match: SmartMatchAny, Conditions: []SmartGroupCondition{
+		{Field: "tag", Op: "eq", Value: "Neurology"},
+		{Field: "difficulty", Op: "lt", Value: 3},
+	}}
+	preview, err := app.PreviewSmartGroup(anyQuery)
+	if err != nil {
+		t.Fatalf("Failed to preview smart group: %v", err)
+	}
+	if texts := questionTexts(preview); len(texts) != 2 || texts[0] != "Q3" || texts[1] != "Q4" {
+		t.Errorf("Expected [Q3 Q4], got %v", texts)
+	}
+	bad := SmartGroupQuery{Conditions: []SmartGroupCondition{{Field: "difficulty", Op: "contains", Value: 3}}}
+	if _, err := app.CreateSmartGroup("Bad", "", "", "", "", bad); err == nil {
+		t.Error("Expected invalid operator to be rejected")
+	}
+}
+
+// TestSmartGroupsInTreeAndConditions tests smart group counts in the tree and group
+// conditions that refer to smart groups
+func TestSmartGroupsInTreeAndConditions(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	data := []map[string]interface{}{
+		{"question": "Q1", "options": []string{"a"}, "answer": []string{"a"}, "tags": []string{"Cardiology"}, "difficulty": float64(4)},
+		{"question": "Q2", "options": []string{"a"}, "answer": []string{"a"}, "tags": []string{"Cardiology"}, "difficulty": float64(2)},
+		{"question": "Q3", "options": []string{"a"}, "answer": []string{"a"}, "tags": []string{"Neurology"}, "difficulty": float64(4)},
+	}
+	app.ImportQuestions(data, "")
+
+	parent, _ := app.CreateQuestionGroup("Parent", "", "", "", "")
+	cardiology, err := app.CreateSmartGroup("Cardiology", "", parent.ID, "", "", SmartGroupQuery{
+		Conditions: []SmartGroupCondition{{Field: "tag", Op: "eq", Value: "Cardiology"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create smart group: %v", err)
+	}
+	hard, err := app.CreateSmartGroup("Hard cardiology", "", parent.ID, "", "", SmartGroupQuery{
+		Conditions: []SmartGroupCondition{
+			{Field: "group", Op: "eq", Value: cardiology.ID},
+			{Field: "difficulty", Op: "gte", Value: 4},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create smart group: %v", err)
+	}
+
+	got, _ := app.GetQuestionsByGroup(hard.ID)
+	if texts := questionTexts(got); len(texts) != 1 || texts[0] != "Q1" {
+		t.Errorf("Expected the group condition to resolve the smart group, got %v", texts)
+	}
+
+	tree, err := app.GetGroupTree("")
+	if err != nil {
+		t.Fatalf("Failed to get group tree: %v", err)
+	}
+	if len(tree) != 1 || tree[0].TotalQuestionCount != 2 || len(tree[0].Children) != 2 {
+		t.Fatalf("Expected the parent to count its smart children, got %+v", tree)
+	}
+	counts := make(map[string]int)
+	for _, child := range tree[0].Children {
+		counts[child.ID] = child.QuestionCount
+	}
+	if counts[cardiology.ID] != 2 || counts[hard.ID] != 1 {
+		t.Errorf("Expected smart group counts 2 and 1, got %v", counts)
+	}
+
+	// A group condition cycle is an error rather than endless recursion
+	if err := app.UpdateSmartGroupQuery(cardiology.ID, SmartGroupQuery{
+		Conditions: []SmartGroupCondition{{Field: "group", Op: "neq", Value: hard.ID}},
+	}); err != nil {
+		t.Fatalf("Failed to update smart group: %v", err)
+	}
+	if _, err := app.GetQuestionsByGroup(hard.ID); err == nil {
+		t.Error("Expected a cycle between smart groups to be reported")
+	}
+}
@@ -0,0 +1,488 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// calendarDateLayout formats calendar dates in study plans and analytics
+const calendarDateLayout = "2006-01-02"
+
+// Study plan parameters
+const (
+	studyPlanReviewShare    = 0.2 // Share of the final days kept for review only
+	defaultReminderTime     = "09:00"
+	studyPlanCalendarDomain = "exammaster"
+	calendarLineOctets      = 75 // Longest iCalendar content line before folding
+)
+
+// studyPlanDate returns the calendar date of a time in local time
+func studyPlanDate(t time.Time) string {
+	return t.In(time.Local).Format(calendarDateLayout)
+}
+
+// scheduleStudyDays lays out new material and reviews from start up to the day before
+// the exam. New questions are spread evenly over the days before the final review
+// stretch; the rest of each day's capacity goes to reviews of wrong questions and of
+// material introduced on earlier days. It returns the days and the number of new
+// questions that do not fit.
+func scheduleStudyDays(start, exam time.Time, newIDs []string, reviewable, capacity int, secondsPerQuestion float64) ([]StudyPlanDay, int) {
+	dayCount := int(exam.Sub(start).Hours()/24 + 0.5)
+	if dayCount <= 0 {
+		return []StudyPlanDay{}, len(newIDs)
+	}
+	newDays := dayCount - int(float64(dayCount)*studyPlanReviewShare)
+	if newDays < 1 {
+		newDays = 1
+	}
+
+	days := make([]StudyPlanDay, 0, dayCount)
+	remaining := newIDs
+	for i := 0; i < dayCount; i++ {
+		day := StudyPlanDay{Date: start.AddDate(0, 0, i).Format(calendarDateLayout), NewQuestionIDs: []string{}}
+		if i < newDays && len(remaining) > 0 {
+			count := int(math.Ceil(float64(len(remaining)) / float64(newDays-i)))
+			if count > capacity {
+				count = capacity
+			}
+			day.NewQuestionIDs = remaining[:count]
+			remaining = remaining[count:]
+		}
+		day.ReviewQuestions = capacity - len(day.NewQuestionIDs)
+		if day.ReviewQuestions > reviewable {
+			day.ReviewQuestions = reviewable
+		}
+		reviewable += len(day.NewQuestionIDs)
+		day.PlannedQuestions = len(day.NewQuestionIDs) + day.ReviewQuestions
+		if day.PlannedQuestions > 0 {
+			day.PlannedMinutes = estimateMinutes(day.PlannedQuestions, secondsPerQuestion)
+		}
+		days = append(days, day)
+	}
+	return days, len(remaining)
+}
+
+// escapeCalendarText escapes a value for an iCalendar text property
+func escapeCalendarText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(value)
+}
+
+// foldCalendarLine folds a content line into lines of at most 75 octets, each
+// continuation starting with a space, without splitting a UTF-8 sequence
+func foldCalendarLine(line string) string {
+	var b strings.Builder
+	limit := calendarLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = calendarLineOctets - 1
+	}
+	b.WriteString(line)
+	return b.String()
+}
+
+// Study plan database methods
+
+// SaveStudyPlan inserts or updates a study plan
+func (d *Database) SaveStudyPlan(plan *StudyPlan) error {
+	stateJSON, err := json.Marshal(plan)
+	if err != nil {
+		return err
+	}
+	_, err = d.db.Exec(`INSERT INTO study_plans (id, status, state, created_at, updated_at) VALUES (?, ?, ?, ?, ?)
+			  ON CONFLICT(id) DO UPDATE SET status = excluded.status, state = excluded.state, updated_at = excluded.updated_at`,
+		plan.ID, plan.Status, string(stateJSON), plan.CreatedAt, plan.UpdatedAt)
+	return err
+}
+
+// GetStudyPlan loads a study plan
+func (d *Database) GetStudyPlan(planID string) (*StudyPlan, error) {
+	var stateJSON string
+	err := d.db.QueryRow(`SELECT state FROM study_plans WHERE id = ?`, planID).Scan(&stateJSON)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("study plan %s not found", planID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var plan StudyPlan
+	if err := json.Unmarshal([]byte(stateJSON), &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse study plan: %v", err)
+	}
+	return &plan, nil
+}
+
+// GetStudyPlans returns all study plans, newest first
+func (d *Database) GetStudyPlans() ([]StudyPlan, error) {
+	rows, err := d.db.Query(`SELECT state FROM study_plans ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var plans []StudyPlan
+	for rows.Next() {
+		var stateJSON string
+		if err := rows.Scan(&stateJSON); err != nil {
+			return nil, err
+		}
+		var plan StudyPlan
+		if err := json.Unmarshal([]byte(stateJSON), &plan); err != nil {
+			return nil, fmt.Errorf("failed to parse study plan: %v", err)
+		}
+		plans = append(plans, plan)
+	}
+	return plans, rows.Err()
+}
+
+// DeleteStudyPlan deletes a study plan
+func (d *Database) DeleteStudyPlan(planID string) error {
+	_, err := d.db.Exec(`DELETE FROM study_plans WHERE id = ?`, planID)
+	return err
+}
+
+// Study plan methods
+
+// CreateStudyPlan generates a day-by-day schedule from today until the exam date over
+// the questions of the selected groups, or the whole bank when none are selected
+func (a *App) CreateStudyPlan(options StudyPlanOptions) (*StudyPlan, error) {
+	now := a.now()
+	exam, err := time.ParseInLocation(calendarDateLayout, options.ExamDate, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exam date %q: %v", options.ExamDate, err)
+	}
+	if options.ExamDate <= studyPlanDate(now) {
+		return nil, fmt.Errorf("exam date %s must be after today", options.ExamDate)
+	}
+	if options.MinutesPerDay < 0 {
+		return nil, fmt.Errorf("minutes per day must not be negative")
+	}
+
+	attempts, err := a.db.GetQuestionAttempts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question attempts: %v", err)
+	}
+	secondsPerQuestion := averageSecondsPerQuestion(attempts)
+	minutes := options.MinutesPerDay
+	if minutes == 0 {
+		minutes = estimateMinutes(a.studyGoalSetting(), secondsPerQuestion)
+	}
+
+	name := strings.TrimSpace(options.Name)
+	if name == "" {
+		name = "Exam on " + options.ExamDate
+	}
+	groupIDs := options.GroupIDs
+	if groupIDs == nil {
+		groupIDs = []string{}
+	}
+	plan := &StudyPlan{
+		ID:                 fmt.Sprintf("plan_%d_%d", now.UnixNano(), rand.Int63()),
+		Name:               name,
+		Status:             StudyPlanActive,
+		ExamDate:           exam.Format(calendarDateLayout),
+		GroupIDs:           groupIDs,
+		MinutesPerDay:      minutes,
+		SecondsPerQuestion: secondsPerQuestion,
+		Days:               []StudyPlanDay{},
+		CreatedAt:          now.Format(time.RFC3339),
+	}
+	scopes := []journalScope{{table: "study_plans", where: "id = ?", args: []interface{}{plan.ID}}}
+	err = a.journaled("Create study plan "+name, scopes, func() error {
+		return a.updateStudyPlan(plan, attempts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// planQuestions returns the IDs of the questions a plan covers
+func (a *App) planQuestions(plan *StudyPlan) ([]string, error) {
+	scopes := plan.GroupIDs
+	if len(scopes) == 0 {
+		scopes = []string{""}
+	}
+	seen := make(map[string]bool)
+	var ids []string
+	for _, groupID := range scopes {
+		questions, err := a.practiceQuestions(groupID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get questions: %v", err)
+		}
+		for _, q := range questions {
+			if !seen[q.ID] {
+				seen[q.ID] = true
+				ids = append(ids, q.ID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// updateStudyPlan brings a study plan up to date and saves it
+func (a *App) updateStudyPlan(plan *StudyPlan, attempts []questionAttempt) error {
+	if err := a.recomputeStudyPlan(plan, attempts); err != nil {
+		return err
+	}
+	if err := a.db.SaveStudyPlan(plan); err != nil {
+		return fmt.Errorf("failed to save study plan: %v", err)
+	}
+	return nil
+}
+
+// recomputeStudyPlan records the work done on each day, reschedules the days after
+// today around what is left and works out the pace without saving the plan. Days up
+// to today keep their schedule.
+func (a *App) recomputeStudyPlan(plan *StudyPlan, attempts []questionAttempt) error {
+	now := a.now()
+	today := studyPlanDate(now)
+	questionIDs, err := a.planQuestions(plan)
+	if err != nil {
+		return err
+	}
+	inPlan := make(map[string]bool, len(questionIDs))
+	for _, id := range questionIDs {
+		inPlan[id] = true
+	}
+
+	attempted := make(map[string]bool)
+	questionsByDate := make(map[string]int)
+	secondsByDate := make(map[string]int)
+	for _, attempt := range attempts {
+		if !inPlan[attempt.QuestionID] {
+			continue
+		}
+		attempted[attempt.QuestionID] = true
+		date := studyPlanDate(attempt.AnsweredAt)
+		questionsByDate[date]++
+		secondsByDate[date] += attempt.TimeSpent
+	}
+
+	// Keep the schedule of past days and today, once planned
+	var kept []StudyPlanDay
+	pending := make(map[string]bool)
+	for _, day := range plan.Days {
+		if day.Date > today {
+			break
+		}
+		kept = append(kept, day)
+		if day.Date == today {
+			for _, id := range day.NewQuestionIDs {
+				pending[id] = true
+			}
+		}
+	}
+
+	if plan.ExamDate <= today {
+		plan.Status = StudyPlanCompleted
+	} else {
+		var newIDs []string
+		for _, id := range questionIDs {
+			if !attempted[id] && !pending[id] {
+				newIDs = append(newIDs, id)
+			}
+		}
+		reviewable, err := a.db.GetWrongQuestions()
+		if err != nil {
+			return fmt.Errorf("failed to get wrong questions: %v", err)
+		}
+		reviews := len(pending)
+		for _, wq := range reviewable {
+			if inPlan[wq.QuestionID] {
+				reviews++
+			}
+		}
+
+		start, _ := time.ParseInLocation(calendarDateLayout, today, time.Local)
+		if len(kept) > 0 && kept[len(kept)-1].Date == today {
+			start = start.AddDate(0, 0, 1)
+		}
+		exam, _ := time.ParseInLocation(calendarDateLayout, plan.ExamDate, time.Local)
+		capacity := int(float64(plan.MinutesPerDay) * 60 / plan.SecondsPerQuestion)
+		if capacity < 1 {
+			capacity = 1
+		}
+		days, unscheduled := scheduleStudyDays(start, exam, newIDs, reviews, capacity, plan.SecondsPerQuestion)
+		kept = append(kept, days...)
+		plan.Unscheduled = unscheduled
+	}
+
+	plan.PlannedToDate, plan.CompletedToDate = 0, 0
+	todayPlanned := 0
+	for i := range kept {
+		day := &kept[i]
+		day.CompletedQuestions = questionsByDate[day.Date]
+		day.CompletedMinutes = int(math.Round(float64(secondsByDate[day.Date]) / 60))
+		switch {
+		case day.Date < today:
+			plan.PlannedToDate += day.PlannedQuestions
+			plan.CompletedToDate += day.CompletedQuestions
+		case day.Date == today:
+			todayPlanned = day.PlannedQuestions
+			plan.CompletedToDate += day.CompletedQuestions
+		}
+	}
+	plan.Days = kept
+	if plan.Days == nil {
+		plan.Days = []StudyPlanDay{}
+	}
+
+	switch {
+	case plan.CompletedToDate < plan.PlannedToDate:
+		plan.Pace = StudyPaceBehind
+	case plan.CompletedToDate > plan.PlannedToDate+todayPlanned:
+		plan.Pace = StudyPaceAhead
+	default:
+		plan.Pace = StudyPaceOnTrack
+	}
+	plan.UpdatedAt = now.Format(time.RFC3339)
+	return nil
+}
+
+// refreshStudyPlans brings the active study plans up to date after a session is saved.
+// Failures are logged since the session itself has already been saved.
+func (a *App) refreshStudyPlans() {
+	plans, err := a.db.GetStudyPlans()
+	if err != nil {
+		log.Printf("Warning: Failed to load study plans: %v", err)
+		return
+	}
+	if len(plans) == 0 {
+		return
+	}
+	attempts, err := a.db.GetQuestionAttempts()
+	if err != nil {
+		log.Printf("Warning: Failed to get question attempts: %v", err)
+		return
+	}
+	for i := range plans {
+		if plans[i].Status != StudyPlanActive {
+			continue
+		}
+		if err := a.updateStudyPlan(&plans[i], attempts); err != nil {
+			log.Printf("Warning: Failed to update study plan %s: %v", plans[i].ID, err)
+		}
+	}
+}
+
+// GetStudyPlan returns a study plan with up-to-date progress and pace. The plan is
+// recomputed for the read only; it is saved when sessions are.
+func (a *App) GetStudyPlan(planID string) (*StudyPlan, error) {
+	plan, err := a.db.GetStudyPlan(planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Status != StudyPlanActive {
+		return plan, nil
+	}
+	attempts, err := a.db.GetQuestionAttempts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question attempts: %v", err)
+	}
+	if err := a.recomputeStudyPlan(plan, attempts); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// GetStudyPlans returns all study plans, newest first
+func (a *App) GetStudyPlans() ([]StudyPlan, error) {
+	plans, err := a.db.GetStudyPlans()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get study plans: %v", err)
+	}
+	if plans == nil {
+		plans = []StudyPlan{}
+	}
+	return plans, nil
+}
+
+// DeleteStudyPlan deletes a study plan
+func (a *App) DeleteStudyPlan(planID string) error {
+	scopes := []journalScope{{table: "study_plans", where: "id = ?", args: []interface{}{planID}}}
+	return a.journaled("Delete study plan", scopes, func() error {
+		return a.db.DeleteStudyPlan(planID)
+	})
+}
+
+// ExportStudyPlanICS exports the remaining days of a study plan and the exam itself as
+// an iCalendar file. Study blocks start at the reminderTime setting and carry an alarm
+// when the dailyReminder setting is on.
+func (a *App) ExportStudyPlanICS(planID string) (string, error) {
+	plan, err := a.GetStudyPlan(planID)
+	if err != nil {
+		return "", err
+	}
+
+	reminderTime := defaultReminderTime
+	if value, err := a.db.GetSetting("reminderTime"); err == nil {
+		var configured string
+		if json.Unmarshal(value, &configured) == nil {
+			if _, err := time.Parse("15:04", configured); err == nil {
+				reminderTime = configured
+			}
+		}
+	}
+	reminder := false
+	if value, err := a.db.GetSetting("dailyReminder"); err == nil {
+		json.Unmarshal(value, &reminder)
+	}
+
+	stamp := a.now().UTC().Format("20060102T150405Z")
+	startTime := strings.Replace(reminderTime, ":", "", 1) + "00"
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		b.WriteString(foldCalendarLine(fmt.Sprintf(format, args...)))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//ExamMaster//Study Plan//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:%s", escapeCalendarText(plan.Name))
+	today := studyPlanDate(a.now())
+	for _, day := range plan.Days {
+		if day.Date < today || day.PlannedQuestions == 0 {
+			continue
+		}
+		date := strings.ReplaceAll(day.Date, "-", "")
+		line("BEGIN:VEVENT")
+		line("UID:%s-%s@%s", plan.ID, date, studyPlanCalendarDomain)
+		line("DTSTAMP:%s", stamp)
+		line("DTSTART:%sT%s", date, startTime)
+		line("DURATION:PT%dM", day.PlannedMinutes)
+		line("SUMMARY:%s", escapeCalendarText(fmt.Sprintf("Study: %d new, %d review", len(day.NewQuestionIDs), day.ReviewQuestions)))
+		line("DESCRIPTION:%s", escapeCalendarText(fmt.Sprintf("%s\n%d questions, about %d minutes", plan.Name, day.PlannedQuestions, day.PlannedMinutes)))
+		if reminder {
+			line("BEGIN:VALARM")
+			line("ACTION:DISPLAY")
+			line("DESCRIPTION:%s", escapeCalendarText("Time to study for "+plan.Name))
+			line("TRIGGER:PT0M")
+			line("END:VALARM")
+		}
+		line("END:VEVENT")
+	}
+	exam := strings.ReplaceAll(plan.ExamDate, "-", "")
+	line("BEGIN:VEVENT")
+	line("UID:%s-exam@%s", plan.ID, studyPlanCalendarDomain)
+	line("DTSTAMP:%s", stamp)
+	line("DTSTART;VALUE=DATE:%s", exam)
+	line("SUMMARY:%s", escapeCalendarText(plan.Name))
+	line("END:VEVENT")
+	line("END:VCALENDAR")
+	return b.String(), nil
+}
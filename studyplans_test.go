@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// TestScheduleStudyDays tests spreading new material and filling days with reviews
+func TestScheduleStudyDays(t *testing.T) {
+	start := time.Date(2025, 6, 10, 0, 0, 0, 0, time.Local)
+	ids := []string{"q1", "q2", "q3", "q4", "q5", "q6", "q7", "q8", "q9", "q10"}
+	days, unscheduled := scheduleStudyDays(start, start.AddDate(0, 0, 5), ids, 0, 5, 60)
+	if len(days) != 5 || unscheduled != 0 {
+		t.Fatalf("Expected 5 days with everything scheduled, got %d days and %d left", len(days), unscheduled)
+	}
+	expected := [][2]int{{3, 0}, {3, 2}, {2, 3}, {2, 3}, {0, 5}}
+	for i, day := range days {
+		if len(day.NewQuestionIDs) != expected[i][0] || day.ReviewQuestions != expected[i][1] || day.PlannedMinutes != day.PlannedQuestions {
+			t.Errorf("Day %d: expected %v new/review, got %+v", i+1, expected[i], day)
+		}
+	}
+	if days[0].Date != "2025-06-10" || days[4].Date != "2025-06-14" {
+		t.Errorf("Expected the days before the exam, got %s to %s", days[0].Date, days[4].Date)
+	}
+
+	if _, unscheduled := scheduleStudyDays(start, start.AddDate(0, 0, 2), ids, 0, 3, 60); unscheduled != 4 {
+		t.Errorf("Expected 4 questions not to fit, got %d", unscheduled)
+	}
+}
+
+// TestStudyPlanProgress tests plan creation, pace tracking, rescheduling and .ics export
+func TestStudyPlanProgress(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	now := time.Date(2025, 6, 10, 8, 0, 0, 0, time.Local)
+	app.clock = func() time.Time { return now }
+
+	var data []map[string]interface{}
+	for i := 1; i <= 10; i++ {
+		data = append(data, map[string]interface{}{"question": fmt.Sprintf("Q%d", i), "options": []string{}, "answer": []string{"a"}})
+	}
+	app.ImportQuestions(data, "")
+
+	if _, err := app.CreateStudyPlan(StudyPlanOptions{ExamDate: "2025-06-10"}); err == nil {
+		t.Error("Expected an exam date of today to be rejected")
+	}
+	if _, err := app.CreateStudyPlan(StudyPlanOptions{ExamDate: "June 15"}); err == nil {
+		t.Error("Expected an invalid exam date to be rejected")
+	}
+
+	plan, err := app.CreateStudyPlan(StudyPlanOptions{ExamDate: "2025-06-15", MinutesPerDay: 5})
+	if err != nil {
+		t.Fatalf("Failed to create study plan: %v", err)
+	}
+	if len(plan.Days) != 5 || len(plan.Days[0].NewQuestionIDs) != 3 || plan.Pace != StudyPaceOnTrack || plan.Name != "Exam on 2025-06-15" {
+		t.Fatalf("Unexpected plan: %+v", plan)
+	}
+
+	// Nothing was done on the first day
+	now = now.AddDate(0, 0, 1)
+	plan, _ = app.GetStudyPlan(plan.ID)
+	if plan.Pace != StudyPaceBehind || plan.PlannedToDate != 3 || plan.CompletedToDate != 0 {
+		t.Errorf("Expected the plan to be behind, got %s (%d/%d)", plan.Pace, plan.CompletedToDate, plan.PlannedToDate)
+	}
+	if saved, _ := db.GetStudyPlan(plan.ID); saved.Pace != StudyPaceOnTrack || saved.UpdatedAt == plan.UpdatedAt {
+		t.Errorf("Expected reading the plan to leave it unsaved, got %s updated at %s", saved.Pace, saved.UpdatedAt)
+	}
+
+	// Answering the whole bank on the second day leaves no new material
+	questions, _ := app.GetQuestions()
+	var records []QuestionRecord
+	for _, q := range questions {
+		records = append(records, QuestionRecord{QuestionID: q.ID, IsCorrect: true, TimeSpent: 60})
+	}
+	details, _ := json.Marshal(records)
+	at := now.Format(time.RFC3339)
+	db.CreatePracticeSession(&PracticeSession{ID: "s1", Mode: "practice", StartTime: at, TotalQuestions: 10, Details: details, CreatedAt: at})
+
+	plan, _ = app.GetStudyPlan(plan.ID)
+	if plan.Pace != StudyPaceAhead || plan.CompletedToDate != 10 || plan.Days[1].CompletedMinutes != 10 {
+		t.Errorf("Expected the plan to be ahead, got %s (%d/%d)", plan.Pace, plan.CompletedToDate, plan.PlannedToDate)
+	}
+	for _, day := range plan.Days[2:] {
+		if len(day.NewQuestionIDs) != 0 {
+			t.Errorf("Expected no new material after everything was answered, got %+v", day)
+		}
+	}
+
+	app.UpdateUserSettings(map[string]interface{}{"dailyReminder": true, "reminderTime": "07:30"})
+	plan.Name = "Boards, part 1 " + strings.Repeat("é", 40)
+	db.SaveStudyPlan(plan)
+	ics, err := app.ExportStudyPlanICS(plan.ID)
+	if err != nil {
+		t.Fatalf("Failed to export study plan: %v", err)
+	}
+	for _, expected := range []string{"BEGIN:VCALENDAR\r\n", "DTSTART:20250611T073000\r\n", "BEGIN:VALARM", "DTSTART;VALUE=DATE:20250615", `Boards\, part 1`} {
+		if !strings.Contains(ics, expected) {
+			t.Errorf("Expected %q in the calendar:\n%s", expected, ics)
+		}
+	}
+	if strings.Contains(ics, "20250610T") {
+		t.Error("Expected past days to be left out of the calendar")
+	}
+	for _, line := range strings.Split(ics, "\r\n") {
+		if len(line) > 75 || !utf8.ValidString(line) {
+			t.Errorf("Expected lines folded at 75 octets, got %q", line)
+		}
+	}
+	if unfolded := strings.ReplaceAll(ics, "\r\n ", ""); !strings.Contains(unfolded, "X-WR-CALNAME:"+escapeCalendarText(plan.Name)+"\r\n") {
+		t.Errorf("Expected the folded name to unfold to the original:\n%s", ics)
+	}
+
+	// Once the exam date has passed the plan is complete
+	now = time.Date(2025, 6, 16, 8, 0, 0, 0, time.Local)
+	if plan, _ = app.GetStudyPlan(plan.ID); plan.Status != StudyPlanCompleted {
+		t.Errorf("Expected the plan to be completed, got %s", plan.Status)
+	}
+}
+
+// TestUndoCreateStudyPlan tests that creating a study plan can be undone and redone
+func TestUndoCreateStudyPlan(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	now := time.Date(2025, 6, 10, 8, 0, 0, 0, time.Local)
+	app.clock = func() time.Time { return now }
+	app.ImportQuestions([]map[string]interface{}{{"question": "Q1", "options": []string{}, "answer": []string{"a"}}}, "")
+
+	plan, err := app.CreateStudyPlan(StudyPlanOptions{ExamDate: "2025-06-15", MinutesPerDay: 5})
+	if err != nil {
+		t.Fatalf("Failed to create study plan: %v", err)
+	}
+	if op, err := app.Undo(); err != nil || op.Label != "Create study plan Exam on 2025-06-15" {
+		t.Fatalf("Expected to undo the plan creation, got %+v (%v)", op, err)
+	}
+	if _, err := db.GetStudyPlan(plan.ID); err == nil {
+		t.Error("Expected the undone plan to be removed")
+	}
+	if _, err := app.Redo(); err != nil {
+		t.Fatalf("Failed to redo plan creation: %v", err)
+	}
+	if _, err := db.GetStudyPlan(plan.ID); err != nil {
+		t.Errorf("Expected the redone plan to be back, got %v", err)
+	}
+}
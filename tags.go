@@ -0,0 +1,546 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// tagPathSeparator separates the levels of a hierarchical tag path
+const tagPathSeparator = "/"
+
+// normalizeTagPath trims each level of a tag path and drops empty levels
+func normalizeTagPath(path string) string {
+	var parts []string
+	for _, part := range strings.Split(path, tagPathSeparator) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, tagPathSeparator)
+}
+
+// tagPathAncestors returns a tag path and all of its ancestor paths, shortest first
+func tagPathAncestors(path string) []string {
+	parts := strings.Split(normalizeTagPath(path), tagPathSeparator)
+	if len(parts) == 1 && parts[0] == "" {
+		return nil
+	}
+	paths := make([]string, len(parts))
+	for i := range parts {
+		paths[i] = strings.Join(parts[:i+1], tagPathSeparator)
+	}
+	return paths
+}
+
+// isTagPathWithin reports whether path equals root or is one of its descendants
+func isTagPathWithin(path, root string) bool {
+	return path == root || strings.HasPrefix(path, root+tagPathSeparator)
+}
+
+// parseQuestionTags decodes a question's JSON tag list into normalized paths
+func parseQuestionTags(tagsJSON json.RawMessage) []string {
+	var raw []interface{}
+	if len(tagsJSON) == 0 || json.Unmarshal(tagsJSON, &raw) != nil {
+		return nil
+	}
+
+	var tags []string
+	seen := make(map[string]bool)
+	for _, item := range raw {
+		tag, ok := item.(string)
+		if !ok {
+			continue
+		}
+		if tag = normalizeTagPath(tag); tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// Tag database methods
+
+// queryer runs statements on either the database or a transaction, so the tag helpers
+// can take part in the transaction of a tag operation
+type queryer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// migrateQuestionTags populates the tag tables from the questions' JSON tags
+// the first time the tag tables are created
+func (d *Database) migrateQuestionTags() error {
+	var tagCount int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM tags`).Scan(&tagCount); err != nil {
+		return err
+	}
+	if tagCount > 0 {
+		return nil
+	}
+
+	rows, err := d.db.Query(`SELECT id, tags FROM questions WHERE tags IS NOT NULL AND tags != '[]' AND tags != 'null'`)
+	if err != nil {
+		return err
+	}
+
+	type questionTags struct {
+		id   string
+		tags json.RawMessage
+	}
+	var pending []questionTags
+	for rows.Next() {
+		var id string
+		var tags sql.NullString
+		if err := rows.Scan(&id, &tags); err != nil {
+			rows.Close()
+			return err
+		}
+		pending = append(pending, questionTags{id: id, tags: handleNullJSON(tags, `[]`)})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	for _, q := range pending {
+		if err := syncQuestionTags(d.db, q.id, q.tags, now); err != nil {
+			return fmt.Errorf("question %s: %v", q.id, err)
+		}
+	}
+	return nil
+}
+
+// ensureTagPath returns the ID of the tag at path, creating it and any missing ancestors
+func ensureTagPath(q queryer, path, now string) (string, error) {
+	var parentID *string
+	var tagID string
+	for _, current := range tagPathAncestors(path) {
+		err := q.QueryRow(`SELECT id FROM tags WHERE path = ?`, current).Scan(&tagID)
+		if err == sql.ErrNoRows {
+			tagID = fmt.Sprintf("tag_%d_%d", time.Now().UnixNano(), rand.Int63())
+			name := current[strings.LastIndex(current, tagPathSeparator)+1:]
+			_, err = q.Exec(`INSERT INTO tags (id, name, parent_id, path, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+				tagID, name, parentID, current, now, now)
+		}
+		if err != nil {
+			return "", err
+		}
+		id := tagID
+		parentID = &id
+	}
+	return tagID, nil
+}
+
+// syncQuestionTags links a question to the tags listed in its JSON tags
+func syncQuestionTags(q queryer, questionID string, tagsJSON json.RawMessage, now string) error {
+	if _, err := q.Exec(`DELETE FROM question_tags WHERE question_id = ?`, questionID); err != nil {
+		return err
+	}
+	for _, path := range parseQuestionTags(tagsJSON) {
+		tagID, err := ensureTagPath(q, path, now)
+		if err != nil {
+			return err
+		}
+		if _, err := q.Exec(`INSERT OR IGNORE INTO question_tags (question_id, tag_id) VALUES (?, ?)`, questionID, tagID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTagByID returns a single tag
+func (d *Database) GetTagByID(tagID string) (*Tag, error) {
+	var t Tag
+	err := d.db.QueryRow(`SELECT id, name, parent_id, path, created_at, updated_at FROM tags WHERE id = ?`, tagID).Scan(
+		&t.ID, &t.Name, &t.ParentID, &t.Path, &t.CreatedAt, &t.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetTagsWithCounts returns all tags with their direct and subtree question counts
+func (d *Database) GetTagsWithCounts() ([]TagWithCount, error) {
+	query := `SELECT t.id, t.name, t.parent_id, t.path,
+				(SELECT COUNT(*) FROM question_tags qt WHERE qt.tag_id = t.id),
+				(SELECT COUNT(DISTINCT qt.question_id) FROM question_tags qt
+				 JOIN tags d ON d.id = qt.tag_id
+				 WHERE d.path = t.path OR substr(d.path, 1, length(t.path) + 1) = t.path || '/')
+			  FROM tags t ORDER BY t.path`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []TagWithCount
+	for rows.Next() {
+		var t TagWithCount
+		if err := rows.Scan(&t.ID, &t.Name, &t.ParentID, &t.Path, &t.QuestionCount, &t.TotalCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+// questionIDsWithinTagPath returns the questions linked to a tag within path
+func questionIDsWithinTagPath(q queryer, path string) ([]string, error) {
+	rows, err := q.Query(`SELECT DISTINCT qt.question_id FROM question_tags qt
+			  JOIN tags t ON t.id = qt.tag_id
+			  WHERE t.path = ? OR substr(t.path, 1, length(?) + 1) = ? || '/'`, path, path, path)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// retagQuestions rewrites the JSON tags of the given questions, replacing the oldPath
+// prefix with newPath, and relinks the questions to their tags
+func retagQuestions(q queryer, questionIDs []string, oldPath, newPath, now string) error {
+	for _, id := range questionIDs {
+		var tags sql.NullString
+		if err := q.QueryRow(`SELECT tags FROM questions WHERE id = ?`, id).Scan(&tags); err != nil {
+			return err
+		}
+
+		retagged := []string{}
+		seen := make(map[string]bool)
+		for _, tag := range parseQuestionTags(handleNullJSON(tags, `[]`)) {
+			if isTagPathWithin(tag, oldPath) {
+				tag = newPath + tag[len(oldPath):]
+			}
+			if !seen[tag] {
+				seen[tag] = true
+				retagged = append(retagged, tag)
+			}
+		}
+		tagsJSON, err := json.Marshal(retagged)
+		if err != nil {
+			return err
+		}
+
+		if _, err := q.Exec(`UPDATE questions SET tags = ?, updated_at = ? WHERE id = ?`, tagsJSON, now, id); err != nil {
+			return err
+		}
+		if err := syncQuestionTags(q, id, tagsJSON, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveTagPath changes the path of a tag subtree after a rename or move
+func moveTagPath(q queryer, tagID, name string, parentID *string, oldPath, newPath, now string) error {
+	var exists int
+	if err := q.QueryRow(`SELECT COUNT(*) FROM tags WHERE path = ?`, newPath).Scan(&exists); err != nil {
+		return err
+	}
+	if exists > 0 {
+		return fmt.Errorf("tag %s already exists", newPath)
+	}
+
+	questionIDs, err := questionIDsWithinTagPath(q, oldPath)
+	if err != nil {
+		return err
+	}
+
+	if _, err := q.Exec(`UPDATE tags SET name = ?, parent_id = ?, updated_at = ? WHERE id = ?`, name, parentID, now, tagID); err != nil {
+		return err
+	}
+	if _, err := q.Exec(`UPDATE tags SET path = ? || substr(path, length(?) + 1)
+			  WHERE path = ? OR substr(path, 1, length(?) + 1) = ? || '/'`,
+		newPath, oldPath, oldPath, oldPath, oldPath); err != nil {
+		return err
+	}
+	return retagQuestions(q, questionIDs, oldPath, newPath, now)
+}
+
+// RenameTag renames a tag, keeping its position in the hierarchy
+func (d *Database) RenameTag(tagID, newName string, at time.Time) (*Tag, error) {
+	tag, err := d.GetTagByID(tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	newPath := newName
+	if i := strings.LastIndex(tag.Path, tagPathSeparator); i >= 0 {
+		newPath = tag.Path[:i+1] + newName
+	}
+	if newPath == tag.Path {
+		return tag, nil
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := moveTagPath(tx, tagID, newName, tag.ParentID, tag.Path, newPath, at.Format(time.RFC3339)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return d.GetTagByID(tagID)
+}
+
+// MoveTag moves a tag and its descendants under a new parent, or to the top level
+// when newParentID is empty
+func (d *Database) MoveTag(tagID, newParentID string, at time.Time) (*Tag, error) {
+	tag, err := d.GetTagByID(tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	newPath := tag.Name
+	var parentID *string
+	if newParentID != "" {
+		parent, err := d.GetTagByID(newParentID)
+		if err != nil {
+			return nil, fmt.Errorf("parent tag not found: %v", err)
+		}
+		if isTagPathWithin(parent.Path, tag.Path) {
+			return nil, fmt.Errorf("cannot move tag %s under itself or its descendant %s", tag.Path, parent.Path)
+		}
+		newPath = parent.Path + tagPathSeparator + tag.Name
+		parentID = &parent.ID
+	}
+	if newPath == tag.Path {
+		return tag, nil
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := moveTagPath(tx, tagID, tag.Name, parentID, tag.Path, newPath, at.Format(time.RFC3339)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return d.GetTagByID(tagID)
+}
+
+// MergeTags merges the source tag and its descendants into the target tag.
+// Questions are relinked to the matching target tags and the source subtree is removed.
+func (d *Database) MergeTags(sourceID, targetID string, at time.Time) (*Tag, error) {
+	source, err := d.GetTagByID(sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("source tag not found: %v", err)
+	}
+	target, err := d.GetTagByID(targetID)
+	if err != nil {
+		return nil, fmt.Errorf("target tag not found: %v", err)
+	}
+	if isTagPathWithin(target.Path, source.Path) {
+		return nil, fmt.Errorf("cannot merge tag %s into itself or its descendant %s", source.Path, target.Path)
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Collect the source subtree before anything is relinked
+	rows, err := tx.Query(`SELECT id, path FROM tags WHERE path = ? OR substr(path, 1, length(?) + 1) = ? || '/'`,
+		source.Path, source.Path, source.Path)
+	if err != nil {
+		return nil, err
+	}
+	subtree := make(map[string]string)
+	for rows.Next() {
+		var id, path string
+		if err := rows.Scan(&id, &path); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		subtree[id] = path
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	now := at.Format(time.RFC3339)
+
+	// Recreate the subtree under the target so unused child tags are kept
+	paths := make([]string, 0, len(subtree))
+	for _, path := range subtree {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if _, err := ensureTagPath(tx, target.Path+path[len(source.Path):], now); err != nil {
+			return nil, err
+		}
+	}
+
+	questionIDs, err := questionIDsWithinTagPath(tx, source.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := retagQuestions(tx, questionIDs, source.Path, target.Path, now); err != nil {
+		return nil, err
+	}
+
+	for id := range subtree {
+		if _, err := tx.Exec(`DELETE FROM question_tags WHERE tag_id = ?`, id); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(`DELETE FROM tags WHERE id = ?`, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return d.GetTagByID(targetID)
+}
+
+// Tag management methods
+
+// ListTagsWithCounts returns every tag with its direct and total question counts
+func (a *App) ListTagsWithCounts() ([]TagWithCount, error) {
+	tags, err := a.db.GetTagsWithCounts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %v", err)
+	}
+	return tags, nil
+}
+
+// RenameTag renames a tag and updates every question that uses it or its descendants
+func (a *App) RenameTag(tagID, newName string) (*Tag, error) {
+	newName = strings.TrimSpace(newName)
+	if newName == "" || strings.Contains(newName, tagPathSeparator) {
+		return nil, fmt.Errorf("invalid tag name %q", newName)
+	}
+
+	questionIDs, err := a.prepareTagRevisions(tagID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rename tag: %v", err)
+	}
+
+	var tag *Tag
+	err = a.journaled("Rename tag to "+newName, tagJournalScopes(), func() error {
+		var err error
+		tag, err = a.db.RenameTag(tagID, newName, a.now())
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to rename tag: %v", err)
+	}
+
+	a.recordTagRevisions(questionIDs, "Renamed tag to "+tag.Path)
+	return tag, nil
+}
+
+// MergeTags merges the source tag into the target tag
+func (a *App) MergeTags(sourceID, targetID string) (*Tag, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("cannot merge a tag into itself")
+	}
+
+	questionIDs, err := a.prepareTagRevisions(sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge tags: %v", err)
+	}
+
+	var tag *Tag
+	err = a.journaled("Merge tags", tagJournalScopes(), func() error {
+		var err error
+		tag, err = a.db.MergeTags(sourceID, targetID, a.now())
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge tags: %v", err)
+	}
+
+	a.recordTagRevisions(questionIDs, "Merged tag into "+tag.Path)
+	return tag, nil
+}
+
+// MoveTag moves a tag under a new parent tag, or to the top level when newParentID is empty
+func (a *App) MoveTag(tagID, newParentID string) (*Tag, error) {
+	questionIDs, err := a.prepareTagRevisions(tagID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move tag: %v", err)
+	}
+
+	var tag *Tag
+	err = a.journaled("Move tag", tagJournalScopes(), func() error {
+		var err error
+		tag, err = a.db.MoveTag(tagID, newParentID, a.now())
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to move tag: %v", err)
+	}
+
+	a.recordTagRevisions(questionIDs, "Moved tag to "+tag.Path)
+	return tag, nil
+}
+
+// prepareTagRevisions returns the questions affected by a change to a tag subtree,
+// making sure each has a baseline revision before its tags are rewritten
+func (a *App) prepareTagRevisions(tagID string) ([]string, error) {
+	tag, err := a.db.GetTagByID(tagID)
+	if err != nil {
+		return nil, fmt.Errorf("tag not found: %v", err)
+	}
+	questionIDs, err := questionIDsWithinTagPath(a.db.db, tag.Path)
+	if err != nil {
+		return nil, err
+	}
+	for _, questionID := range questionIDs {
+		if err := a.ensureQuestionRevisionBaseline(questionID); err != nil {
+			log.Printf("Warning: Failed to record baseline revision for question %s: %v", questionID, err)
+		}
+	}
+	return questionIDs, nil
+}
+
+// recordTagRevisions records the rewritten tags of affected questions in their history
+func (a *App) recordTagRevisions(questionIDs []string, reason string) {
+	for _, questionID := range questionIDs {
+		if err := a.recordQuestionRevision(questionID, RevisionActorUser, reason); err != nil {
+			log.Printf("Warning: Failed to record revision for question %s: %v", questionID, err)
+		}
+	}
+}
+
+// tagJournalScopes covers the tag tables and the questions whose JSON tags mirror them
+func tagJournalScopes() []journalScope {
+	return []journalScope{
+		{table: "tags"},
+		{table: "question_tags"},
+		{table: "questions"},
+	}
+}
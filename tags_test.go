@@ -0,0 +1,210 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+// findTag returns the tag with the given path from a tag listing
+func findTag(tags []TagWithCount, path string) *TagWithCount {
+	for i := range tags {
+		if tags[i].Path == path {
+			return &tags[i]
+		}
+	}
+	return nil
+}
+
+// TestTagHierarchyAndCounts tests that hierarchical tags are created with subtree counts
+func TestTagHierarchyAndCounts(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	data := []map[string]interface{}{
+		{"question": "Q1", "options": []string{"a"}, "answer": []string{"a"}, "tags": []string{"Medicine/Cardiology/Arrhythmia"}},
+		{"question": "Q2", "options": []string{"a"}, "answer": []string{"a"}, "tags": []string{"Medicine/Cardiology", "Statistics"}},
+	}
+	if result := app.ImportQuestions(data, ""); result.Imported != 2 {
+		t.Fatalf("Expected 2 imported questions, got %d (%v)", result.Imported, result.Errors)
+	}
+
+	tags, err := app.ListTagsWithCounts()
+	if err != nil {
+		t.Fatalf("Failed to list tags: %v", err)
+	}
+	if len(tags) != 4 {
+		t.Fatalf("Expected 4 tags, got %+v", tags)
+	}
+
+	medicine := findTag(tags, "Medicine")
+	if medicine == nil || medicine.QuestionCount != 0 || medicine.TotalCount != 2 {
+		t.Errorf("Unexpected Medicine counts: %+v", medicine)
+	}
+	cardiology := findTag(tags, "Medicine/Cardiology")
+	if cardiology == nil || cardiology.QuestionCount != 1 || cardiology.TotalCount != 2 {
+		t.Errorf("Unexpected Cardiology counts: %+v", cardiology)
+	}
+	if cardiology != nil && (cardiology.ParentID == nil || *cardiology.ParentID != medicine.ID) {
+		t.Errorf("Expected Cardiology to be a child of Medicine")
+	}
+}
+
+// TestRenameMergeMoveTags tests that tag edits are reflected in question tags
+func TestRenameMergeMoveTags(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	created, err := app.CreateQuestion(Question{
+		Question: "Tagged question",
+		Options:  json.RawMessage(`[]`),
+		Answer:   json.RawMessage(`["a"]`),
+		Tags:     json.RawMessage(`["Cardio/ECG", "Pulmo"]`),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create question: %v", err)
+	}
+
+	tags, _ := app.ListTagsWithCounts()
+	if _, err := app.RenameTag(findTag(tags, "Cardio").ID, "Cardiology"); err != nil {
+		t.Fatalf("Failed to rename tag: %v", err)
+	}
+	assertQuestionTags(t, app, created.ID, `["Cardiology/ECG","Pulmo"]`)
+
+	tags, _ = app.ListTagsWithCounts()
+	if _, err := app.MoveTag(findTag(tags, "Cardiology").ID, findTag(tags, "Cardiology/ECG").ID); err == nil {
+		t.Error("Expected moving a tag under its own descendant to fail")
+	}
+
+	medicine, err := app.CreateQuestion(Question{
+		Question: "Another question",
+		Options:  json.RawMessage(`[]`),
+		Answer:   json.RawMessage(`["a"]`),
+		Tags:     json.RawMessage(`["Medicine"]`),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create question: %v", err)
+	}
+
+	tags, _ = app.ListTagsWithCounts()
+	if _, err := app.MoveTag(findTag(tags, "Cardiology").ID, findTag(tags, "Medicine").ID); err != nil {
+		t.Fatalf("Failed to move tag: %v", err)
+	}
+	assertQuestionTags(t, app, created.ID, `["Medicine/Cardiology/ECG","Pulmo"]`)
+
+	tags, _ = app.ListTagsWithCounts()
+	if _, err := app.MergeTags(findTag(tags, "Pulmo").ID, findTag(tags, "Medicine").ID); err != nil {
+		t.Fatalf("Failed to merge tags: %v", err)
+	}
+	assertQuestionTags(t, app, created.ID, `["Medicine/Cardiology/ECG","Medicine"]`)
+	assertQuestionTags(t, app, medicine.ID, `["Medicine"]`)
+
+	tags, _ = app.ListTagsWithCounts()
+	if findTag(tags, "Pulmo") != nil {
+		t.Error("Expected merged tag to be removed")
+	}
+	if m := findTag(tags, "Medicine"); m == nil || m.QuestionCount != 2 || m.TotalCount != 2 {
+		t.Errorf("Unexpected Medicine counts after merge: %+v", m)
+	}
+
+	revisions, _ := app.GetQuestionRevisions(created.ID)
+	if len(revisions) != 4 {
+		t.Errorf("Expected tag edits to be recorded as revisions, got %d revisions", len(revisions))
+	}
+}
+
+// TestTagEditRollsBack tests that a tag edit failing partway leaves tags and questions untouched
+func TestTagEditRollsBack(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	created, err := app.CreateQuestion(Question{
+		Question: "Tagged question",
+		Options:  json.RawMessage(`[]`),
+		Answer:   json.RawMessage(`["a"]`),
+		Tags:     json.RawMessage(`["Cardio/ECG"]`),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create question: %v", err)
+	}
+
+	// Fail the question rewrite after the tag paths have been changed
+	if _, err := db.db.Exec(`CREATE TRIGGER fail_retag BEFORE UPDATE OF tags ON questions
+			  BEGIN SELECT RAISE(ABORT, 'retag failed'); END`); err != nil {
+		t.Fatalf("Failed to create trigger: %v", err)
+	}
+	tags, _ := app.ListTagsWithCounts()
+	if _, err := app.RenameTag(findTag(tags, "Cardio").ID, "Cardiology"); err == nil {
+		t.Fatal("Expected the rename to fail")
+	}
+
+	tags, _ = app.ListTagsWithCounts()
+	if findTag(tags, "Cardio/ECG") == nil || findTag(tags, "Cardiology") != nil {
+		t.Errorf("Expected the tag paths to be rolled back, got %+v", tags)
+	}
+	assertQuestionTags(t, app, created.ID, `["Cardio/ECG"]`)
+}
+
+// assertQuestionTags checks the JSON tags stored on a question
+func assertQuestionTags(t *testing.T, app *App, questionID, expected string) {
+	t.Helper()
+	q, err := app.GetQuestionByID(questionID)
+	if err != nil {
+		t.Fatalf("Failed to get question: %v", err)
+	}
+	if string(normalizedJSON(q.Tags)) != expected {
+		t.Errorf("Expected tags %s, got %s", expected, q.Tags)
+	}
+}
+
+// TestMigrateQuestionTags tests that existing JSON tags are migrated into the tag tables
+func TestMigrateQuestionTags(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "tags_migration.db")
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE questions (
+		id TEXT PRIMARY KEY,
+		question TEXT NOT NULL,
+		options JSON NOT NULL,
+		answer JSON NOT NULL,
+		explanation TEXT,
+		tags JSON,
+		image_url TEXT,
+		difficulty INTEGER,
+		source TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		t.Fatalf("Failed to create old schema: %v", err)
+	}
+	_, err = db.Exec(`INSERT INTO questions (id, question, options, answer, tags)
+		VALUES ('old-1', 'Old', '[]', '[]', '["Cardio", "Medicine/Renal"]')`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	database := &Database{db: db}
+	if err := database.migrate(); err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+
+	tags, err := database.GetTagsWithCounts()
+	if err != nil {
+		t.Fatalf("Failed to get tags: %v", err)
+	}
+	if len(tags) != 3 || findTag(tags, "Medicine/Renal") == nil || findTag(tags, "Cardio").QuestionCount != 1 {
+		t.Errorf("Unexpected migrated tags: %+v", tags)
+	}
+}
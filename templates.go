@@ -0,0 +1,511 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Template limits
+const (
+	maxTemplateVariableSteps = 100000 // Values a numeric range may take
+	templateRenderAttempts   = 20     // Draws tried before a variant fails to render
+)
+
+// templatePlaceholder matches {{name}} references in template text
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// templateVariableName is the form of variable names usable in expressions
+var templateVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// templateInstanceID matches the IDs of generated variants and captures the template ID
+var templateInstanceID = regexp.MustCompile(`^(template_\d+_\d+)_v\d+_s\d+$`)
+
+// isTemplateID reports whether an ID refers to a question template
+func isTemplateID(id string) bool {
+	return strings.HasPrefix(id, "template_") && !templateInstanceID.MatchString(id)
+}
+
+// templateIDForInstance returns the template a generated variant came from
+func templateIDForInstance(questionID string) (string, bool) {
+	match := templateInstanceID.FindStringSubmatch(questionID)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// templateInstanceIDFor returns the ID of the variant of a template version and seed
+func templateInstanceIDFor(template QuestionTemplate, seed int64) string {
+	return fmt.Sprintf("%s_v%d_s%d", template.ID, template.Version, seed)
+}
+
+// sessionTemplateSeed derives the seed of a template's variant in a session, so the
+// same session always shows the same variant
+func sessionTemplateSeed(sessionID, templateID string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(sessionID + "/" + templateID))
+	return int64(h.Sum64() & math.MaxInt64)
+}
+
+// formatTemplateNumber renders a number with the given decimals, or as few as needed
+func formatTemplateNumber(value float64, decimals *int) string {
+	if decimals != nil {
+		return strconv.FormatFloat(value, 'f', *decimals, 64)
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// drawTemplateVariables draws a value for every variable. Numeric values are returned
+// for expressions and all values as text for the stem.
+func drawTemplateVariables(template QuestionTemplate, rng *rand.Rand) (map[string]float64, map[string]string) {
+	numbers := make(map[string]float64, len(template.Variables))
+	texts := make(map[string]string, len(template.Variables))
+	for _, variable := range template.Variables {
+		if len(variable.Values) > 0 {
+			value := variable.Values[rng.Intn(len(variable.Values))]
+			texts[variable.Name] = value
+			if number, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				numbers[variable.Name] = number
+			}
+			continue
+		}
+
+		step := variable.Step
+		if step <= 0 {
+			step = 1
+		}
+		steps := int(math.Floor((variable.Max-variable.Min)/step + 1e-9))
+		value := variable.Min + float64(rng.Intn(steps+1))*step
+		value = math.Round(value*1e9) / 1e9 // Drop floating point noise from the step
+		numbers[variable.Name] = value
+		texts[variable.Name] = formatTemplateNumber(value, nil)
+	}
+	return numbers, texts
+}
+
+// renderTemplateText replaces {{name}} placeholders, leaving unknown names as they are
+func renderTemplateText(text string, values map[string]string) string {
+	return templatePlaceholder.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := templatePlaceholder.FindStringSubmatch(placeholder)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// evaluateTemplateValues computes the answer and the distinct distractors of drawn
+// variables, formatted with the template's decimals and unit
+func evaluateTemplateValues(template QuestionTemplate, numbers map[string]float64) ([]string, error) {
+	withUnit := func(value float64) string {
+		text := formatTemplateNumber(value, template.Decimals)
+		if template.Unit != "" {
+			text += " " + template.Unit
+		}
+		return text
+	}
+
+	answerValue, err := evaluateExpression(template.Answer, numbers)
+	if err != nil {
+		return nil, fmt.Errorf("answer: %v", err)
+	}
+	values := []string{withUnit(answerValue)}
+	for i, expression := range template.Distractors {
+		value, err := evaluateExpression(expression, numbers)
+		if err != nil {
+			return nil, fmt.Errorf("distractor %d: %v", i+1, err)
+		}
+		if text := withUnit(value); !containsString(values, text) {
+			values = append(values, text)
+		}
+	}
+	return values, nil
+}
+
+// renderQuestionTemplate generates the variant of a template for a seed. Options are
+// the computed answer and distinct distractors in seeded order; without distractors the
+// variant is free response and the rendered answer is the key. Variables are drawn
+// again when the expressions fail for a draw, such as on a division by zero.
+func renderQuestionTemplate(template QuestionTemplate, seed int64) (*Question, error) {
+	rng := rand.New(rand.NewSource(seed))
+	var texts map[string]string
+	var values []string
+	var err error
+	for attempt := 0; attempt < templateRenderAttempts; attempt++ {
+		var numbers map[string]float64
+		numbers, texts = drawTemplateVariables(template, rng)
+		if values, err = evaluateTemplateValues(template, numbers); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	answer := values[0]
+	texts["answer"] = answer
+
+	var options []QuestionOption
+	key := []string{answer}
+	if len(template.Distractors) > 0 {
+		rng.Shuffle(len(values), func(i, j int) { values[i], values[j] = values[j], values[i] })
+		options = make([]QuestionOption, len(values))
+		for i, value := range values {
+			options[i] = QuestionOption{ID: string(rune('a' + i)), Text: value}
+			if value == answer {
+				key = []string{options[i].ID}
+			}
+		}
+	}
+
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+	if options == nil {
+		optionsJSON = json.RawMessage(`[]`)
+	}
+	answerJSON, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	tags := template.Tags
+	if len(tags) == 0 {
+		tags = json.RawMessage(`[]`)
+	}
+
+	return &Question{
+		ID:          templateInstanceIDFor(template, seed),
+		Question:    renderTemplateText(template.Stem, texts),
+		Options:     optionsJSON,
+		Answer:      answerJSON,
+		Explanation: renderTemplateText(template.Explanation, texts),
+		Tags:        tags,
+		Difficulty:  template.Difficulty,
+		Source:      "template:" + template.Name,
+	}, nil
+}
+
+// parseTemplateNumber reads a free response to a template variant as a number,
+// allowing the template's unit after it
+func parseTemplateNumber(text, unit string) (float64, bool) {
+	text = strings.TrimSpace(text)
+	if unit != "" {
+		text = strings.TrimSpace(strings.TrimSuffix(text, unit))
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, false
+	}
+	return value, true
+}
+
+// templateAnswerTolerance is how far a free response may be from the key: half a unit
+// in the last decimal the template renders, or rounding error without decimals
+func templateAnswerTolerance(decimals *int) float64 {
+	if decimals == nil {
+		return 1e-9
+	}
+	return 0.5*math.Pow(10, -float64(*decimals)) + 1e-9
+}
+
+// isTemplateAnswerCorrect grades a free response to a variant of the template by value,
+// so "12.50" or "12.5" match a key of "12.5 mg". Answers that are not numbers are
+// compared as text.
+func isTemplateAnswerCorrect(template QuestionTemplate, q Question, userAnswer []string) bool {
+	key := parseQuestionAnswer(q.Answer)
+	if len(parseQuestionOptions(q.Options)) > 0 || len(key) != 1 || len(userAnswer) != 1 {
+		return isAnswerCorrect(q, userAnswer)
+	}
+	expected, ok := parseTemplateNumber(key[0], template.Unit)
+	if !ok {
+		return isAnswerCorrect(q, userAnswer)
+	}
+	given, ok := parseTemplateNumber(userAnswer[0], template.Unit)
+	if !ok {
+		return isAnswerCorrect(q, userAnswer)
+	}
+	return math.Abs(given-expected) <= templateAnswerTolerance(template.Decimals)
+}
+
+// validateQuestionTemplate checks a template and renders a variant to catch errors in
+// its expressions. Ranges are limited so drawing from them cannot overflow.
+func validateQuestionTemplate(template QuestionTemplate) error {
+	if strings.TrimSpace(template.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(template.Stem) == "" {
+		return fmt.Errorf("stem is required")
+	}
+	if strings.TrimSpace(template.Answer) == "" {
+		return fmt.Errorf("answer expression is required")
+	}
+	if template.Decimals != nil && (*template.Decimals < 0 || *template.Decimals > 10) {
+		return fmt.Errorf("decimals must be between 0 and 10")
+	}
+
+	seen := make(map[string]bool)
+	for _, variable := range template.Variables {
+		if !templateVariableName.MatchString(variable.Name) || variable.Name == "answer" {
+			return fmt.Errorf("invalid variable name %q", variable.Name)
+		}
+		if seen[variable.Name] {
+			return fmt.Errorf("variable %s is defined twice", variable.Name)
+		}
+		seen[variable.Name] = true
+		if len(variable.Values) > 0 {
+			continue
+		}
+		if variable.Max < variable.Min || variable.Step < 0 {
+			return fmt.Errorf("variable %s needs a range with min <= max and a positive step", variable.Name)
+		}
+		step := variable.Step
+		if step == 0 {
+			step = 1
+		}
+		if steps := (variable.Max - variable.Min) / step; math.IsNaN(steps) || steps > maxTemplateVariableSteps {
+			return fmt.Errorf("variable %s may take at most %d values", variable.Name, maxTemplateVariableSteps)
+		}
+	}
+
+	if _, err := renderQuestionTemplate(template, 1); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Question template database methods
+
+// SaveQuestionTemplate inserts or replaces a template
+func (d *Database) SaveQuestionTemplate(template *QuestionTemplate) error {
+	definition, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+	_, err = d.db.Exec(`INSERT INTO question_templates (id, name, group_id, definition, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?)
+			  ON CONFLICT(id) DO UPDATE SET name = excluded.name, group_id = excluded.group_id,
+			  definition = excluded.definition, updated_at = excluded.updated_at`,
+		template.ID, template.Name, template.GroupID, string(definition), template.CreatedAt, template.UpdatedAt)
+	return err
+}
+
+// GetQuestionTemplate returns a template by ID
+func (d *Database) GetQuestionTemplate(id string) (*QuestionTemplate, error) {
+	var definition string
+	err := d.db.QueryRow(`SELECT definition FROM question_templates WHERE id = ?`, id).Scan(&definition)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("question template %s not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var template QuestionTemplate
+	if err := json.Unmarshal([]byte(definition), &template); err != nil {
+		return nil, fmt.Errorf("failed to parse question template: %v", err)
+	}
+	return &template, nil
+}
+
+// GetQuestionTemplates returns the templates of a group, or all templates when groupID
+// is empty, ordered by name
+func (d *Database) GetQuestionTemplates(groupID string) ([]QuestionTemplate, error) {
+	rows, err := d.db.Query(`SELECT definition FROM question_templates WHERE ? = '' OR group_id = ? ORDER BY name`, groupID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := make([]QuestionTemplate, 0)
+	for rows.Next() {
+		var definition string
+		if err := rows.Scan(&definition); err != nil {
+			return nil, err
+		}
+		var template QuestionTemplate
+		if err := json.Unmarshal([]byte(definition), &template); err != nil {
+			return nil, fmt.Errorf("failed to parse question template: %v", err)
+		}
+		templates = append(templates, template)
+	}
+	return templates, rows.Err()
+}
+
+// DeleteQuestionTemplate deletes a template. Its generated variants are kept so past
+// sessions can still be reviewed.
+func (d *Database) DeleteQuestionTemplate(id string) error {
+	_, err := d.db.Exec(`DELETE FROM question_templates WHERE id = ?`, id)
+	return err
+}
+
+// SaveTemplateInstance stores a generated variant. A variant never changes once stored.
+func (d *Database) SaveTemplateInstance(templateID string, seed int64, question *Question) error {
+	data, err := json.Marshal(question)
+	if err != nil {
+		return err
+	}
+	_, err = d.db.Exec(`INSERT OR IGNORE INTO template_instances (id, template_id, seed, question, created_at) VALUES (?, ?, ?, ?, ?)`,
+		question.ID, templateID, seed, string(data), question.CreatedAt)
+	return err
+}
+
+// GetTemplateInstance returns a generated variant by ID
+func (d *Database) GetTemplateInstance(id string) (*Question, error) {
+	var data string
+	err := d.db.QueryRow(`SELECT question FROM template_instances WHERE id = ?`, id).Scan(&data)
+	if err != nil {
+		return nil, err
+	}
+	var question Question
+	if err := json.Unmarshal([]byte(data), &question); err != nil {
+		return nil, fmt.Errorf("failed to parse template instance: %v", err)
+	}
+	return &question, nil
+}
+
+// Question template methods
+
+// CreateQuestionTemplate validates and stores a new question template
+func (a *App) CreateQuestionTemplate(template QuestionTemplate) (*QuestionTemplate, error) {
+	if err := validateQuestionTemplate(template); err != nil {
+		return nil, fmt.Errorf("invalid question template: %v", err)
+	}
+	template.ID = fmt.Sprintf("template_%d_%d", time.Now().UnixNano(), rand.Int63())
+	template.Version = 1
+	template.CreatedAt = a.now().Format(time.RFC3339)
+	template.UpdatedAt = template.CreatedAt
+
+	scopes := []journalScope{{table: "question_templates", where: "id = ?", args: []interface{}{template.ID}}}
+	err := a.journaled("Create question template "+template.Name, scopes, func() error {
+		if err := a.db.SaveQuestionTemplate(&template); err != nil {
+			return fmt.Errorf("failed to save question template: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// UpdateQuestionTemplate validates and replaces a template. The version is bumped so
+// variants generated earlier keep their content.
+func (a *App) UpdateQuestionTemplate(template QuestionTemplate) error {
+	existing, err := a.db.GetQuestionTemplate(template.ID)
+	if err != nil {
+		return err
+	}
+	if err := validateQuestionTemplate(template); err != nil {
+		return fmt.Errorf("invalid question template: %v", err)
+	}
+	template.Version = existing.Version + 1
+	template.CreatedAt = existing.CreatedAt
+	template.UpdatedAt = a.now().Format(time.RFC3339)
+
+	scopes := []journalScope{{table: "question_templates", where: "id = ?", args: []interface{}{template.ID}}}
+	return a.journaled("Update question template "+template.Name, scopes, func() error {
+		if err := a.db.SaveQuestionTemplate(&template); err != nil {
+			return fmt.Errorf("failed to save question template: %v", err)
+		}
+		return nil
+	})
+}
+
+// GetQuestionTemplates returns the templates of a group, or all templates when groupID
+// is empty
+func (a *App) GetQuestionTemplates(groupID string) ([]QuestionTemplate, error) {
+	return a.db.GetQuestionTemplates(groupID)
+}
+
+// GetQuestionTemplate returns a question template by ID
+func (a *App) GetQuestionTemplate(id string) (*QuestionTemplate, error) {
+	return a.db.GetQuestionTemplate(id)
+}
+
+// DeleteQuestionTemplate deletes a question template
+func (a *App) DeleteQuestionTemplate(id string) error {
+	scopes := []journalScope{{table: "question_templates", where: "id = ?", args: []interface{}{id}}}
+	return a.journaled("Delete question template", scopes, func() error {
+		if err := a.db.DeleteQuestionTemplate(id); err != nil {
+			return fmt.Errorf("failed to delete question template: %v", err)
+		}
+		return nil
+	})
+}
+
+// GenerateQuestionFromTemplate renders and stores the variant of a template for a seed.
+// The same template version and seed always give the same variant; a seed of 0 picks a
+// random one.
+func (a *App) GenerateQuestionFromTemplate(templateID string, seed int64) (*Question, error) {
+	template, err := a.db.GetQuestionTemplate(templateID)
+	if err != nil {
+		return nil, err
+	}
+	if seed == 0 {
+		seed = rand.Int63n(math.MaxInt64-1) + 1
+	}
+	return a.instantiateTemplate(template, seed)
+}
+
+// instantiateTemplate returns the stored variant of a template for a seed, generating
+// it first when needed
+func (a *App) instantiateTemplate(template *QuestionTemplate, seed int64) (*Question, error) {
+	if seed < 0 {
+		return nil, fmt.Errorf("template seeds must not be negative")
+	}
+	if existing, err := a.db.GetTemplateInstance(templateInstanceIDFor(*template, seed)); err == nil {
+		return existing, nil
+	}
+	question, err := renderQuestionTemplate(*template, seed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %v", template.Name, err)
+	}
+	question.CreatedAt = a.now().Format(time.RFC3339)
+	question.UpdatedAt = question.CreatedAt
+	if err := a.db.SaveTemplateInstance(template.ID, seed, question); err != nil {
+		return nil, fmt.Errorf("failed to save template instance: %v", err)
+	}
+	return question, nil
+}
+
+// GetTemplateAnalytics rolls up the answers to all variants of each template
+func (a *App) GetTemplateAnalytics() ([]TemplateAnalytics, error) {
+	templates, err := a.db.GetQuestionTemplates("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question templates: %v", err)
+	}
+	attempts, err := a.db.GetQuestionAttempts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attempts: %v", err)
+	}
+	byTemplate := groupAttemptsByQuestion(attempts)
+
+	analytics := make([]TemplateAnalytics, 0, len(templates))
+	for _, template := range templates {
+		entry := TemplateAnalytics{TemplateID: template.ID, Name: template.Name}
+		variants := make(map[string]bool)
+		timeSpent := 0
+		for _, attempt := range byTemplate[template.ID] {
+			entry.Attempts++
+			if attempt.IsCorrect {
+				entry.Correct++
+			}
+			variants[attempt.VariantID] = true
+			timeSpent += attempt.TimeSpent
+		}
+		entry.Variants = len(variants)
+		if entry.Attempts > 0 {
+			entry.Accuracy = float64(entry.Correct) / float64(entry.Attempts)
+			entry.AverageTimeSpent = float64(timeSpent) / float64(entry.Attempts)
+		}
+		analytics = append(analytics, entry)
+	}
+	return analytics, nil
+}
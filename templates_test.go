@@ -0,0 +1,272 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestEvaluateExpression tests operator precedence, functions and errors
+func TestEvaluateExpression(t *testing.T) {
+	vars := map[string]float64{"weight": 70, "dose": 1.5}
+	cases := map[string]float64{
+		"weight * dose":          105,
+		"2 + 3 * 4":              14,
+		"(2 + 3) * 4":            20,
+		"-2 ^ 2":                 -4,
+		"2 ^ 3 ^ 2":              512,
+		"round(weight / 3, 2)":   23.33,
+		"max(weight, 80) % 7":    3,
+		"sqrt(16) + abs(-dose)":  5.5,
+		"floor(dose) + ceil(.2)": 2,
+	}
+	for expression, expected := range cases {
+		value, err := evaluateExpression(expression, vars)
+		if err != nil || math.Abs(value-expected) > 1e-9 {
+			t.Errorf("%s: expected %v, got %v (%v)", expression, expected, value, err)
+		}
+	}
+
+	for _, expression := range []string{"weight *", "height", "weight / 0", "foo(1)", "(1 + 2", "1 2"} {
+		if _, err := evaluateExpression(expression, vars); err == nil {
+			t.Errorf("%s: expected an error", expression)
+		}
+	}
+}
+
+// dosingTemplate returns a weight-based dosing template
+func dosingTemplate() QuestionTemplate {
+	decimals := 0
+	return QuestionTemplate{
+		Name: "Weight-based dose",
+		Stem: "A {{weight}} kg patient needs {{dose}} mg/kg of {{drug}}. How much is given?",
+		Variables: []TemplateVariable{
+			{Name: "weight", Min: 50, Max: 90, Step: 5},
+			{Name: "dose", Values: []string{"1", "2", "4"}},
+			{Name: "drug", Values: []string{"amoxicillin", "ceftriaxone"}},
+		},
+		Answer:      "weight * dose",
+		Distractors: []string{"weight + dose", "weight * dose * 10", "weight * dose / 2"},
+		Decimals:    &decimals,
+		Unit:        "mg",
+		Explanation: "{{weight}} kg x {{dose}} mg/kg = {{answer}}",
+		Tags:        json.RawMessage(`["Pharmacology"]`),
+	}
+}
+
+// TestRenderQuestionTemplate tests reproducible variants and computed answers
+func TestRenderQuestionTemplate(t *testing.T) {
+	template := dosingTemplate()
+	template.ID = "template_1_2"
+	template.Version = 1
+
+	first, err := renderQuestionTemplate(template, 42)
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+	again, _ := renderQuestionTemplate(template, 42)
+	if first.Question != again.Question || string(first.Options) != string(again.Options) {
+		t.Error("Expected the same seed to give the same variant")
+	}
+	if first.ID != "template_1_2_v1_s42" {
+		t.Errorf("Unexpected variant ID %s", first.ID)
+	}
+	if id, ok := templateIDForInstance(first.ID); !ok || id != template.ID {
+		t.Errorf("Expected the variant to map back to its template, got %q", id)
+	}
+
+	variants := make(map[string]bool)
+	for seed := int64(1); seed <= 20; seed++ {
+		q, err := renderQuestionTemplate(template, seed)
+		if err != nil {
+			t.Fatalf("Failed to render seed %d: %v", seed, err)
+		}
+		variants[q.Question] = true
+
+		// The key must point at weight * dose, read back from the stem
+		parts := strings.Fields(strings.NewReplacer("of ", "", ".", "").Replace(q.Question))
+		weight, _ := strconv.ParseFloat(parts[1], 64)
+		dose, _ := strconv.ParseFloat(parts[5], 64)
+		if drug := parts[7]; drug != "amoxicillin" && drug != "ceftriaxone" {
+			t.Errorf("Unexpected drug in %q", q.Question)
+		}
+		var options []QuestionOption
+		json.Unmarshal(q.Options, &options)
+		key := parseQuestionAnswer(q.Answer)
+		expected := strconv.FormatFloat(weight*dose, 'f', 0, 64) + " mg"
+		found := false
+		for _, option := range options {
+			if option.ID == key[0] {
+				found = option.Text == expected
+			}
+		}
+		if !found || len(options) < 2 {
+			t.Errorf("Expected key %v to be %s in %+v (%q)", key, expected, options, q.Question)
+		}
+		if !strings.HasSuffix(q.Explanation, "= "+expected) {
+			t.Errorf("Unexpected explanation %q", q.Explanation)
+		}
+	}
+	if len(variants) < 5 {
+		t.Errorf("Expected varied stems, got %d distinct", len(variants))
+	}
+
+	// Without distractors the variant is free response
+	template.Distractors = nil
+	q, _ := renderQuestionTemplate(template, 7)
+	if string(q.Options) != "[]" || !strings.HasSuffix(parseQuestionAnswer(q.Answer)[0], " mg") {
+		t.Errorf("Expected a free response variant, got %s / %s", q.Options, q.Answer)
+	}
+
+	// Ranges too fine to draw from are rejected
+	huge := dosingTemplate()
+	huge.Variables[0] = TemplateVariable{Name: "weight", Min: 0, Max: 1e12, Step: 1e-9}
+	if err := validateQuestionTemplate(huge); err == nil {
+		t.Error("Expected a range with too many values to be rejected")
+	}
+
+	// Draws that divide by zero are drawn again, and fail only when every draw does
+	ratio := QuestionTemplate{
+		Name:      "Ratio",
+		Stem:      "What is 12 / {{d}}?",
+		Variables: []TemplateVariable{{Name: "d", Min: 0, Max: 3, Step: 1}},
+		Answer:    "12 / d",
+	}
+	if err := validateQuestionTemplate(ratio); err != nil {
+		t.Fatalf("Expected a divisor that may be 0 to be accepted, got %v", err)
+	}
+	for seed := int64(1); seed <= 50; seed++ {
+		if q, err := renderQuestionTemplate(ratio, seed); err != nil || strings.Contains(q.Question, "/ 0?") {
+			t.Fatalf("Expected seed %d to render a nonzero divisor, got %v", seed, err)
+		}
+	}
+	ratio.Variables[0].Max = 0
+	if err := validateQuestionTemplate(ratio); err == nil {
+		t.Error("Expected a divisor that is always 0 to be rejected")
+	}
+}
+
+// TestTemplateSessionsAndAnalytics tests variants in sessions, grading and roll-up analytics
+func TestTemplateSessionsAndAnalytics(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+	group := createTestGroup(t, app, "Dosing", "")
+
+	invalid := dosingTemplate()
+	invalid.Answer = "weight * height"
+	if _, err := app.CreateQuestionTemplate(invalid); err == nil {
+		t.Error("Expected a template with an unknown variable to be rejected")
+	}
+
+	definition := dosingTemplate()
+	definition.GroupID = group.ID
+	template, err := app.CreateQuestionTemplate(definition)
+	if err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+
+	session, err := app.StartSession(group.ID, PracticeModeTest, nil)
+	if err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	if len(session.Questions) != 1 || session.Questions[0].Answer != nil {
+		t.Fatalf("Expected one concealed variant, got %+v", session.Questions)
+	}
+	variantID := session.Questions[0].ID
+	if id, _ := templateIDForInstance(variantID); id != template.ID {
+		t.Fatalf("Expected a variant of the template, got %s", variantID)
+	}
+
+	variant, err := app.GetQuestionByID(variantID)
+	if err != nil {
+		t.Fatalf("Failed to load variant: %v", err)
+	}
+	app.SubmitSessionAnswer(session.ID, variantID, parseQuestionAnswer(variant.Answer), 12, "")
+	session, err = app.FinishSession(session.ID)
+	if err != nil || session.CorrectCount != 1 {
+		t.Fatalf("Expected the variant to be graded correct, got %+v (%v)", session, err)
+	}
+
+	// A second session answers another variant wrong
+	session, _ = app.StartSession("", PracticeModePractice, []string{template.ID})
+	if session.QuestionIDs[0] == variantID {
+		t.Error("Expected a new session to get its own variant")
+	}
+	feedback, err := app.SubmitSessionAnswer(session.ID, session.QuestionIDs[0], []string{"none"}, 8, "")
+	if err != nil || feedback.IsCorrect {
+		t.Fatalf("Expected a wrong answer, got %+v (%v)", feedback, err)
+	}
+	app.FinishSession(session.ID)
+	app.waitForCalibration()
+
+	if wrong, _ := app.GetWrongQuestions(); len(wrong) != 0 {
+		t.Errorf("Expected variants to stay off the wrong question list, got %+v", wrong)
+	}
+
+	// Updating the template keeps the stored variants unchanged
+	updated := *template
+	updated.Stem = "Changed {{weight}}"
+	if err := app.UpdateQuestionTemplate(updated); err != nil {
+		t.Fatalf("Failed to update template: %v", err)
+	}
+	if stored, _ := app.GetQuestionByID(variantID); stored.Question != variant.Question {
+		t.Error("Expected stored variants to keep their content")
+	}
+
+	analytics, err := app.GetTemplateAnalytics()
+	if err != nil {
+		t.Fatalf("Failed to get template analytics: %v", err)
+	}
+	if len(analytics) != 1 || analytics[0].Attempts != 2 || analytics[0].Correct != 1 ||
+		analytics[0].Variants != 2 || analytics[0].AverageTimeSpent != 10 {
+		t.Errorf("Unexpected template analytics: %+v", analytics)
+	}
+}
+
+// TestFreeResponseTemplateGrading tests that free responses to template variants are
+// graded by value within the rendered decimals
+func TestFreeResponseTemplateGrading(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	decimals := 1
+	template, err := app.CreateQuestionTemplate(QuestionTemplate{
+		Name:      "Quarter dose",
+		Stem:      "A quarter of {{dose}} mg is how much?",
+		Variables: []TemplateVariable{{Name: "dose", Values: []string{"50"}}},
+		Answer:    "dose / 4",
+		Decimals:  &decimals,
+		Unit:      "mg",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+
+	for _, tc := range []struct {
+		answer  string
+		correct bool
+	}{
+		{"12.5", true},
+		{"12.50", true},
+		{" 12.5 mg", true},
+		{"12.46", true},
+		{"12.6", false},
+		{"13", false},
+		{"twelve", false},
+	} {
+		session, _ := app.StartSession("", PracticeModePractice, []string{template.ID})
+		feedback, err := app.SubmitSessionAnswer(session.ID, session.QuestionIDs[0], []string{tc.answer}, 5, "")
+		if err != nil {
+			t.Fatalf("Failed to submit %q: %v", tc.answer, err)
+		}
+		if feedback.IsCorrect != tc.correct {
+			t.Errorf("Expected %q to be graded correct=%v", tc.answer, tc.correct)
+		}
+	}
+}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// parseExamTime parses a timestamp stored in a timed exam
+func parseExamTime(value *string) time.Time {
+	if value == nil {
+		return time.Time{}
+	}
+	t, _ := time.Parse(time.RFC3339Nano, *value)
+	return t
+}
+
+// formatExamTime formats a timestamp for storage in a timed exam
+func formatExamTime(t time.Time) *string {
+	value := t.Format(time.RFC3339Nano)
+	return &value
+}
+
+// usedSeconds returns the answering time spent in completed sections
+func (e *TimedExam) usedSeconds() int {
+	used := 0
+	for _, section := range e.Sections {
+		if section.StartedAt != nil && section.CompletedAt != nil {
+			used += int(parseExamTime(section.CompletedAt).Sub(parseExamTime(section.StartedAt)).Seconds())
+		}
+	}
+	return used
+}
+
+// startSection opens a section and sets its deadline from the section limit and the
+// answering time left in the exam
+func (e *TimedExam) startSection(index int, at time.Time) {
+	e.Status = TimedExamInProgress
+	e.CurrentSection = index
+	e.BreakEndsAt = nil
+
+	section := &e.Sections[index]
+	section.StartedAt = formatExamTime(at)
+	var deadline time.Time
+	if section.TimeLimit > 0 {
+		deadline = at.Add(time.Duration(section.TimeLimit) * time.Second)
+	}
+	if e.TimeLimit > 0 {
+		remaining := e.TimeLimit - e.usedSeconds()
+		if remaining < 0 {
+			remaining = 0
+		}
+		overall := at.Add(time.Duration(remaining) * time.Second)
+		if deadline.IsZero() || overall.Before(deadline) {
+			deadline = overall
+		}
+	}
+	if !deadline.IsZero() {
+		section.Deadline = formatExamTime(deadline)
+	}
+}
+
+// completeSection locks the current section and moves on to a break, the next section
+// or the end of the exam
+func (e *TimedExam) completeSection(at time.Time, timedOut bool) {
+	section := &e.Sections[e.CurrentSection]
+	section.Locked = true
+	section.TimedOut = timedOut
+	section.CompletedAt = formatExamTime(at)
+
+	next := e.CurrentSection + 1
+	if next >= len(e.Sections) || (e.TimeLimit > 0 && e.usedSeconds() >= e.TimeLimit) {
+		e.finish(at)
+		return
+	}
+	for _, b := range e.Breaks {
+		if b.AfterSection == e.CurrentSection && b.Duration > 0 {
+			e.Status = TimedExamOnBreak
+			e.BreakEndsAt = formatExamTime(at.Add(time.Duration(b.Duration) * time.Second))
+			return
+		}
+	}
+	e.startSection(next, at)
+}
+
+// finish ends the exam and locks every section
+func (e *TimedExam) finish(at time.Time) {
+	e.Status = TimedExamCompleted
+	e.FinishedAt = formatExamTime(at)
+	e.BreakEndsAt = nil
+	for i := range e.Sections {
+		e.Sections[i].Locked = true
+	}
+}
+
+// advance applies every deadline and break end that has passed by now
+func (e *TimedExam) advance(now time.Time) {
+	for {
+		switch e.Status {
+		case TimedExamInProgress:
+			section := e.Sections[e.CurrentSection]
+			if section.Deadline == nil || now.Before(parseExamTime(section.Deadline)) {
+				return
+			}
+			e.completeSection(parseExamTime(section.Deadline), true)
+		case TimedExamOnBreak:
+			breakEnd := parseExamTime(e.BreakEndsAt)
+			if now.Before(breakEnd) {
+				return
+			}
+			e.startSection(e.CurrentSection+1, breakEnd)
+		default:
+			return
+		}
+	}
+}
+
+// sectionTimings summarizes the sections of a finished exam
+func (e *TimedExam) sectionTimings() []SectionTiming {
+	timings := make([]SectionTiming, 0, len(e.Sections))
+	for _, section := range e.Sections {
+		timing := SectionTiming{
+			Name:      section.Name,
+			TimeLimit: section.TimeLimit,
+			Questions: len(section.QuestionIDs),
+			Answered:  len(section.Answers),
+			TimedOut:  section.TimedOut,
+		}
+		if section.StartedAt != nil {
+			timing.StartedAt = parseExamTime(section.StartedAt).Format(time.RFC3339)
+		}
+		if section.CompletedAt != nil {
+			timing.CompletedAt = parseExamTime(section.CompletedAt).Format(time.RFC3339)
+			if section.StartedAt != nil {
+				timing.Duration = int(parseExamTime(section.CompletedAt).Sub(parseExamTime(section.StartedAt)).Seconds())
+			}
+		}
+		for _, answer := range section.Answers {
+			if answer.IsCorrect {
+				timing.Correct++
+			}
+		}
+		timings = append(timings, timing)
+	}
+	return timings
+}
+
+// Timed exam database methods
+
+// SaveTimedExam stores the state of a timed exam
+func (d *Database) SaveTimedExam(exam *TimedExam, at time.Time) error {
+	stored := *exam
+	stored.CurrentQuestions = nil
+	stateJSON, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	now := at.Format(time.RFC3339)
+	_, err = d.db.Exec(`INSERT INTO timed_exams (id, status, state, created_at, updated_at) VALUES (?, ?, ?, ?, ?)
+			  ON CONFLICT(id) DO UPDATE SET status = excluded.status, state = excluded.state, updated_at = excluded.updated_at`,
+		exam.ID, exam.Status, string(stateJSON), now, now)
+	return err
+}
+
+// GetTimedExam loads the state of a timed exam
+func (d *Database) GetTimedExam(examID string) (*TimedExam, error) {
+	var stateJSON string
+	err := d.db.QueryRow(`SELECT state FROM timed_exams WHERE id = ?`, examID).Scan(&stateJSON)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("timed exam %s not found", examID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var exam TimedExam
+	if err := json.Unmarshal([]byte(stateJSON), &exam); err != nil {
+		return nil, fmt.Errorf("failed to parse timed exam: %v", err)
+	}
+	return &exam, nil
+}
+
+// Timed exam methods
+
+// StartTimedExam generates an exam from a blueprint and starts its first section.
+// Deadlines are kept by the backend clock, not the client.
+func (a *App) StartTimedExam(blueprintID string) (*TimedExam, error) {
+	blueprint, err := a.db.GetExamBlueprint(blueprintID)
+	if err != nil {
+		return nil, err
+	}
+	generated, err := a.GenerateExamFromBlueprint(blueprintID)
+	if err != nil {
+		return nil, err
+	}
+	if generated.TotalQuestions == 0 {
+		return nil, fmt.Errorf("the question bank has no questions for this blueprint")
+	}
+
+	now := a.now()
+	exam := &TimedExam{
+		ID:          fmt.Sprintf("exam_%d_%d", now.UnixNano(), rand.Int63()),
+		BlueprintID: blueprint.ID,
+		Name:        blueprint.Name,
+		TimeLimit:   generated.TimeLimit,
+		Breaks:      blueprint.Breaks,
+		StartedAt:   now.Format(time.RFC3339),
+		Shortfalls:  generated.Shortfalls,
+	}
+	for _, section := range generated.Sections {
+		ids := make([]string, 0, len(section.Questions))
+		for _, q := range section.Questions {
+			ids = append(ids, q.ID)
+		}
+		exam.Sections = append(exam.Sections, TimedExamSection{
+			Name:        section.Name,
+			TimeLimit:   section.TimeLimit,
+			QuestionIDs: ids,
+			Answers:     []QuestionRecord{},
+		})
+	}
+	exam.startSection(0, now)
+
+	return a.storeTimedExam(exam, "")
+}
+
+// GetTimedExam returns the current state of a timed exam after applying any deadlines
+// that have passed
+func (a *App) GetTimedExam(examID string) (*TimedExam, error) {
+	return a.updateTimedExam(examID, func(exam *TimedExam, now time.Time) error { return nil })
+}
+
+// SubmitTimedExamAnswer records an answer in the running section. Answers after the
+// section or exam deadline, during breaks or to locked sections are rejected. An answer
+// may be changed until its section is locked.
+func (a *App) SubmitTimedExamAnswer(examID, questionID string, userAnswer []string) (*TimedExam, error) {
+	return a.updateTimedExam(examID, func(exam *TimedExam, now time.Time) error {
+		switch exam.Status {
+		case TimedExamCompleted:
+			return fmt.Errorf("the exam has finished, the answer was not accepted")
+		case TimedExamOnBreak:
+			return fmt.Errorf("the exam is on a break, the answer was not accepted")
+		}
+
+		section := &exam.Sections[exam.CurrentSection]
+		inSection := false
+		for _, id := range section.QuestionIDs {
+			inSection = inSection || id == questionID
+		}
+		if !inSection {
+			for _, other := range exam.Sections {
+				for _, id := range other.QuestionIDs {
+					if id == questionID && other.Locked {
+						return fmt.Errorf("section %s is locked, the answer was not accepted", other.Name)
+					}
+				}
+			}
+			return fmt.Errorf("question %s is not part of the current section", questionID)
+		}
+
+		question, err := a.db.GetQuestionByID(questionID)
+		if err != nil {
+			return fmt.Errorf("failed to get question: %v", err)
+		}
+
+		// Time is measured from the section start or the previous answer
+		since := parseExamTime(section.StartedAt)
+		if section.LastAnswerAt != nil {
+			since = parseExamTime(section.LastAnswerAt)
+		}
+		spent := int(now.Sub(since).Seconds())
+		section.LastAnswerAt = formatExamTime(now)
+
+		record := QuestionRecord{
+			QuestionID: questionID,
+			UserAnswer: userAnswer,
+			IsCorrect:  a.gradeAnswer(*question, userAnswer),
+			TimeSpent:  spent,
+		}
+		for i := range section.Answers {
+			if section.Answers[i].QuestionID == questionID {
+				record.TimeSpent += section.Answers[i].TimeSpent
+				section.Answers[i] = record
+				return nil
+			}
+		}
+		section.Answers = append(section.Answers, record)
+		return nil
+	})
+}
+
+// CompleteTimedExamSection locks the running section before its deadline and moves on
+// to the scheduled break, the next section or the end of the exam
+func (a *App) CompleteTimedExamSection(examID string) (*TimedExam, error) {
+	return a.updateTimedExam(examID, func(exam *TimedExam, now time.Time) error {
+		if exam.Status != TimedExamInProgress {
+			return fmt.Errorf("no section is running")
+		}
+		exam.completeSection(now, false)
+		return nil
+	})
+}
+
+// ResumeTimedExam ends the current break early and starts the next section
+func (a *App) ResumeTimedExam(examID string) (*TimedExam, error) {
+	return a.updateTimedExam(examID, func(exam *TimedExam, now time.Time) error {
+		if exam.Status != TimedExamOnBreak {
+			return fmt.Errorf("the exam is not on a break")
+		}
+		exam.startSection(exam.CurrentSection+1, now)
+		return nil
+	})
+}
+
+// FinishTimedExam ends the exam early, locking every section
+func (a *App) FinishTimedExam(examID string) (*TimedExam, error) {
+	return a.updateTimedExam(examID, func(exam *TimedExam, now time.Time) error {
+		switch exam.Status {
+		case TimedExamCompleted:
+			return nil
+		case TimedExamInProgress:
+			section := &exam.Sections[exam.CurrentSection]
+			section.CompletedAt = formatExamTime(now)
+		}
+		exam.finish(now)
+		return nil
+	})
+}
+
+// updateTimedExam loads an exam, applies passed deadlines and the given change, and
+// stores the result. Deadline transitions are kept even when the change is rejected.
+func (a *App) updateTimedExam(examID string, change func(exam *TimedExam, now time.Time) error) (*TimedExam, error) {
+	a.timedExamMu.Lock()
+	defer a.timedExamMu.Unlock()
+
+	exam, err := a.db.GetTimedExam(examID)
+	if err != nil {
+		return nil, err
+	}
+	previousStatus := exam.Status
+
+	now := a.now()
+	exam.advance(now)
+	changeErr := change(exam, now)
+
+	stored, err := a.storeTimedExam(exam, previousStatus)
+	if err != nil {
+		return nil, err
+	}
+	if changeErr != nil {
+		return stored, changeErr
+	}
+	return stored, nil
+}
+
+// storeTimedExam saves an exam, records it as a practice session when it has just
+// finished and fills in the questions of the running section. Answers, explanations
+// and results stay hidden until the exam has finished.
+func (a *App) storeTimedExam(exam *TimedExam, previousStatus string) (*TimedExam, error) {
+	if exam.Status == TimedExamCompleted && previousStatus != TimedExamCompleted {
+		if err := a.saveTimedExamSession(exam); err != nil {
+			return nil, err
+		}
+	}
+	if err := a.withoutJournal(func() error { return a.db.SaveTimedExam(exam, a.now()) }); err != nil {
+		return nil, fmt.Errorf("failed to save timed exam: %v", err)
+	}
+
+	exam.CurrentQuestions = []SessionQuestion{}
+	if exam.Status == TimedExamInProgress {
+		for _, id := range exam.Sections[exam.CurrentSection].QuestionIDs {
+			q, err := a.db.GetQuestionByID(id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get question %s: %v", id, err)
+			}
+			exam.CurrentQuestions = append(exam.CurrentQuestions, sanitizeQuestion(*q))
+		}
+	}
+	if exam.Status != TimedExamCompleted {
+		for i := range exam.Sections {
+			for j := range exam.Sections[i].Answers {
+				exam.Sections[i].Answers[j].IsCorrect = false
+			}
+		}
+	}
+	return exam, nil
+}
+
+// saveTimedExamSession records a finished exam as a practice session with per-section
+// timing. Unanswered questions count as incorrect.
+func (a *App) saveTimedExamSession(exam *TimedExam) error {
+	var records []QuestionRecord
+	correct := 0
+	for _, section := range exam.Sections {
+		answers := make(map[string]QuestionRecord, len(section.Answers))
+		for _, answer := range section.Answers {
+			answers[answer.QuestionID] = answer
+		}
+		for _, id := range section.QuestionIDs {
+			record, ok := answers[id]
+			if !ok {
+				record = QuestionRecord{QuestionID: id, UserAnswer: []string{}}
+			}
+			if record.IsCorrect {
+				correct++
+			}
+			records = append(records, record)
+		}
+	}
+
+	timings := exam.sectionTimings()
+	duration := 0
+	for _, timing := range timings {
+		duration += timing.Duration
+	}
+	endTime := parseExamTime(exam.FinishedAt).Format(time.RFC3339)
+
+	session := &PracticeSession{
+		ID:             exam.ID,
+		Mode:           PracticeModeTimedExam,
+		StartTime:      exam.StartedAt,
+		EndTime:        &endTime,
+		Duration:       duration,
+		TotalQuestions: len(records),
+		CorrectCount:   correct,
+		Sections:       timings,
+		CreatedAt:      a.now().Format(time.RFC3339),
+	}
+	return a.saveCompletedSession(session, records)
+}
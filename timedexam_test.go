@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestTimedExamDeadlinesAndBreaks tests deadline enforcement, breaks, locking and the saved session
+func TestTimedExamDeadlinesAndBreaks(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	start := time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC)
+	now := start
+	app.clock = func() time.Time { return now }
+
+	data := []map[string]interface{}{
+		{"question": "Cardio", "options": []string{"A", "B"}, "answer": []string{"a"}, "tags": []string{"Cardiology"}},
+		{"question": "Stats", "options": []string{"A", "B"}, "answer": []string{"a"}, "tags": []string{"Statistics"}},
+	}
+	app.ImportQuestions(data, "")
+
+	blueprint, err := app.CreateExamBlueprint(ExamBlueprint{
+		Name: "Board simulation",
+		Sections: []BlueprintSection{
+			{Name: "Block 1", Tag: "Cardiology", Count: 1, TimeLimit: 60},
+			{Name: "Block 2", Tag: "Statistics", Count: 1, TimeLimit: 60},
+		},
+		Breaks: []ExamBreak{{AfterSection: 0, Duration: 30}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create blueprint: %v", err)
+	}
+
+	exam, err := app.StartTimedExam(blueprint.ID)
+	if err != nil {
+		t.Fatalf("Failed to start timed exam: %v", err)
+	}
+	if exam.TimeLimit != 120 || len(exam.CurrentQuestions) != 1 || exam.CurrentQuestions[0].Question != "Cardio" {
+		t.Fatalf("Unexpected exam: %+v", exam)
+	}
+	cardio := exam.CurrentQuestions[0].ID
+
+	now = start.Add(10 * time.Second)
+	if _, err := app.SubmitTimedExamAnswer(exam.ID, cardio, []string{"b"}); err != nil {
+		t.Fatalf("Failed to submit answer: %v", err)
+	}
+	now = start.Add(20 * time.Second)
+	if _, err := app.SubmitTimedExamAnswer(exam.ID, cardio, []string{"a"}); err != nil {
+		t.Fatalf("Failed to change answer: %v", err)
+	}
+
+	// After the section deadline late answers are rejected and the break starts
+	now = start.Add(70 * time.Second)
+	if _, err := app.SubmitTimedExamAnswer(exam.ID, cardio, []string{"b"}); err == nil {
+		t.Error("Expected a late answer to be rejected")
+	}
+	exam, _ = app.GetTimedExam(exam.ID)
+	if exam.Status != TimedExamOnBreak || !exam.Sections[0].Locked || !exam.Sections[0].TimedOut {
+		t.Fatalf("Expected a break after the timed out section, got %+v", exam)
+	}
+
+	// The next section starts when the break ends, and the first section stays locked
+	now = start.Add(95 * time.Second)
+	exam, _ = app.GetTimedExam(exam.ID)
+	if exam.Status != TimedExamInProgress || exam.CurrentSection != 1 {
+		t.Fatalf("Expected the second section to run, got %+v", exam)
+	}
+	if *exam.Sections[1].StartedAt != start.Add(90*time.Second).Format(time.RFC3339Nano) {
+		t.Errorf("Expected the section to start at the end of the break, got %s", *exam.Sections[1].StartedAt)
+	}
+	if _, err := app.SubmitTimedExamAnswer(exam.ID, cardio, []string{"a"}); err == nil {
+		t.Error("Expected answers to a locked section to be rejected")
+	}
+	stats := exam.CurrentQuestions[0].ID
+	now = start.Add(100 * time.Second)
+	if _, err := app.SubmitTimedExamAnswer(exam.ID, stats, []string{"a"}); err != nil {
+		t.Fatalf("Failed to submit answer: %v", err)
+	}
+
+	exam, err = app.CompleteTimedExamSection(exam.ID)
+	if err != nil || exam.Status != TimedExamCompleted {
+		t.Fatalf("Expected the exam to finish, got %+v (%v)", exam, err)
+	}
+	app.waitForCalibration()
+
+	sessions, _ := db.GetPracticeSessions()
+	if len(sessions) != 1 {
+		t.Fatalf("Expected one saved session, got %d", len(sessions))
+	}
+	session := sessions[0]
+	if session.Mode != PracticeModeTimedExam || session.CorrectCount != 2 || session.Duration != 70 {
+		t.Errorf("Unexpected session: %+v", session)
+	}
+	if len(session.Sections) != 2 || session.Sections[0].Duration != 60 || !session.Sections[0].TimedOut ||
+		session.Sections[1].Duration != 10 || session.Sections[1].TimedOut {
+		t.Errorf("Unexpected section timing: %+v", session.Sections)
+	}
+	if _, err := app.SubmitTimedExamAnswer(exam.ID, stats, []string{"a"}); err == nil {
+		t.Error("Expected answers after the exam to be rejected")
+	}
+}
+
+// TestTimedExamConcurrentUpdates tests that concurrent answers are all kept and an exam
+// finished by several requests at once is saved once
+func TestTimedExamConcurrentUpdates(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	start := time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC)
+	var clockMu sync.Mutex
+	now := start
+	app.clock = func() time.Time {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		return now
+	}
+
+	var data []map[string]interface{}
+	for i := 0; i < 8; i++ {
+		data = append(data, map[string]interface{}{"question": fmt.Sprintf("Q%d", i), "options": []string{"A", "B"}, "answer": []string{"a"}})
+	}
+	app.ImportQuestions(data, "")
+	blueprint, _ := app.CreateExamBlueprint(ExamBlueprint{
+		Name:     "Block",
+		Sections: []BlueprintSection{{Name: "Block 1", Count: 8, TimeLimit: 60}},
+	})
+	exam, err := app.StartTimedExam(blueprint.ID)
+	if err != nil {
+		t.Fatalf("Failed to start timed exam: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, q := range exam.CurrentQuestions {
+		wg.Add(1)
+		go func(questionID string) {
+			defer wg.Done()
+			app.SubmitTimedExamAnswer(exam.ID, questionID, []string{"a"})
+		}(q.ID)
+	}
+	wg.Wait()
+	stored, _ := db.GetTimedExam(exam.ID)
+	if len(stored.Sections[0].Answers) != 8 {
+		t.Errorf("Expected every concurrent answer to be kept, got %d", len(stored.Sections[0].Answers))
+	}
+
+	clockMu.Lock()
+	now = start.Add(2 * time.Minute)
+	clockMu.Unlock()
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			app.GetTimedExam(exam.ID)
+		}()
+	}
+	wg.Wait()
+	app.waitForCalibration()
+	if sessions, _ := app.GetPracticeSessions(); len(sessions) != 1 {
+		t.Errorf("Expected the finished exam to be saved once, got %d sessions", len(sessions))
+	}
+}
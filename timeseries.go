@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// heatmapDays is the default span of the activity heatmap
+const heatmapDays = 365
+
+// defaultMovingAverage returns the default moving average window for a granularity
+func defaultMovingAverage(granularity string) int {
+	switch granularity {
+	case GranularityWeek:
+		return 4
+	case GranularityMonth:
+		return 3
+	default:
+		return 7
+	}
+}
+
+// periodStart returns the first day of the period containing t
+func periodStart(t time.Time, granularity string) time.Time {
+	year, month, day := t.Date()
+	switch granularity {
+	case GranularityWeek:
+		return weekStart(time.Date(year, month, day, 0, 0, 0, 0, t.Location()))
+	case GranularityMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// nextPeriod returns the start of the period after the one starting at t
+func nextPeriod(t time.Time, granularity string) time.Time {
+	switch granularity {
+	case GranularityWeek:
+		return t.AddDate(0, 0, 7)
+	case GranularityMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// analyticsRange resolves the inclusive date range of a query in local time
+func analyticsRange(from, to string, attempts []questionAttempt, now time.Time) (time.Time, time.Time, error) {
+	end := now.In(time.Local)
+	if to != "" {
+		parsed, err := time.ParseInLocation(calendarDateLayout, to, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end date %q: %v", to, err)
+		}
+		end = parsed
+	}
+	start := end
+	if from != "" {
+		parsed, err := time.ParseInLocation(calendarDateLayout, from, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start date %q: %v", from, err)
+		}
+		start = parsed
+	} else {
+		for _, attempt := range attempts {
+			if answered := attempt.AnsweredAt.In(time.Local); answered.Before(start) {
+				start = answered
+			}
+		}
+	}
+	start, end = periodStart(start, GranularityDay), periodStart(end, GranularityDay)
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("start date %s is after end date %s", from, to)
+	}
+	return start, end, nil
+}
+
+// filterAnalyticsAttempts returns the answers matching a query's group, tag and mode
+func (a *App) filterAnalyticsAttempts(attempts []questionAttempt, query TimeSeriesQuery) ([]questionAttempt, error) {
+	var inGroup map[string]bool
+	if query.GroupID != "" {
+		questions, err := a.groupQuestions(query.GroupID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get group questions: %v", err)
+		}
+		inGroup = make(map[string]bool, len(questions))
+		for _, q := range questions {
+			inGroup[q.ID] = true
+		}
+	}
+
+	var tagged map[string]bool
+	if tag := normalizeTagPath(query.Tag); tag != "" {
+		questions, err := a.db.GetQuestions()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get questions: %v", err)
+		}
+		tagged = make(map[string]bool)
+		for _, q := range questions {
+			for _, path := range parseQuestionTags(q.Tags) {
+				if isTagPathWithin(path, tag) {
+					tagged[q.ID] = true
+					break
+				}
+			}
+		}
+	}
+
+	filtered := make([]questionAttempt, 0, len(attempts))
+	for _, attempt := range attempts {
+		if inGroup != nil && !inGroup[attempt.QuestionID] {
+			continue
+		}
+		if tagged != nil && !tagged[attempt.QuestionID] {
+			continue
+		}
+		if query.Mode != "" && attempt.Mode != query.Mode {
+			continue
+		}
+		filtered = append(filtered, attempt)
+	}
+	return filtered, nil
+}
+
+// Time series methods
+
+// GetLearningTimeSeries aggregates accuracy, question volume and study time per day,
+// week or month with trailing moving averages. Periods without answers are included
+// so charts stay continuous.
+func (a *App) GetLearningTimeSeries(query TimeSeriesQuery) (*TimeSeries, error) {
+	granularity := query.Granularity
+	switch granularity {
+	case "":
+		granularity = GranularityDay
+	case GranularityDay, GranularityWeek, GranularityMonth:
+	default:
+		return nil, fmt.Errorf("unknown granularity %q", query.Granularity)
+	}
+	window := query.MovingAverage
+	if window <= 0 {
+		window = defaultMovingAverage(granularity)
+	}
+
+	attempts, err := a.db.GetQuestionAttempts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question attempts: %v", err)
+	}
+	attempts, err = a.filterAnalyticsAttempts(attempts, query)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := analyticsRange(query.From, query.To, attempts, a.now())
+	if err != nil {
+		return nil, err
+	}
+
+	type periodTotals struct {
+		sessions  map[string]bool
+		questions int
+		correct   int
+		seconds   int
+	}
+	totals := make(map[string]*periodTotals)
+	last := end.AddDate(0, 0, 1)
+	for _, attempt := range attempts {
+		answered := attempt.AnsweredAt.In(time.Local)
+		if answered.Before(start) || !answered.Before(last) {
+			continue
+		}
+		key := periodStart(answered, granularity).Format(calendarDateLayout)
+		t := totals[key]
+		if t == nil {
+			t = &periodTotals{sessions: make(map[string]bool)}
+			totals[key] = t
+		}
+		t.sessions[attempt.SessionID] = true
+		t.questions++
+		if attempt.IsCorrect {
+			t.correct++
+		}
+		t.seconds += attempt.TimeSpent
+	}
+
+	series := &TimeSeries{Granularity: granularity, MovingAverage: window, Points: []TimeSeriesPoint{}}
+	for period := periodStart(start, granularity); !period.After(end); period = nextPeriod(period, granularity) {
+		point := TimeSeriesPoint{Period: period.Format(calendarDateLayout)}
+		if t := totals[point.Period]; t != nil {
+			point.Sessions = len(t.sessions)
+			point.Questions = t.questions
+			point.Correct = t.correct
+			point.Accuracy = float64(t.correct) / float64(t.questions) * 100
+			point.StudyMinutes = float64(t.seconds) / 60
+		}
+		series.Points = append(series.Points, point)
+	}
+
+	// Trailing averages; accuracy is weighted by the answers in the window
+	for i := range series.Points {
+		from := i - window + 1
+		if from < 0 {
+			from = 0
+		}
+		questions, correct, minutes := 0, 0, 0.0
+		for _, p := range series.Points[from : i+1] {
+			questions += p.Questions
+			correct += p.Correct
+			minutes += p.StudyMinutes
+		}
+		periods := float64(i - from + 1)
+		point := &series.Points[i]
+		point.QuestionsAverage = float64(questions) / periods
+		point.StudyMinutesAverage = minutes / periods
+		if questions > 0 {
+			point.AccuracyAverage = float64(correct) / float64(questions) * 100
+		}
+	}
+	return series, nil
+}
+
+// GetActivityHeatmap returns the questions answered and time studied on each day of the
+// query's range, the last year by default. Active days are leveled 1 to 4 relative to
+// the busiest day.
+func (a *App) GetActivityHeatmap(query TimeSeriesQuery) ([]HeatmapDay, error) {
+	if query.From == "" {
+		query.From = a.now().In(time.Local).AddDate(0, 0, 1-heatmapDays).Format(calendarDateLayout)
+	}
+	query.Granularity = GranularityDay
+	query.MovingAverage = 1
+	series, err := a.GetLearningTimeSeries(query)
+	if err != nil {
+		return nil, err
+	}
+
+	busiest := 0
+	for _, p := range series.Points {
+		if p.Questions > busiest {
+			busiest = p.Questions
+		}
+	}
+
+	days := make([]HeatmapDay, 0, len(series.Points))
+	for _, p := range series.Points {
+		day := HeatmapDay{Date: p.Period, Questions: p.Questions, StudyMinutes: p.StudyMinutes}
+		if p.Questions > 0 {
+			day.Level = int(math.Ceil(4 * float64(p.Questions) / float64(busiest)))
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
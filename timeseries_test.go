@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+// TestLearningTimeSeries tests bucketing, gap filling, filters, moving averages and the heatmap
+func TestLearningTimeSeries(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	app := &App{db: db}
+
+	app.clock = func() time.Time { return time.Date(2025, 6, 11, 18, 0, 0, 0, time.Local) }
+
+	data := []map[string]interface{}{
+		{"question": "C1", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Cardio/Valves"}},
+		{"question": "R1", "options": []string{}, "answer": []string{"a"}, "tags": []string{"Renal"}},
+	}
+	app.ImportQuestions(data, "")
+	questions, _ := app.GetQuestions()
+	ids := make(map[string]string)
+	for _, q := range questions {
+		ids[q.Question] = q.ID
+	}
+
+	addSession := func(id, mode string, at time.Time, records []QuestionRecord) {
+		details, _ := json.Marshal(records)
+		stamp := at.Format(time.RFC3339)
+		db.CreatePracticeSession(&PracticeSession{ID: id, Mode: mode, StartTime: stamp, TotalQuestions: len(records), Details: details, CreatedAt: stamp})
+	}
+	cardio := func(correct bool) QuestionRecord {
+		return QuestionRecord{QuestionID: ids["C1"], IsCorrect: correct, TimeSpent: 30}
+	}
+	renal := func(correct bool) QuestionRecord {
+		return QuestionRecord{QuestionID: ids["R1"], IsCorrect: correct, TimeSpent: 30}
+	}
+	addSession("s1", "practice", time.Date(2025, 6, 2, 9, 0, 0, 0, time.Local), []QuestionRecord{cardio(true), cardio(true), cardio(true), cardio(false)})
+	addSession("s2", "test", time.Date(2025, 6, 4, 9, 0, 0, 0, time.Local), []QuestionRecord{renal(true), renal(false)})
+	addSession("s3", "practice", time.Date(2025, 6, 10, 9, 0, 0, 0, time.Local), []QuestionRecord{cardio(true), renal(true)})
+
+	daily, err := app.GetLearningTimeSeries(TimeSeriesQuery{})
+	if err != nil {
+		t.Fatalf("Failed to get time series: %v", err)
+	}
+	if len(daily.Points) != 10 || daily.Points[0].Period != "2025-06-02" || daily.Points[9].Period != "2025-06-11" {
+		t.Fatalf("Expected a gap-free daily series from the first answer to today, got %+v", daily.Points)
+	}
+	if p := daily.Points[0]; p.Questions != 4 || p.Accuracy != 75 || p.StudyMinutes != 2 || p.Sessions != 1 {
+		t.Errorf("Unexpected first day: %+v", p)
+	}
+	if p := daily.Points[2]; p.QuestionsAverage != 2 || math.Abs(p.AccuracyAverage-200.0/3) > 1e-9 {
+		t.Errorf("Unexpected moving averages: %+v", p)
+	}
+
+	weekly, err := app.GetLearningTimeSeries(TimeSeriesQuery{Granularity: GranularityWeek, MovingAverage: 2})
+	if err != nil {
+		t.Fatalf("Failed to get weekly series: %v", err)
+	}
+	if len(weekly.Points) != 2 || weekly.Points[0].Questions != 6 || weekly.Points[1].QuestionsAverage != 4 || weekly.Points[1].Sessions != 1 {
+		t.Errorf("Unexpected weekly series: %+v", weekly.Points)
+	}
+
+	monthly, _ := app.GetLearningTimeSeries(TimeSeriesQuery{Granularity: GranularityMonth, From: "2025-05-20"})
+	if len(monthly.Points) != 2 || monthly.Points[0].Questions != 0 || monthly.Points[1].Questions != 8 {
+		t.Errorf("Unexpected monthly series: %+v", monthly.Points)
+	}
+
+	byTag, _ := app.GetLearningTimeSeries(TimeSeriesQuery{Granularity: GranularityMonth, Tag: "Cardio"})
+	byMode, _ := app.GetLearningTimeSeries(TimeSeriesQuery{Granularity: GranularityMonth, Mode: "test"})
+	if byTag.Points[0].Questions != 5 || byMode.Points[0].Questions != 2 {
+		t.Errorf("Expected the tag and mode filters to apply, got %+v and %+v", byTag.Points, byMode.Points)
+	}
+
+	if _, err := app.GetLearningTimeSeries(TimeSeriesQuery{Granularity: "hour"}); err == nil {
+		t.Error("Expected an unknown granularity to be rejected")
+	}
+	if _, err := app.GetLearningTimeSeries(TimeSeriesQuery{From: "2025-06-12", To: "2025-06-01"}); err == nil {
+		t.Error("Expected an inverted range to be rejected")
+	}
+
+	heatmap, err := app.GetActivityHeatmap(TimeSeriesQuery{})
+	if err != nil {
+		t.Fatalf("Failed to get heatmap: %v", err)
+	}
+	if len(heatmap) != heatmapDays || heatmap[len(heatmap)-1].Date != "2025-06-11" {
+		t.Fatalf("Expected a year of days ending today, got %d", len(heatmap))
+	}
+	levels := make(map[string]int)
+	for _, day := range heatmap {
+		levels[day.Date] = day.Level
+	}
+	if levels["2025-06-02"] != 4 || levels["2025-06-04"] != 2 || levels["2025-06-03"] != 0 {
+		t.Errorf("Unexpected heatmap levels: %v %v %v", levels["2025-06-02"], levels["2025-06-04"], levels["2025-06-03"])
+	}
+}